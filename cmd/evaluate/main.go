@@ -0,0 +1,80 @@
+// Command evaluate reports the precision and recall of the repository and
+// paper filters and model categories, and the NDCG of every ranking strategy,
+// over a labeled fixture set:
+//
+//	go run ./cmd/evaluate
+//	go run ./cmd/evaluate -json > internal/evaluate/testdata/baseline.json
+//	go run ./cmd/evaluate -baseline internal/evaluate/testdata/baseline.json
+//
+// With -baseline it exits with status 1 when a metric dropped below the
+// baseline, so it can run in CI.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/gerryyang2025/llm-news/internal/evaluate"
+)
+
+func main() {
+	fixtures := flag.String("fixtures", "internal/evaluate/testdata/labeled.json", "labeled fixture file")
+	k := flag.Int("k", evaluate.DefaultK, "number of results NDCG is computed over")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	baseline := flag.String("baseline", "", "report to compare with; exits with status 1 on regressions")
+	tolerance := flag.Float64("tolerance", 0.001, "drop below the baseline allowed before a metric counts as a regression")
+	verbose := flag.Bool("v", false, "show the scrapers' logs")
+	flag.Parse()
+
+	if !*verbose {
+		// 过滤器会为每个仓库输出日志，默认关闭
+		log.SetOutput(io.Discard)
+	}
+
+	f, err := evaluate.Load(*fixtures)
+	if err != nil {
+		fatal(err)
+	}
+	report := evaluate.Run(f, *k)
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fatal(err)
+		}
+	} else if err := report.WriteText(os.Stdout); err != nil {
+		fatal(err)
+	}
+
+	if *baseline == "" {
+		return
+	}
+	base, err := evaluate.LoadReport(*baseline)
+	if err != nil {
+		fatal(err)
+	}
+	if base.K != report.K {
+		fatal(fmt.Errorf("baseline was computed with -k %d", base.K))
+	}
+	regressions := evaluate.Compare(*base, report, *tolerance)
+	if len(regressions) == 0 {
+		fmt.Fprintln(os.Stderr, "No regressions against", *baseline)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%d regressions against %s:\n", len(regressions), *baseline)
+	for _, r := range regressions {
+		fmt.Fprintln(os.Stderr, "  "+r)
+	}
+	os.Exit(1)
+}
+
+// fatal reports an error without the log prefix, which may be disabled
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(2)
+}
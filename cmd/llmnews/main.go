@@ -0,0 +1,285 @@
+// Command llmnews runs the scrapers once and prints the results, for cron
+// jobs, notebooks and debugging a single source without the web server:
+//
+//	llmnews scrape repos [-source trending|paperswithcode] [-format json]
+//	llmnews scrape papers [-source NAME] [-format csv]
+//	llmnews scrape articles [-source NAME] [-format markdown]
+//	llmnews export [-kind repos|papers] [-format ndjson] [-input FILE] [-data DIR]
+//	llmnews score [-kind repos|papers] [-rank NAME] [-input FILE] [-limit N]
+//	llmnews sources
+//
+// Results go to stdout and logs to stderr. export and score read the latest
+// snapshot of the data directory unless -input names a JSON file written by
+// scrape ("-" reads stdin).
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/export"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/papers"
+	"github.com/gerryyang2025/llm-news/internal/ranking"
+	"github.com/gerryyang2025/llm-news/internal/scrapers"
+	"github.com/gerryyang2025/llm-news/internal/store"
+)
+
+// Kinds of data
+const (
+	kindRepos    = "repos"
+	kindPapers   = "papers"
+	kindArticles = "articles"
+)
+
+// errUsage marks errors caused by the command line rather than a source
+var errUsage = errors.New("usage")
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "scrape":
+		err = runScrape(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "score":
+		err = runScore(os.Args[2:])
+	case "sources":
+		err = runSources()
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+	default:
+		err = fmt.Errorf("%w: unknown command %q", errUsage, os.Args[1])
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if errors.Is(err, errUsage) {
+			usage()
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `Usage:
+  llmnews scrape repos|papers|articles [-source NAME] [-format FORMAT]
+  llmnews export [-kind repos|papers] [-format FORMAT] [-input FILE] [-data DIR]
+  llmnews score [-kind repos|papers] [-rank NAME] [-input FILE] [-data DIR] [-limit N]
+  llmnews sources
+
+Formats: `+strings.Join(export.Formats(), ", ")+`
+Rankers: `+strings.Join(ranking.Names(), ", ")+`
+`)
+}
+
+// parseArgs parses flags given before or after the positional argument, so
+// both "scrape repos -format csv" and "scrape -format csv repos" work
+func parseArgs(fs *flag.FlagSet, args []string) (string, error) {
+	var target string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		target, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if target == "" && fs.NArg() > 0 {
+		target = fs.Arg(0)
+	} else if fs.NArg() > 0 {
+		return "", fmt.Errorf("%w: unexpected argument %q", errUsage, fs.Arg(0))
+	}
+	return target, nil
+}
+
+func runScrape(args []string) error {
+	fs := flag.NewFlagSet("scrape", flag.ContinueOnError)
+	source := fs.String("source", "", "fetch a single source, see \"llmnews sources\"")
+	format := fs.String("format", export.JSON, "output format")
+	target, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	switch target {
+	case kindRepos:
+		var repos []models.Repository
+		switch strings.ToLower(*source) {
+		case "", "trending":
+			repos, err = scrapers.ScrapeGithubTrending()
+		case "paperswithcode":
+			repos, err = scrapers.ScrapePapersWithCode()
+		default:
+			return fmt.Errorf("%w: unknown repository source %q, expected trending or paperswithcode", errUsage, *source)
+		}
+		if err != nil {
+			return err
+		}
+		return export.WriteRepos(os.Stdout, *format, repos)
+
+	case kindPapers, kindArticles:
+		var list []models.Paper
+		switch {
+		case *source != "":
+			list, err = papers.FetchSource(*source)
+		case target == kindPapers:
+			list, err = papers.FetchTopPapers()
+		default:
+			list, err = papers.FetchOtherBlogPosts()
+		}
+		if err != nil {
+			return err
+		}
+		return export.WritePapers(os.Stdout, *format, list)
+	}
+	return fmt.Errorf("%w: scrape expects repos, papers or articles", errUsage)
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	kind := fs.String("kind", kindRepos, "data to export: repos or papers")
+	format := fs.String("format", export.JSON, "output format")
+	input := fs.String("input", "", "JSON file written by scrape, \"-\" for stdin; defaults to the latest snapshot")
+	dataDir := fs.String("data", store.DataDir(), "data directory holding the snapshots")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	repos, list, err := loadData(*kind, *input, *dataDir)
+	if err != nil {
+		return err
+	}
+	if *kind == kindRepos {
+		return export.WriteRepos(os.Stdout, *format, repos)
+	}
+	return export.WritePapers(os.Stdout, *format, list)
+}
+
+func runScore(args []string) error {
+	fs := flag.NewFlagSet("score", flag.ContinueOnError)
+	kind := fs.String("kind", kindRepos, "data to score: repos or papers")
+	rank := fs.String("rank", ranking.Weighted, "repository ranking strategy")
+	input := fs.String("input", "", "JSON file written by scrape, \"-\" for stdin; defaults to the latest snapshot")
+	dataDir := fs.String("data", store.DataDir(), "data directory holding the snapshots")
+	limit := fs.Int("limit", 20, "number of results shown, 0 for all")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	repos, list, err := loadData(*kind, *input, *dataDir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+	if *kind == kindRepos {
+		ranker, ok := ranking.Get(*rank)
+		if !ok {
+			return fmt.Errorf("%w: unknown ranker %q, expected one of %s", errUsage, *rank, strings.Join(ranking.Names(), ", "))
+		}
+		ranked := ranking.Rank(ranker, repos, now)
+		fmt.Fprintln(tw, "#\tSCORE\tREPOSITORY\tBREAKDOWN")
+		for i, repo := range ranked {
+			if *limit > 0 && i >= *limit {
+				break
+			}
+			breakdown := ""
+			if ranker.Name() == ranking.Weighted {
+				// 只有加权策略有分项
+				breakdown = formatBreakdown(ranking.WeightedBreakdown(repo, now))
+			}
+			fmt.Fprintf(tw, "%d\t%.3f\t%s\t%s\n", i+1, ranker.Score(repo, now), repo.Name, breakdown)
+		}
+		return tw.Flush()
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return papers.RankingScore(list[i], now) > papers.RankingScore(list[j], now)
+	})
+	fmt.Fprintln(tw, "#\tSCORE\tSOURCE\tTITLE\tBREAKDOWN")
+	for i, p := range list {
+		if *limit > 0 && i >= *limit {
+			break
+		}
+		fmt.Fprintf(tw, "%d\t%.3f\t%s\t%s\t%s\n", i+1, papers.RankingScore(p, now), p.Source, p.Title, formatBreakdown(papers.ScoreBreakdown(p, now)))
+	}
+	return tw.Flush()
+}
+
+func runSources() error {
+	fmt.Println("repos:    trending, paperswithcode")
+	fmt.Println("papers:   " + strings.Join(papers.SourceNames(), ", "))
+	return nil
+}
+
+// loadData reads repositories or papers from input, or from the latest
+// snapshot in dataDir when input is empty
+func loadData(kind, input, dataDir string) ([]models.Repository, []models.Paper, error) {
+	if kind != kindRepos && kind != kindPapers {
+		return nil, nil, fmt.Errorf("%w: -kind must be repos or papers", errUsage)
+	}
+
+	if input == "" {
+		st, err := store.Open(dataDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		snap, ok := st.Latest()
+		if !ok {
+			return nil, nil, fmt.Errorf("no snapshots in %s, run the server or pass -input", dataDir)
+		}
+		return snap.Repos, snap.Papers, nil
+	}
+
+	var data []byte
+	var err error
+	if input == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", input, err)
+	}
+
+	var repos []models.Repository
+	var list []models.Paper
+	if kind == kindRepos {
+		err = json.Unmarshal(data, &repos)
+	} else {
+		err = json.Unmarshal(data, &list)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s as JSON %s: %w", input, kind, err)
+	}
+	return repos, list, nil
+}
+
+// formatBreakdown renders score components as sorted key=value pairs
+func formatBreakdown(breakdown map[string]float64) string {
+	keys := make([]string, 0, len(breakdown))
+	for k := range breakdown {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%.3f", k, breakdown[k])
+	}
+	return strings.Join(parts, " ")
+}
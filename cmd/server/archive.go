@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// archiveDateLayout is the date format of /archive/:date
+const archiveDateLayout = "2006-01-02"
+
+// archiveInfo describes the snapshot shown on an archive page
+type archiveInfo struct {
+	Date     string
+	Previous string // 前一个有快照的日期，没有时为空
+	Next     string
+}
+
+// requestSnapshot returns the data a list endpoint serves: the snapshot at
+// ?at= or, without it, the current data. historical reports whether a
+// snapshot was requested. On failure the error response has been written
+// and ok is false.
+func requestSnapshot(c *gin.Context) (snap store.Snapshot, historical bool, ok bool) {
+	at := c.Query("at")
+	if at == "" {
+		return store.Snapshot{TakenAt: lastUpdated, Repos: githubRepos, Papers: researchPapers}, false, true
+	}
+	snap, status, err := snapshotAt(at)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return store.Snapshot{}, true, false
+	}
+	c.Header("X-Snapshot-At", snap.TakenAt.UTC().Format(time.RFC3339))
+	return snap, true, true
+}
+
+// snapshotAt returns the latest snapshot taken at or before the time at. A
+// date alone means the end of that day. On failure status is the HTTP status
+// matching the error.
+func snapshotAt(at string) (store.Snapshot, int, error) {
+	t, err := parseEndTimeParam("at", at)
+	if err != nil {
+		return store.Snapshot{}, http.StatusBadRequest, err
+	}
+	if snapshotStore == nil {
+		return store.Snapshot{}, http.StatusServiceUnavailable, errors.New("snapshot history is not available")
+	}
+	snap, found := snapshotStore.At(t)
+	if !found {
+		return store.Snapshot{}, http.StatusNotFound, fmt.Errorf("no snapshot at or before %s", t.UTC().Format(time.RFC3339))
+	}
+	return snap, http.StatusOK, nil
+}
+
+// archivePageHandler renders the home page from the last snapshot of a day
+func archivePageHandler(c *gin.Context) {
+	date, err := time.Parse(archiveDateLayout, c.Param("date"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid date, expected YYYY-MM-DD")
+		return
+	}
+	if snapshotStore == nil {
+		c.String(http.StatusServiceUnavailable, "Snapshot history is not available")
+		return
+	}
+	snap, ok := snapshotStore.OnDay(date)
+	if !ok {
+		c.String(http.StatusNotFound, "No snapshot archived for %s", date.Format(archiveDateLayout))
+		return
+	}
+
+	info := &archiveInfo{Date: date.Format(archiveDateLayout)}
+	for _, day := range snapshotStore.Days() {
+		if day.Before(date) {
+			info.Previous = day.Format(archiveDateLayout)
+		} else if day.After(date) && info.Next == "" {
+			info.Next = day.Format(archiveDateLayout)
+		}
+	}
+	renderIndex(c, snap.Repos, snap.Papers, snap.TakenAt, info)
+}
+
+// compactSnapshots applies the snapshot retention rules
+func compactSnapshots() {
+	if snapshotStore == nil {
+		return
+	}
+	removed, err := snapshotStore.Compact(store.RetentionFromEnv(), time.Now())
+	if err != nil {
+		log.Printf("Warning: Failed to compact snapshots: %v", err)
+	}
+	if removed > 0 {
+		log.Printf("Removed %d snapshots, %d remain", removed, snapshotStore.Len())
+	}
+}
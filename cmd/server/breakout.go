@@ -0,0 +1,56 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/trend"
+	"github.com/gin-gonic/gin"
+)
+
+// breakoutHistoryDays is how much snapshot history the detector compares with
+const breakoutHistoryDays = 14
+
+// detectBreakouts flags the repositories whose star velocity jumped compared
+// with their own snapshot history or with the other trending repositories.
+// It returns the ones that were not breaking out in the current data.
+func detectBreakouts(repos []models.Repository) []models.Repository {
+	history := make(map[string][]trend.Sample)
+	now := time.Now()
+	if snapshotStore != nil {
+		for _, snap := range snapshotStore.Between(now.AddDate(0, 0, -breakoutHistoryDays), now) {
+			for _, r := range snap.Repos {
+				id := models.RepoID(r.Name)
+				history[id] = append(history[id], trend.Sample{Time: snap.TakenAt, Stars: r.Stars})
+			}
+		}
+	}
+
+	if n := trend.Detect(repos, history, now, trend.DefaultConfig()); n > 0 {
+		log.Printf("Detected %d repositories breaking out", n)
+	}
+
+	previous := make(map[string]bool)
+	for _, repo := range trend.Breakouts(githubRepos) {
+		previous[models.RepoID(repo.Name)] = true
+	}
+	var started []models.Repository
+	for _, repo := range trend.Breakouts(repos) {
+		if !previous[models.RepoID(repo.Name)] {
+			started = append(started, repo)
+		}
+	}
+	return started
+}
+
+// breakoutsHandler returns the repositories breaking out, fastest first
+func breakoutsHandler(c *gin.Context) {
+	breakouts := trend.Breakouts(githubRepos)
+	if breakouts == nil {
+		breakouts = []models.Repository{}
+	}
+	lang, ok := apiLang(c)
+	c.JSON(http.StatusOK, localizeRepos(breakouts, lang, ok))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/changes"
+	"github.com/gerryyang2025/llm-news/internal/ranking"
+	"github.com/gerryyang2025/llm-news/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// lastVisitCookie holds the time of the previous visit of the home page
+const lastVisitCookie = "last_visit"
+
+// Headers of the in-place refresh of the live page. The refresh is not a new
+// visit: it keeps the cookie and compares with the visit the page was
+// rendered for, which it sends back in lastVisitHeader.
+const (
+	liveRefreshHeader = "X-Live-Refresh"
+	lastVisitHeader   = "X-Last-Visit"
+)
+
+// defaultChangesLimit caps the rank movements and star deltas of /api/changes
+const defaultChangesLimit = 10
+
+// changesSince compares the current data with the snapshot taken at or
+// before since. Without since the snapshot before the latest one is used,
+// i.e. the changes of the last refresh. ok is false when there is nothing
+// to compare with.
+func changesSince(since time.Time, ranker ranking.Ranker, limit int) (changes.Changes, bool) {
+	if snapshotStore == nil {
+		return changes.Changes{}, false
+	}
+	var base store.Snapshot
+	var ok bool
+	if since.IsZero() {
+		if latest, found := snapshotStore.Latest(); found {
+			base, ok = snapshotStore.At(latest.TakenAt.Add(-time.Nanosecond))
+		}
+	} else {
+		base, ok = snapshotStore.At(since)
+		if !ok {
+			// 早于第一个快照时，与最早的快照比较
+			if inRange := snapshotStore.Between(since, time.Now()); len(inRange) > 0 {
+				base, ok = inRange[0], true
+			}
+		}
+	}
+	if !ok {
+		return changes.Changes{}, false
+	}
+
+	// 两边使用同一策略排序，排名变化才有意义
+	base.Repos = ranking.Rank(ranker, base.Repos, base.TakenAt)
+	current := store.Snapshot{
+		TakenAt: lastUpdated,
+		Repos:   ranking.Rank(ranker, githubRepos, time.Now()),
+		Papers:  researchPapers,
+	}
+	return changes.Diff(base, current, limit), true
+}
+
+// changesHandler serves GET /api/changes?since=&limit=&rank=&lang=
+func changesHandler(c *gin.Context) {
+	ranker, err := requestRanker(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := defaultChangesLimit
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+			return
+		}
+	}
+
+	var since time.Time
+	if value := c.Query("since"); value != "" {
+		if since, err = parseTimeParam("since", value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if snapshotStore == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "snapshot history is not available"})
+		return
+	}
+	diff, ok := changesSince(since, ranker, limit)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no earlier snapshot to compare with"})
+		return
+	}
+
+	lang, localized := apiLang(c)
+	diff.Entered = localizeRepos(diff.Entered, lang, localized)
+	diff.Left = localizeRepos(diff.Left, lang, localized)
+	diff.NewPapers = localizePapers(diff.NewPapers, lang, localized)
+
+	c.Header("X-Rank-Strategy", ranker.Name())
+	c.JSON(http.StatusOK, diff)
+}
+
+// visitChanges returns the IDs of the repositories and papers added since
+// the previous visit recorded in the lastVisitCookie and records this one.
+// An in-place refresh compares with lastVisitHeader instead and records
+// nothing. previous is the time compared with, empty on first visits, which
+// get no badges.
+func visitChanges(c *gin.Context, ranker ranking.Ranker) (newRepos, newPapers map[string]bool, previous string) {
+	if c.GetHeader(liveRefreshHeader) != "" {
+		previous = c.GetHeader(lastVisitHeader)
+	} else {
+		previous, _ = c.Cookie(lastVisitCookie)
+		c.SetCookie(lastVisitCookie, time.Now().UTC().Format(time.RFC3339), 365*24*3600, "/", "", false, false)
+	}
+
+	since, err := time.Parse(time.RFC3339, previous)
+	if err != nil {
+		return nil, nil, ""
+	}
+	diff, ok := changesSince(since, ranker, 0)
+	if !ok {
+		return nil, nil, previous
+	}
+	newRepos = make(map[string]bool, len(diff.Entered))
+	for _, repo := range diff.Entered {
+		newRepos[repo.ID] = true
+	}
+	newPapers = make(map[string]bool, len(diff.NewPapers))
+	for _, paper := range diff.NewPapers {
+		newPapers[paper.ID] = true
+	}
+	return newRepos, newPapers, previous
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/graph"
+	"github.com/gerryyang2025/llm-news/internal/i18n"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/papers"
+	"github.com/gin-gonic/gin"
+)
+
+// findRepo looks up a collected repository by ID. Repositories that are
+// only known from the link graph carry just their name and URL.
+func findRepo(id string) (models.Repository, bool) {
+	id = models.RepoID(id)
+	for _, r := range githubRepos {
+		if models.RepoID(r.Name) == id {
+			r.ID = id
+			return r, true
+		}
+	}
+	if n, ok := currentGraph().Node(graph.RepoNodeID(id)); ok {
+		return models.Repository{ID: id, Name: n.Label, URL: n.URL}, true
+	}
+	return models.Repository{}, false
+}
+
+// findPaper looks up a collected paper by ID, falling back to the papers
+// referenced in the link graph
+func findPaper(id string) (models.Paper, bool) {
+	for _, p := range researchPapers {
+		if models.PaperID(p.URL, p.Title) == id {
+			p.ID = id
+			return p, true
+		}
+	}
+	if n, ok := currentGraph().Node(graph.PaperNodeID(id)); ok {
+		return models.Paper{ID: id, Title: n.Label, URL: n.URL}, true
+	}
+	return models.Paper{}, false
+}
+
+// repoDetail collects everything shown about a repository
+func repoDetail(repo models.Repository) gin.H {
+	linked := graphPapers(currentGraph().PapersForRepo(repo.ID))
+	return gin.H{
+		"repo":         repo,
+		"papers":       linked,
+		"star_history": starHistory(repo.ID, defaultHistoryDays),
+		"score": gin.H{
+			"total":     repo.RelevanceScore,
+			"breakdown": repo.ScoreBreakdown,
+		},
+	}
+}
+
+// paperDetail collects everything shown about a paper
+func paperDetail(paper models.Paper) gin.H {
+	breakdown := papers.ScoreBreakdown(paper, time.Now())
+	total := 0.0
+	for _, v := range breakdown {
+		total += v
+	}
+	return gin.H{
+		"paper": paper,
+		"repos": graphRepos(currentGraph().ReposForPaper(paper.ID)),
+		"score": gin.H{
+			"total":     total,
+			"breakdown": breakdown,
+		},
+	}
+}
+
+// graphPapers assigns IDs to papers returned by the link graph
+func graphPapers(list []models.Paper) []models.Paper {
+	result := make([]models.Paper, len(list))
+	copy(result, list)
+	models.AssignPaperIDs(result)
+	return result
+}
+
+// graphRepos assigns IDs to repositories returned by the link graph
+func graphRepos(list []models.Repository) []models.Repository {
+	result := make([]models.Repository, len(list))
+	copy(result, list)
+	models.AssignRepoIDs(result)
+	return result
+}
+
+// repoDetailHandler serves /api/repos/:owner/:name
+func repoDetailHandler(c *gin.Context) {
+	repo, ok := findRepo(c.Param("owner") + "/" + c.Param("name"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "repository not found"})
+		return
+	}
+	lang, ok := apiLang(c)
+	detail := repoDetail(localizeRepos([]models.Repository{repo}, lang, ok)[0])
+	detail["papers"] = localizePapers(detail["papers"].([]models.Paper), lang, ok)
+	c.JSON(http.StatusOK, detail)
+}
+
+// paperDetailHandler serves /api/papers/:id
+func paperDetailHandler(c *gin.Context) {
+	paper, ok := findPaper(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "paper not found"})
+		return
+	}
+	lang, ok := apiLang(c)
+	detail := paperDetail(localizePapers([]models.Paper{paper}, lang, ok)[0])
+	detail["repos"] = localizeRepos(detail["repos"].([]models.Repository), lang, ok)
+	c.JSON(http.StatusOK, detail)
+}
+
+// repoPageHandler renders the detail page of a repository
+func repoPageHandler(c *gin.Context) {
+	lang := requestLang(c)
+	repo, ok := findRepo(c.Param("owner") + "/" + c.Param("name"))
+	if !ok {
+		c.String(http.StatusNotFound, i18n.T(lang, "detail.repo_not_found"))
+		return
+	}
+	detail := repoDetail(i18n.LocalizeRepos([]models.Repository{repo}, lang)[0])
+	detail["papers"] = i18n.LocalizePapers(detail["papers"].([]models.Paper), lang)
+	c.HTML(http.StatusOK, "repo.html", detailPageData(lang, repo.Name, detail))
+}
+
+// paperPageHandler renders the detail page of a paper
+func paperPageHandler(c *gin.Context) {
+	lang := requestLang(c)
+	paper, ok := findPaper(c.Param("id"))
+	if !ok {
+		c.String(http.StatusNotFound, i18n.T(lang, "detail.paper_not_found"))
+		return
+	}
+	detail := paperDetail(i18n.LocalizePapers([]models.Paper{paper}, lang)[0])
+	detail["repos"] = i18n.LocalizeRepos(detail["repos"].([]models.Repository), lang)
+	c.HTML(http.StatusOK, "paper.html", detailPageData(lang, paper.Title, detail))
+}
+
+// detailPageData adds the fields shared by all pages to a detail
+func detailPageData(lang i18n.Lang, heading string, detail gin.H) gin.H {
+	detail["title"] = heading + " - LLM News"
+	detail["lang"] = lang
+	detail["switchLang"] = otherLang(lang)
+	detail["lastUpdated"] = lastUpdated.Format("2006-01-02 15:04:05")
+	detail["now"] = time.Now()
+	return detail
+}
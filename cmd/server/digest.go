@@ -0,0 +1,75 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/digest"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	digestArchive *digest.Archive
+	digestMailer  *digest.Mailer // 未配置SMTP时为nil，只归档不发送
+)
+
+// runDigest builds the digest for the period ending now, archives it and
+// mails it to the configured recipients
+func runDigest(period digest.Period) {
+	if snapshotStore == nil || digestArchive == nil {
+		return
+	}
+
+	d, err := digest.FromStore(snapshotStore, period, time.Now().UTC())
+	if err != nil {
+		log.Printf("Error: Failed to build %s digest: %v", period, err)
+		return
+	}
+	// 配置了公网地址时在摘要中嵌入Star历史迷你图
+	d.ChartBaseURL = strings.TrimSpace(os.Getenv("LLM_NEWS_PUBLIC_URL"))
+
+	if err := digestArchive.Save(d); err != nil {
+		log.Printf("Error: Failed to archive %s digest: %v", period, err)
+	}
+
+	if digestMailer == nil {
+		return
+	}
+	if err := digestMailer.Send(d); err != nil {
+		log.Printf("Error: Failed to mail %s digest: %v", period, err)
+		return
+	}
+	log.Printf("Sent %s digest with %d new repositories and %d papers", period, len(d.NewRepos), len(d.TopPapers))
+}
+
+// digestHandler serves an archived digest, e.g. /digest/2026-10-18?period=weekly.
+// Append &format=markdown to get the Markdown version.
+func digestHandler(c *gin.Context) {
+	if digestArchive == nil {
+		c.String(http.StatusServiceUnavailable, "Digest archive is not available")
+		return
+	}
+
+	period := digest.ParsePeriod(c.Query("period"))
+	date := c.Param("date")
+
+	if c.Query("format") == "markdown" {
+		data, err := digestArchive.LoadMarkdown(date, period)
+		if err != nil {
+			c.String(http.StatusNotFound, "No %s digest archived for %s", period, date)
+			return
+		}
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", data)
+		return
+	}
+
+	data, err := digestArchive.LoadHTML(date, period)
+	if err != nil {
+		c.String(http.StatusNotFound, "No %s digest archived for %s", period, date)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", data)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/export"
+	"github.com/gerryyang2025/llm-news/internal/i18n"
+	"github.com/gerryyang2025/llm-news/internal/ranking"
+	"github.com/gerryyang2025/llm-news/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// exportColumns precede the repository or paper columns of every export row
+var exportColumns = []export.Column{
+	{Name: "snapshot_at", Type: export.Time},
+	{Name: "rank", Type: export.Int}, // 在该快照中的排名，从1开始
+}
+
+// timeLayouts are the accepted formats of time query parameters, read as UTC
+var timeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"}
+
+// parseTimeParam parses a time query parameter
+func parseTimeParam(name, value string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s must be an RFC 3339 time or a YYYY-MM-DD date", name)
+}
+
+// parseEndTimeParam parses a time query parameter that ends a range. A date
+// without a time stands for the end of that UTC day, so that ?to=2026-09-01
+// and ?at=2026-09-01 include the snapshots taken on September 1.
+func parseEndTimeParam(name, value string) (time.Time, error) {
+	if date, err := time.Parse(archiveDateLayout, value); err == nil {
+		return date.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return parseTimeParam(name, value)
+}
+
+// exportRequest holds the parsed query of an export
+type exportRequest struct {
+	format    string
+	ranker    ranking.Ranker
+	lang      i18n.Lang
+	localized bool
+	current   bool // 导出当前数据而不是历史快照
+	snapshots []store.Snapshot
+}
+
+// parseExportRequest reads ?format=, the list endpoint filters ?rank= and
+// ?lang=, and the ?from= and ?to= range. Without a range the current data is
+// exported as a single snapshot.
+func parseExportRequest(c *gin.Context) (*exportRequest, error) {
+	req := &exportRequest{format: c.DefaultQuery("format", export.CSV)}
+	switch req.format {
+	case export.CSV, export.NDJSON, export.Parquet:
+	default:
+		return nil, fmt.Errorf("format must be one of csv, ndjson, parquet")
+	}
+
+	ranker, err := requestRanker(c)
+	if err != nil {
+		return nil, err
+	}
+	req.ranker = ranker
+	req.lang, req.localized = apiLang(c)
+
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" && to == "" {
+		req.current = true
+		req.snapshots = []store.Snapshot{{TakenAt: lastUpdated, Repos: githubRepos, Papers: researchPapers}}
+		return req, nil
+	}
+	if snapshotStore == nil {
+		return nil, fmt.Errorf("snapshot history is not available")
+	}
+	start, end := time.Time{}, time.Now()
+	if from != "" {
+		if start, err = parseTimeParam("from", from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if end, err = parseEndTimeParam("to", to); err != nil {
+			return nil, err
+		}
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("to must not be before from")
+	}
+	req.snapshots = snapshotStore.Between(start, end)
+	return req, nil
+}
+
+// exportReposHandler streams the repositories of the current data or of every
+// snapshot in the requested range, one batch of rows per snapshot
+func exportReposHandler(c *gin.Context) {
+	streamExport(c, "repos", export.RepoColumns(), func(req *exportRequest, snap store.Snapshot) [][]interface{} {
+		// 当前数据与/api/repos的排序一致，历史快照按当时的时间排序
+		at := snap.TakenAt
+		if req.current {
+			at = time.Now()
+		}
+		repos := ranking.Rank(req.ranker, snap.Repos, at)
+		repos = localizeRepos(repos, req.lang, req.localized)
+		rows := make([][]interface{}, len(repos))
+		for i, repo := range repos {
+			rows[i] = append([]interface{}{snap.TakenAt, i + 1}, export.RepoRow(repo)...)
+		}
+		return rows
+	})
+}
+
+// exportPapersHandler streams papers like exportReposHandler. Papers keep
+// the relevance order they were stored in.
+func exportPapersHandler(c *gin.Context) {
+	streamExport(c, "papers", export.PaperColumns(), func(req *exportRequest, snap store.Snapshot) [][]interface{} {
+		list := localizePapers(snap.Papers, req.lang, req.localized)
+		rows := make([][]interface{}, len(list))
+		for i, p := range list {
+			rows[i] = append([]interface{}{snap.TakenAt, i + 1}, export.PaperRow(p)...)
+		}
+		return rows
+	})
+}
+
+// streamExport writes the rows of every snapshot and flushes after each, so
+// long ranges start downloading at once and are never held in memory as a
+// whole
+func streamExport(c *gin.Context, kind string, columns []export.Column, rowsOf func(*exportRequest, store.Snapshot) [][]interface{}) {
+	req, err := parseExportRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	extension := req.format
+	if extension == export.NDJSON {
+		extension = "jsonl"
+	}
+	c.Header("Content-Type", export.ContentType(req.format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="llm-news-%s-%s.%s"`, kind, time.Now().UTC().Format("20060102"), extension))
+	c.Header("X-Rank-Strategy", req.ranker.Name())
+	c.Status(http.StatusOK)
+
+	rw, err := export.NewRowWriter(c.Writer, req.format, append(append([]export.Column(nil), exportColumns...), columns...))
+	if err != nil {
+		log.Printf("Warning: Failed to start %s export: %v", kind, err)
+		return
+	}
+	for _, snap := range req.snapshots {
+		if err := rw.WriteRows(rowsOf(req, snap)); err != nil {
+			// 响应已经开始，只能中断输出
+			log.Printf("Warning: Failed to export %s: %v", kind, err)
+			return
+		}
+		c.Writer.Flush()
+	}
+	if err := rw.Close(); err != nil {
+		log.Printf("Warning: Failed to finish %s export: %v", kind, err)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gerryyang2025/llm-news/internal/feed"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// requestBaseURL reconstructs the public base URL of the server from the request
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, c.Request.Host)
+}
+
+// writeFeed renders the feed in the format selected by ?format=rss|atom
+func writeFeed(c *gin.Context, f feed.Feed) {
+	format := feed.ParseFormat(c.Query("format"))
+	f.SelfLink = requestBaseURL(c) + c.Request.URL.RequestURI()
+
+	var buf bytes.Buffer
+	if err := feed.Render(&buf, f, format); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// repoFeedHandler serves the trending repositories as RSS/Atom
+func repoFeedHandler(c *gin.Context) {
+	repos := sortRepositories(mergeRepositories(githubRepos, []models.Repository{}))
+
+	writeFeed(c, feed.Feed{
+		Title:       "LLM News - Trending AI/ML Repositories",
+		Link:        requestBaseURL(c) + "/#repositories",
+		Description: "Trending AI/ML open source repositories collected by LLM News",
+		Updated:     lastUpdated,
+		Items:       feed.RepositoryItems(repos),
+	})
+}
+
+// paperFeedHandler serves the research papers and articles as RSS/Atom
+func paperFeedHandler(c *gin.Context) {
+	writeFeed(c, feed.Feed{
+		Title:       "LLM News - AI Research Articles",
+		Link:        requestBaseURL(c) + "/#papers",
+		Description: "Latest AI research papers and technical articles collected by LLM News",
+		Updated:     lastUpdated,
+		Items:       feed.PaperItems(researchPapers),
+	})
+}
+
+// modelFeedHandler serves repositories and papers of one model category,
+// e.g. /feed/model/claude.xml
+func modelFeedHandler(c *gin.Context) {
+	model := strings.TrimSuffix(c.Param("model"), ".xml")
+	if model == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Model name is required"})
+		return
+	}
+
+	var repos []models.Repository
+	for _, repo := range sortRepositories(mergeRepositories(githubRepos, []models.Repository{})) {
+		for _, category := range repo.GetModelCategories() {
+			if strings.EqualFold(category, model) {
+				repos = append(repos, repo)
+				break
+			}
+		}
+	}
+
+	var matchedPapers []models.Paper
+	for _, paper := range researchPapers {
+		for _, keyword := range paper.Keywords {
+			if strings.EqualFold(keyword, model) {
+				matchedPapers = append(matchedPapers, paper)
+				break
+			}
+		}
+	}
+
+	items := append(feed.RepositoryItems(repos), feed.PaperItems(matchedPapers)...)
+
+	writeFeed(c, feed.Feed{
+		Title:       fmt.Sprintf("LLM News - %s", model),
+		Link:        requestBaseURL(c) + "/",
+		Description: fmt.Sprintf("Trending repositories and research articles related to %s", model),
+		Updated:     lastUpdated,
+		Items:       items,
+	})
+}
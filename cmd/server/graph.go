@@ -0,0 +1,90 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gerryyang2025/llm-news/internal/graph"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	graphMu   sync.RWMutex
+	linkGraph = graph.Build(nil, nil, nil) // 论文、仓库、模型、分类和作者之间的关系图
+)
+
+// rebuildGraph rebuilds the relationship graph from the current data
+func rebuildGraph() {
+	hubMu.RLock()
+	items := hubItems
+	hubMu.RUnlock()
+
+	g := graph.Build(githubRepos, researchPapers, items)
+	graphMu.Lock()
+	linkGraph = g
+	graphMu.Unlock()
+
+	stats := g.Stats()
+	log.Printf("Rebuilt link graph with %d papers, %d repositories, %d models and %d edges",
+		stats[graph.KindPaper], stats[graph.KindRepo], stats[graph.KindModel], stats["edges"])
+}
+
+func currentGraph() *graph.Graph {
+	graphMu.RLock()
+	defer graphMu.RUnlock()
+	return linkGraph
+}
+
+// paperReposHandler returns the repositories implementing a paper. The id is
+// the arXiv ID of the paper or the key listed in /api/graph.
+func paperReposHandler(c *gin.Context) {
+	g := currentGraph()
+	key := c.Param("id")
+	node, ok := g.Node(graph.PaperNodeID(key))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "paper not found"})
+		return
+	}
+	repos := g.ReposForPaper(key)
+	if repos == nil {
+		repos = []models.Repository{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"paper": node,
+		"repos": repos,
+	})
+}
+
+// repoPapersHandler returns the papers a repository implements
+func repoPapersHandler(c *gin.Context) {
+	g := currentGraph()
+	name := c.Param("owner") + "/" + c.Param("name")
+	node, ok := g.Node(graph.RepoNodeID(name))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "repository not found"})
+		return
+	}
+	papers := g.PapersForRepo(name)
+	if papers == nil {
+		papers = []models.Paper{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"repo":   node,
+		"papers": papers,
+	})
+}
+
+// graphHandler exports the graph for visualization. ?kind=paper,repo limits
+// the export to the listed node kinds.
+func graphHandler(c *gin.Context) {
+	var kinds []string
+	if raw := strings.TrimSpace(c.Query("kind")); raw != "" {
+		for _, kind := range strings.Split(raw, ",") {
+			kinds = append(kinds, strings.TrimSpace(kind))
+		}
+	}
+	c.JSON(http.StatusOK, currentGraph().Export(kinds...))
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/graph"
+	"github.com/gerryyang2025/llm-news/internal/graphql"
+	"github.com/gerryyang2025/llm-news/internal/i18n"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/ranking"
+	"github.com/gerryyang2025/llm-news/internal/store"
+	"github.com/gerryyang2025/llm-news/internal/trend"
+	"github.com/gin-gonic/gin"
+)
+
+// graphqlSchema serves /graphql, nil when the schema failed to build
+var graphqlSchema *graphql.Schema
+
+// gqlRequest is the context of one GraphQL request. The link graph is taken
+// once so all nested lookups see the same data.
+type gqlRequest struct {
+	c     *gin.Context
+	graph *graph.Graph
+}
+
+// gqlLocale is the lang argument of a root field, applied to everything
+// nested below it
+type gqlLocale struct {
+	lang i18n.Lang
+	ok   bool
+}
+
+func localeArg(args map[string]interface{}) gqlLocale {
+	name, _ := args["lang"].(string)
+	lang, ok := i18n.Parse(name)
+	return gqlLocale{lang: lang, ok: ok}
+}
+
+// gqlRepo and gqlPaper are the sources of the Repository and Paper types.
+// The embedded fields are read by the default resolver through their json
+// tags.
+type gqlRepo struct {
+	models.Repository
+	locale gqlLocale
+}
+
+type gqlPaper struct {
+	models.Paper
+	locale gqlLocale
+}
+
+func wrapRepos(list []models.Repository, locale gqlLocale) []gqlRepo {
+	list = localizeRepos(graphRepos(list), locale.lang, locale.ok)
+	result := make([]gqlRepo, len(list))
+	for i, repo := range list {
+		result[i] = gqlRepo{Repository: repo, locale: locale}
+	}
+	return result
+}
+
+func wrapPapers(list []models.Paper, locale gqlLocale) []gqlPaper {
+	list = localizePapers(graphPapers(list), locale.lang, locale.ok)
+	result := make([]gqlPaper, len(list))
+	for i, paper := range list {
+		result[i] = gqlPaper{Paper: paper, locale: locale}
+	}
+	return result
+}
+
+// limitArg returns the length of a list of n items cut to the limit argument
+func limitArg(args map[string]interface{}, n int) int {
+	if limit, _ := args["limit"].(int); limit > 0 && limit < n {
+		return limit
+	}
+	return n
+}
+
+// snapshotArg returns the data at the time of the at argument, or the
+// current data without it
+func snapshotArg(args map[string]interface{}) (snap store.Snapshot, historical bool, err error) {
+	at, _ := args["at"].(string)
+	if at == "" {
+		return store.Snapshot{TakenAt: lastUpdated, Repos: githubRepos, Papers: researchPapers}, false, nil
+	}
+	snap, _, err = snapshotAt(at)
+	return snap, true, err
+}
+
+// rankerArg picks the ranking strategy like /api/repos: the rank argument,
+// otherwise the A/B arm of the visitor
+func rankerArg(req *gqlRequest, args map[string]interface{}) (ranking.Ranker, error) {
+	if name, _ := args["rank"].(string); name != "" {
+		r, ok := ranking.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("rank must be one of %s", strings.Join(ranking.Names(), ", "))
+		}
+		return r, nil
+	}
+	return requestRanker(req.c)
+}
+
+// Arguments shared by several fields
+var (
+	langArg  = graphql.Arg{Name: "lang", Type: "String", Description: "Language of the descriptions and titles, e.g. zh"}
+	atArg    = graphql.Arg{Name: "at", Type: "String", Description: "Serve the snapshot taken at or before this time"}
+	limitDef = graphql.Arg{Name: "limit", Type: "Int", Description: "Maximum number of items"}
+)
+
+// newGraphQLSchema builds the schema of /graphql. Field names follow the
+// JSON of the REST endpoints.
+func newGraphQLSchema() (*graphql.Schema, error) {
+	trendMetrics := &graphql.Object{Name: "TrendMetrics", Description: "Short-term growth of a repository", Fields: []*graphql.Field{
+		{Name: "stars_24h", Type: "Int!"},
+		{Name: "forks_24h", Type: "Int!"},
+		{Name: "views_7d", Type: "Int!"},
+	}}
+	breakout := &graphql.Object{Name: "Breakout", Description: "Breakout signal of a repository whose star velocity jumped", Fields: []*graphql.Field{
+		{Name: "velocity", Type: "Float!"},
+		{Name: "baseline", Type: "Float!"},
+		{Name: "self_z", Type: "Float!"},
+		{Name: "cohort_z", Type: "Float!"},
+		{Name: "reasons", Type: "[String!]!"},
+	}}
+	starPoint := &graphql.Object{Name: "StarPoint", Description: "Star count of a repository recorded in a snapshot", Fields: []*graphql.Field{
+		{Name: "time", Type: "Time!"},
+		{Name: "stars", Type: "Int!"},
+	}}
+	dataSource := &graphql.Object{Name: "DataSource", Description: "Health of a scraped data source", Fields: []*graphql.Field{
+		{Name: "source", Type: "String!"},
+		{Name: "status", Type: "String!", Description: "ok, degraded or failed"},
+		{Name: "items", Type: "Int!"},
+		{Name: "problems", Type: "[String!]!"},
+		{Name: "error", Type: "String"},
+		{Name: "checked_at", Type: "Time"},
+		{Name: "last_success", Type: "Time"},
+	}}
+
+	repository := &graphql.Object{Name: "Repository", Description: "A trending repository", Fields: []*graphql.Field{
+		{Name: "id", Type: "ID!"},
+		{Name: "name", Type: "String!"},
+		{Name: "url", Type: "String!"},
+		{Name: "description", Type: "String!"},
+		{Name: "language", Type: "String!"},
+		{Name: "stars", Type: "Int!"},
+		{Name: "forks", Type: "Int!"},
+		{Name: "gained_stars", Type: "Int!"},
+		{Name: "gained_forks", Type: "Int!"},
+		{Name: "last_updated", Type: "Time"},
+		{Name: "last_commit", Type: "Time"},
+		{Name: "created_at", Type: "Time"},
+		{Name: "tech_stack", Type: "[String!]!"},
+		{Name: "trend_metrics", Type: "TrendMetrics!"},
+		{Name: "breakout", Type: "Breakout"},
+		{Name: "relevance_score", Type: "Float!"},
+		{Name: "has_docs", Type: "Boolean!"},
+		{Name: "has_readme", Type: "Boolean!"},
+		{Name: "has_wiki", Type: "Boolean!"},
+		{Name: "docs_url", Type: "String!"},
+		{Name: "model_categories", Type: "[String!]!"},
+		{Name: "source", Type: "String!"},
+		{Name: "paper_url", Type: "String!"},
+		{Name: "paper_title", Type: "String!"},
+		{Name: "authors", Type: "[String!]!"},
+		{Name: "tldr", Type: "String!"},
+		{Name: "highlights", Type: "[String!]!"},
+		{Name: "key_techniques", Type: "[String!]!"},
+		{Name: "papers", Type: "[Paper!]!", Description: "Papers the repository implements", Batch: batchRepoPapers,
+			Args: []graphql.Arg{limitDef}},
+		{Name: "star_history", Type: "[StarPoint!]!", Batch: batchStarHistory,
+			Args: []graphql.Arg{{Name: "days", Type: "Int", Default: defaultHistoryDays, Description: "History window, at most 365 days"}}},
+	}}
+	paper := &graphql.Object{Name: "Paper", Description: "A research paper", Fields: []*graphql.Field{
+		{Name: "id", Type: "ID!"},
+		{Name: "title", Type: "String!"},
+		{Name: "url", Type: "String!"},
+		{Name: "authors", Type: "[String!]!"},
+		{Name: "published_date", Type: "Time"},
+		{Name: "source", Type: "String!"},
+		{Name: "summary", Type: "String!"},
+		{Name: "tldr", Type: "String!"},
+		{Name: "keywords", Type: "[String!]!"},
+		{Name: "citation_count", Type: "Int!"},
+		{Name: "citation_velocity", Type: "Float!"},
+		{Name: "novelty_score", Type: "Float!"},
+		{Name: "reproducibility_score", Type: "Float!"},
+		{Name: "core_contributions", Type: "[String!]!"},
+		{Name: "key_techniques", Type: "[String!]!"},
+		{Name: "repos", Type: "[Repository!]!", Description: "Repositories implementing the paper", Batch: batchPaperRepos,
+			Args: []graphql.Arg{limitDef}},
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: []*graphql.Field{
+		{Name: "repos", Type: "[Repository!]!", Description: "Trending repositories, like /api/repos",
+			Args: []graphql.Arg{{Name: "rank", Type: "String", Description: "Ranking strategy"}, langArg, atArg, limitDef},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				ranker, err := rankerArg(p.Context.(*gqlRequest), p.Args)
+				if err != nil {
+					return nil, err
+				}
+				snap, historical, err := snapshotArg(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				rankAt := time.Now()
+				if historical {
+					rankAt = snap.TakenAt
+				}
+				sorted := ranking.Rank(ranker, mergeRepositories(snap.Repos, []models.Repository{}), rankAt)
+				return wrapRepos(sorted[:limitArg(p.Args, len(sorted))], localeArg(p.Args)), nil
+			}},
+		{Name: "repo", Type: "Repository", Description: "A repository by owner/name",
+			Args: []graphql.Arg{{Name: "id", Type: "ID!"}, langArg},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				repo, ok := findRepo(p.Args["id"].(string))
+				if !ok {
+					return nil, nil
+				}
+				return wrapRepos([]models.Repository{repo}, localeArg(p.Args))[0], nil
+			}},
+		{Name: "papers", Type: "[Paper!]!", Description: "Research papers, like /api/research-articles",
+			Args: []graphql.Arg{langArg, atArg, limitDef},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				snap, _, err := snapshotArg(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				list := papersWithURL(snap.Papers)
+				return wrapPapers(list[:limitArg(p.Args, len(list))], localeArg(p.Args)), nil
+			}},
+		{Name: "paper", Type: "Paper", Description: "A paper by ID",
+			Args: []graphql.Arg{{Name: "id", Type: "ID!"}, langArg},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				paper, ok := findPaper(p.Args["id"].(string))
+				if !ok {
+					return nil, nil
+				}
+				return wrapPapers([]models.Paper{paper}, localeArg(p.Args))[0], nil
+			}},
+		{Name: "breakouts", Type: "[Repository!]!", Description: "Repositories breaking out, fastest first",
+			Args: []graphql.Arg{langArg},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				return wrapRepos(trend.Breakouts(githubRepos), localeArg(p.Args)), nil
+			}},
+		{Name: "sources", Type: "[DataSource!]!", Description: "Health of the data sources, like /api/health",
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				return sourceHealth.Records(), nil
+			}},
+		{Name: "last_updated", Type: "Time",
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				return lastUpdated, nil
+			}},
+	}}
+
+	return graphql.NewSchema(query, repository, paper, trendMetrics, breakout, starPoint, dataSource)
+}
+
+// batchRepoPapers loads the linked papers of many repositories. Each
+// repository is looked up once in the link graph of the request.
+func batchRepoPapers(p graphql.Params, sources []interface{}) ([]interface{}, error) {
+	g := p.Context.(*gqlRequest).graph
+	loaded := make(map[gqlLocale]map[string][]gqlPaper)
+	result := make([]interface{}, len(sources))
+	for i, source := range sources {
+		repo := source.(gqlRepo)
+		byID := loaded[repo.locale]
+		if byID == nil {
+			byID = make(map[string][]gqlPaper)
+			loaded[repo.locale] = byID
+		}
+		list, ok := byID[repo.ID]
+		if !ok {
+			list = wrapPapers(g.PapersForRepo(repo.Name), repo.locale)
+			byID[repo.ID] = list
+		}
+		result[i] = list[:limitArg(p.Args, len(list))]
+	}
+	return result, nil
+}
+
+// batchPaperRepos loads the implementing repositories of many papers
+func batchPaperRepos(p graphql.Params, sources []interface{}) ([]interface{}, error) {
+	g := p.Context.(*gqlRequest).graph
+	loaded := make(map[gqlLocale]map[string][]gqlRepo)
+	result := make([]interface{}, len(sources))
+	for i, source := range sources {
+		paper := source.(gqlPaper)
+		byID := loaded[paper.locale]
+		if byID == nil {
+			byID = make(map[string][]gqlRepo)
+			loaded[paper.locale] = byID
+		}
+		list, ok := byID[paper.ID]
+		if !ok {
+			list = wrapRepos(g.ReposForPaper(paper.ID), paper.locale)
+			byID[paper.ID] = list
+		}
+		result[i] = list[:limitArg(p.Args, len(list))]
+	}
+	return result, nil
+}
+
+// batchStarHistory loads the star history of many repositories with a
+// single pass over the snapshots
+func batchStarHistory(p graphql.Params, sources []interface{}) ([]interface{}, error) {
+	ids := make([]string, len(sources))
+	for i, source := range sources {
+		ids[i] = source.(gqlRepo).ID
+	}
+	days, _ := p.Args["days"].(int)
+	histories := starHistories(ids, clampHistoryDays(days))
+	result := make([]interface{}, len(sources))
+	for i, id := range ids {
+		result[i] = histories[models.RepoID(id)]
+	}
+	return result, nil
+}
+
+// graphqlHandler serves /graphql. POST takes a JSON body with query,
+// variables and operationName; GET takes them as query parameters.
+// Requests that fail to parse or validate get 400, field errors are
+// reported next to the data with 200.
+func graphqlHandler(c *gin.Context) {
+	var req graphql.Request
+	if c.Request.Method == http.MethodPost {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: "invalid request body: " + err.Error()}}})
+			return
+		}
+	} else {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: "invalid variables: " + err.Error()}}})
+				return
+			}
+		}
+	}
+	req.Context = &gqlRequest{c: c, graph: currentGraph()}
+
+	resp := graphql.Execute(graphqlSchema, req)
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, resp)
+}
+
+// graphqlSchemaHandler serves /graphql/schema, the schema in SDL
+func graphqlSchemaHandler(c *gin.Context) {
+	c.String(http.StatusOK, graphqlSchema.SDL())
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/health"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/papers"
+	"github.com/gin-gonic/gin"
+)
+
+// sourceHealth holds the health record of every data source
+var sourceHealth = health.NewTracker()
+
+// restoreLatestSnapshot starts from the data of the latest snapshot, so a
+// source that is broken at startup still has its previous good data
+func restoreLatestSnapshot() {
+	if snapshotStore == nil {
+		return
+	}
+	snap, ok := snapshotStore.Latest()
+	if !ok {
+		return
+	}
+	githubRepos = snap.Repos
+	researchPapers = snap.Papers
+	lastUpdated = snap.TakenAt
+	log.Printf("Restored %d repositories and %d papers from the snapshot of %s",
+		len(snap.Repos), len(snap.Papers), snap.TakenAt.Format(time.RFC3339))
+}
+
+// observeSource records the outcome of refreshing a source that is
+// validated by its scraper, such as the trending pages
+func observeSource(source string, items int, err error) {
+	publishHealth(sourceHealth.Observe(health.Report{Source: source, Items: items}, err, time.Now()))
+	var degraded *health.DegradedError
+	if errors.As(err, &degraded) {
+		log.Printf("Warning: %v, keeping the previous data", err)
+	}
+}
+
+// checkPapers validates the fetched papers per source and keeps the previous
+// papers of the sources that came back degraded or empty
+func checkPapers(fresh []models.Paper) []models.Paper {
+	now := time.Now()
+	merged, reports, missing := health.MergePapers(fresh, researchPapers, now)
+	replaced := len(missing) > 0
+	for _, report := range reports {
+		publishHealth(sourceHealth.Observe(report, nil, now))
+		if report.Degraded() {
+			log.Printf("Warning: %v, keeping the previous papers", &health.DegradedError{Report: report})
+			replaced = true
+		}
+	}
+	for _, source := range missing {
+		publishHealth(sourceHealth.Observe(health.Report{Source: source}, health.ErrNoItems, now))
+		log.Printf("Warning: %s returned no papers, keeping the previous papers", source)
+	}
+	if replaced {
+		// 保留的旧论文追加在末尾，重新按相关性排序
+		sort.SliceStable(merged, func(i, j int) bool {
+			return papers.RankingScore(merged[i], now) > papers.RankingScore(merged[j], now)
+		})
+	}
+	return merged
+}
+
+// healthHandler returns the overall status and the record of every source.
+// The status code stays 200 so a degraded scraper does not take the site out
+// of a load balancer.
+func healthHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  sourceHealth.Status(),
+		"sources": sourceHealth.Records(),
+	})
+}
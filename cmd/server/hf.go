@@ -0,0 +1,73 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gerryyang2025/llm-news/internal/health"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/scrapers"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	hubMu      sync.RWMutex
+	hubItems   []models.HubItem     // Hugging Face Hub热门模型、数据集和Space
+	hubHistory *scrapers.HubHistory // 点赞和下载量历史，打开失败时为nil
+)
+
+// refreshHubItems fetches the trending Hugging Face Hub items
+func refreshHubItems() {
+	items, err := scrapers.FetchHuggingFaceTrending(hubHistory)
+	observeSource(health.SourceHuggingFace, len(items), err)
+	if err != nil {
+		log.Printf("Error: Failed to fetch Hugging Face trending items: %v", err)
+		return
+	}
+	hubMu.Lock()
+	hubItems = items
+	hubMu.Unlock()
+	log.Printf("Found %d trending Hugging Face items", len(items))
+	rebuildGraph()
+	publishRefresh("hf")
+}
+
+// hubItemsHandler serves the trending Hub items. ?kind= selects models,
+// datasets or Spaces, ?sort= orders by trending (default), likes, downloads
+// or velocity, and ?limit= caps the result.
+func hubItemsHandler(c *gin.Context) {
+	hubMu.RLock()
+	items := make([]models.HubItem, 0, len(hubItems))
+	kind := strings.TrimSuffix(strings.ToLower(c.Query("kind")), "s")
+	for _, item := range hubItems {
+		if kind == "" || item.Kind == kind {
+			items = append(items, item)
+		}
+	}
+	hubMu.RUnlock()
+
+	var less func(a, b models.HubItem) bool
+	switch c.DefaultQuery("sort", "trending") {
+	case "trending":
+		less = func(a, b models.HubItem) bool { return a.TrendingScore > b.TrendingScore }
+	case "likes":
+		less = func(a, b models.HubItem) bool { return a.Likes > b.Likes }
+	case "downloads":
+		less = func(a, b models.HubItem) bool { return a.Downloads > b.Downloads }
+	case "velocity":
+		less = func(a, b models.HubItem) bool { return a.LikesPerDay > b.LikesPerDay }
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be one of trending, likes, downloads, velocity"})
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	c.JSON(http.StatusOK, items)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/chart"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Star history windows in days
+const (
+	defaultHistoryDays = 30
+	maxHistoryDays     = 365
+)
+
+// starPoint is one star count sample of a repository taken from a snapshot
+type starPoint struct {
+	Time  time.Time `json:"time"`
+	Stars int       `json:"stars"`
+}
+
+// starHistory returns the star counts of a repository recorded in the
+// snapshots of the last days, oldest first
+func starHistory(id string, days int) []starPoint {
+	id = models.RepoID(id)
+	history := starHistories([]string{id}, days)[id]
+	if history == nil {
+		history = []starPoint{}
+	}
+	return history
+}
+
+// starHistories collects the star history of several repositories in one
+// pass over the snapshots, keyed by repository ID
+func starHistories(ids []string, days int) map[string][]starPoint {
+	histories := make(map[string][]starPoint, len(ids))
+	for _, id := range ids {
+		histories[models.RepoID(id)] = nil
+	}
+	if snapshotStore == nil {
+		return histories
+	}
+	now := time.Now()
+	for _, snap := range snapshotStore.Between(now.AddDate(0, 0, -days), now) {
+		for _, r := range snap.Repos {
+			id := models.RepoID(r.Name)
+			if history, ok := histories[id]; ok && (len(history) == 0 || !history[len(history)-1].Time.Equal(snap.TakenAt)) {
+				histories[id] = append(history, starPoint{Time: snap.TakenAt, Stars: r.Stars})
+			}
+		}
+	}
+	return histories
+}
+
+// historyDays reads ?days=, defaulting to 30 and capped at 365
+func historyDays(c *gin.Context) int {
+	days, _ := strconv.Atoi(c.Query("days"))
+	return clampHistoryDays(days)
+}
+
+// clampHistoryDays applies the default and the cap to a history window
+func clampHistoryDays(days int) int {
+	if days <= 0 {
+		return defaultHistoryDays
+	}
+	if days > maxHistoryDays {
+		return maxHistoryDays
+	}
+	return days
+}
+
+// repoHistoryHandler serves /api/repos/:owner/:name/history, the star count
+// samples of a repository over the last ?days= days
+func repoHistoryHandler(c *gin.Context) {
+	id := models.RepoID(c.Param("owner") + "/" + c.Param("name"))
+	days := historyDays(c)
+	history := starHistory(id, days)
+	if _, ok := findRepo(id); !ok && len(history) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "repository not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":     id,
+		"days":   days,
+		"points": history,
+	})
+}
+
+// chartHandler serves /chart/:owner/:name.svg, the star history of a
+// repository drawn as SVG. ?style=full draws a chart with axes instead of a
+// sparkline, and ?width=, ?height= and ?days= adjust it.
+func chartHandler(c *gin.Context) {
+	name := c.Param("name")
+	if !strings.HasSuffix(name, ".svg") {
+		c.String(http.StatusNotFound, "chart not found")
+		return
+	}
+	id := models.RepoID(c.Param("owner") + "/" + strings.TrimSuffix(name, ".svg"))
+
+	var points []chart.Point
+	for _, p := range starHistory(id, historyDays(c)) {
+		points = append(points, chart.Point{Time: p.Time, Value: float64(p.Stars)})
+	}
+	width, _ := strconv.Atoi(c.Query("width"))
+	height, _ := strconv.Atoi(c.Query("height"))
+	opts := chart.Options{
+		Style:  chart.ParseStyle(c.Query("style")),
+		Width:  width,
+		Height: height,
+		Title:  id + " ★",
+	}
+
+	var buf bytes.Buffer
+	if err := chart.Render(&buf, points, opts); err != nil {
+		c.String(http.StatusInternalServerError, "failed to render chart: %v", err)
+		return
+	}
+	// 快照每小时更新一次，允许浏览器和README图片代理缓存
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", buf.Bytes())
+}
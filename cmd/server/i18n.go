@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gerryyang2025/llm-news/internal/i18n"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// langCookie remembers the language picked with ?lang=
+const langCookie = "lang"
+
+// requestLang picks the UI language of a page request. A supported ?lang=
+// parameter is stored in a cookie so later pages keep the choice.
+func requestLang(c *gin.Context) i18n.Lang {
+	if lang, ok := i18n.Parse(c.Query("lang")); ok {
+		c.SetCookie(langCookie, string(lang), 365*24*3600, "/", "", false, false)
+		return lang
+	}
+	cookie, _ := c.Cookie(langCookie)
+	return i18n.Negotiate(cookie, c.GetHeader("Accept-Language"))
+}
+
+// otherLang is the target of the language switch link in the navigation
+func otherLang(lang i18n.Lang) i18n.Lang {
+	if lang == i18n.Chinese {
+		return i18n.English
+	}
+	return i18n.Chinese
+}
+
+// apiLang returns the language requested with ?lang= on API endpoints. API
+// responses are only localized on request so existing clients keep getting
+// the original texts.
+func apiLang(c *gin.Context) (i18n.Lang, bool) {
+	return i18n.Parse(c.Query("lang"))
+}
+
+// localizeRepos swaps in translated descriptions when lang is set
+func localizeRepos(repos []models.Repository, lang i18n.Lang, ok bool) []models.Repository {
+	if !ok {
+		return repos
+	}
+	return i18n.LocalizeRepos(repos, lang)
+}
+
+// localizePapers swaps in translated titles and summaries when lang is set
+func localizePapers(papers []models.Paper, lang i18n.Lang, ok bool) []models.Paper {
+	if !ok {
+		return papers
+	}
+	return i18n.LocalizePapers(papers, lang)
+}
+
+// i18nHandler returns the browser messages and category labels of a language
+func i18nHandler(c *gin.Context) {
+	lang := requestLang(c)
+
+	categories := make(map[string]string)
+	for category := range models.AIModelKeywords {
+		categories[category] = i18n.CategoryLabel(lang, category)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"lang":       lang,
+		"messages":   i18n.Messages(lang, "js."),
+		"categories": categories,
+	})
+}
@@ -10,13 +10,22 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/gerryyang2025/llm-news/internal/digest"
+	"github.com/gerryyang2025/llm-news/internal/health"
+	"github.com/gerryyang2025/llm-news/internal/i18n"
 	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/notifier"
 	"github.com/gerryyang2025/llm-news/internal/papers"
+	"github.com/gerryyang2025/llm-news/internal/ranking"
 	"github.com/gerryyang2025/llm-news/internal/scrapers"
+	"github.com/gerryyang2025/llm-news/internal/store"
+	"github.com/gerryyang2025/llm-news/internal/trend"
+	"github.com/gerryyang2025/llm-news/internal/watchlist"
 	"github.com/gin-gonic/gin"
 	"github.com/go-co-op/gocron"
 )
@@ -26,6 +35,8 @@ var (
 	researchPapers []models.Paper
 	lastUpdated    time.Time
 	verboseLogging = false // 控制是否输出详细日志
+	snapshotStore  *store.Store
+	repoNotifier   *notifier.Notifier // 未配置任何通知渠道时为nil
 )
 
 func getLocalIP() string {
@@ -97,6 +108,49 @@ func main() {
 	logInfo("LLM News server initializing...")
 	logWarning("Verbose logging is currently %t", verboseLogging)
 
+	// 打开快照存储，用于摘要和历史对比
+	dataDir := store.DataDir()
+	st, err := store.Open(dataDir)
+	if err != nil {
+		logError("Failed to open snapshot store: %v", err)
+	} else {
+		snapshotStore = st
+		logInfo("Loaded %d snapshots from %s", st.Len(), dataDir)
+	}
+
+	digestArchive, err = digest.NewArchive(filepath.Join(dataDir, "digests"))
+	if err != nil {
+		logError("Failed to open digest archive: %v", err)
+	}
+	if cfg, ok := digest.SMTPConfigFromEnv(); ok {
+		digestMailer = digest.NewMailer(cfg)
+		logInfo("Digests will be mailed to %d recipients", len(cfg.To))
+	}
+
+	watchlists, err = watchlist.Open(dataDir)
+	if err != nil {
+		logError("Failed to open watchlists: %v", err)
+	}
+
+	graphqlSchema, err = newGraphQLSchema()
+	if err != nil {
+		logError("Failed to build GraphQL schema: %v", err)
+	}
+
+	hubHistory, err = scrapers.OpenHubHistory(filepath.Join(dataDir, "hf_history.json"))
+	if err != nil {
+		logError("Failed to open Hugging Face history: %v", err)
+	}
+
+	repoNotifier = notifier.FromEnv()
+	if repoNotifier != nil {
+		logInfo("Notifications enabled for %d targets", len(repoNotifier.Targets()))
+	}
+
+	// 先恢复最近的快照，初次抓取失败或数据残缺时仍有可用数据
+	restoreLatestSnapshot()
+	compactSnapshots()
+
 	// Initialize the scheduler
 	s := gocron.NewScheduler(time.UTC)
 
@@ -104,13 +158,23 @@ func main() {
 	s.Every(1).Hour().Do(func() {
 		logInfo("Scraping GitHub trending repositories...")
 		repos, err := scrapers.ScrapeGithubTrending()
+		observeSource(health.SourceTrending, len(repos), err)
 		if err != nil {
 			logError("Error scraping GitHub trending: %v", err)
 			return
 		}
+		started := detectBreakouts(repos)
 		githubRepos = repos
 		lastUpdated = time.Now()
 		logInfo("Found %d trending repositories", len(repos))
+		saveSnapshot()
+		if repoNotifier != nil {
+			repoNotifier.CheckRepositories(repos)
+		}
+		evaluateWatchlists()
+		rebuildGraph()
+		publishRefresh("repos")
+		publishBreakouts(started)
 	})
 
 	// Schedule research papers scraping every 6 hours (more frequent than daily)
@@ -121,11 +185,32 @@ func main() {
 			logError("Error fetching research papers: %v", err)
 			return
 		}
-		researchPapers = papers
+		researchPapers = checkPapers(papers)
 		lastUpdated = time.Now()
 		logInfo("Found %d research papers", len(papers))
+		saveSnapshot()
+		evaluateWatchlists()
+		rebuildGraph()
+		publishRefresh("papers")
+	})
+
+	// Hugging Face Hub热门榜单每3小时刷新一次，启动时立即执行
+	s.Every(3).Hours().Do(func() {
+		logInfo("Fetching trending Hugging Face items...")
+		refreshHubItems()
 	})
 
+	// 每日摘要（UTC 00:30，即北京时间08:30），每周一发送周报
+	s.Every(1).Day().At("00:30").Do(func() {
+		runDigest(digest.Daily)
+	})
+	s.Every(1).Monday().At("00:35").Do(func() {
+		runDigest(digest.Weekly)
+	})
+
+	// 每日按保留规则压缩快照，在摘要生成之后执行
+	s.Every(1).Day().At("01:00").Do(compactSnapshots)
+
 	// Start the scheduler in a separate goroutine
 	s.StartAsync()
 
@@ -134,9 +219,11 @@ func main() {
 
 	// GitHub trending
 	repos, err := scrapers.ScrapeGithubTrending()
+	observeSource(health.SourceTrending, len(repos), err)
 	if err != nil {
 		logError("Initial GitHub scraping error: %v", err)
 	} else {
+		detectBreakouts(repos)
 		githubRepos = repos
 		logInfo("Initially found %d trending repositories", len(repos))
 	}
@@ -146,11 +233,14 @@ func main() {
 	if err != nil {
 		logError("Initial papers fetching error: %v", err)
 	} else {
-		researchPapers = papersList
+		researchPapers = checkPapers(papersList)
 		logInfo("Initially found %d research papers", len(papersList))
 	}
 
 	lastUpdated = time.Now()
+	saveSnapshot()
+	evaluateWatchlists()
+	rebuildGraph()
 
 	// Setup the web server
 	r := gin.Default()
@@ -255,6 +345,13 @@ func main() {
 			}
 			return s[:maxLen] + "..."
 		},
+		// 界面文案和模型分类名的本地化
+		"t": func(lang i18n.Lang, key string, args ...interface{}) string {
+			return i18n.T(lang, key, args...)
+		},
+		"categoryLabel": func(lang i18n.Lang, category string) string {
+			return i18n.CategoryLabel(lang, category)
+		},
 	})
 
 	// Load templates
@@ -265,60 +362,58 @@ func main() {
 
 	// Routes
 	r.GET("/", func(c *gin.Context) {
-		// 设置默认标题
-		title := "LLM News - 最新AI/ML开源仓库、研究论文动态"
+		renderIndex(c, githubRepos, researchPapers, lastUpdated, nil)
+	})
 
-		// 处理仓库和论文数据
-		combinedRepos := mergeRepositories(githubRepos, []models.Repository{})
-		sortedRepos := sortRepositories(combinedRepos)
+	// 按日期浏览历史快照
+	r.GET("/archive/:date", archivePageHandler)
 
-		// 确保论文URL不为空
-		papersWithValidURL := make([]models.Paper, len(researchPapers))
-		copy(papersWithValidURL, researchPapers)
+	// 仓库和论文详情页
+	r.GET("/repos/:owner/:name", repoPageHandler)
+	r.GET("/papers/:id", paperPageHandler)
+	r.GET("/chart/:owner/:name", chartHandler)
 
-		for i := range papersWithValidURL {
-			// 如果URL为空，设置一个默认值
-			if papersWithValidURL[i].URL == "" {
-				papersWithValidURL[i].URL = "https://arxiv.org/search/?query=" + url.QueryEscape(papersWithValidURL[i].Title)
-			}
+	// API endpoints
+	r.GET("/api/repos", func(c *gin.Context) {
+		ranker, err := requestRanker(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
-
-		// 准备模板数据
-		data := gin.H{
-			"title":       title,
-			"lastUpdated": lastUpdated.Format("2006-01-02 15:04:05"),
-			"now":         time.Now(),
-			"repos":       sortedRepos,
-			"papers":      papersWithValidURL,
+		// ?at=返回该时间点的快照，并按快照时间排序
+		snap, historical, ok := requestSnapshot(c)
+		if !ok {
+			return
+		}
+		rankAt := time.Now()
+		if historical {
+			rankAt = snap.TakenAt
 		}
+		combinedRepos := mergeRepositories(snap.Repos, []models.Repository{})
+		sortedRepos := ranking.Rank(ranker, combinedRepos, rankAt)
+		lang, ok := apiLang(c)
 
-		c.HTML(200, "index.html", data)
-	})
-
-	// API endpoints
-	r.GET("/api/repos", func(c *gin.Context) {
-		combinedRepos := mergeRepositories(githubRepos, []models.Repository{})
-		sortedRepos := sortRepositories(combinedRepos)
-		c.JSON(200, sortedRepos)
+		// 排序策略在响应头中返回，响应体总是仓库数组
+		c.Header("X-Rank-Strategy", ranker.Name())
+		c.JSON(200, localizeRepos(sortedRepos, lang, ok))
 	})
 
 	r.GET("/api/research-articles", func(c *gin.Context) {
-		// 确保论文URL不为空
-		papersWithValidURL := make([]models.Paper, len(researchPapers))
-		copy(papersWithValidURL, researchPapers)
-
-		for i := range papersWithValidURL {
-			// 如果URL为空，设置一个默认值
-			if papersWithValidURL[i].URL == "" {
-				papersWithValidURL[i].URL = "https://arxiv.org/search/?query=" + url.QueryEscape(papersWithValidURL[i].Title)
-			}
+		snap, _, ok := requestSnapshot(c)
+		if !ok {
+			return
 		}
-		c.JSON(200, papersWithValidURL)
+		lang, ok := apiLang(c)
+		c.JSON(200, localizePapers(papersWithURL(snap.Papers), lang, ok))
 	})
 
-	// 为了向后兼容，保留/api/papers接口，但重定向到/api/research-articles
+	// 为了向后兼容，保留/api/papers接口，但重定向到/api/research-articles，保留查询参数
 	r.GET("/api/papers", func(c *gin.Context) {
-		c.Redirect(http.StatusMovedPermanently, "/api/research-articles")
+		target := "/api/research-articles"
+		if c.Request.URL.RawQuery != "" {
+			target += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, target)
 	})
 
 	r.GET("/api/stats", func(c *gin.Context) {
@@ -331,6 +426,45 @@ func main() {
 
 	// 添加新的API路由用于模型特定仓库搜索
 	r.GET("/api/model-repos/:model", searchModelReposHandler)
+	r.GET("/api/i18n", i18nHandler)
+	r.GET("/api/hf", hubItemsHandler)
+	r.GET("/api/breakouts", breakoutsHandler)
+	r.GET("/api/repos/:owner/:name", repoDetailHandler)
+	r.GET("/api/papers/:id", paperDetailHandler)
+	r.GET("/api/papers/:id/repos", paperReposHandler)
+	r.GET("/api/repos/:owner/:name/papers", repoPapersHandler)
+	r.GET("/api/repos/:owner/:name/history", repoHistoryHandler)
+	r.GET("/api/graph", graphHandler)
+	r.GET("/api/health", healthHandler)
+	r.GET("/api/export/repos", exportReposHandler)
+	r.GET("/api/export/papers", exportPapersHandler)
+	r.GET("/api/changes", changesHandler)
+	r.GET("/api/stream", streamHandler)
+
+	// 用户保存的关注规则
+	if watchlists != nil {
+		r.GET("/api/watchlists", listWatchlistsHandler)
+		r.POST("/api/watchlists", createWatchlistHandler)
+		r.GET("/api/watchlists/:id", getWatchlistHandler)
+		r.PUT("/api/watchlists/:id", updateWatchlistHandler)
+		r.DELETE("/api/watchlists/:id", deleteWatchlistHandler)
+		r.GET("/api/watchlists/:id/hits", watchlistHitsHandler)
+	}
+
+	// GraphQL查询接口，嵌套的论文、仓库和星标历史按层批量加载
+	if graphqlSchema != nil {
+		r.GET("/graphql", graphqlHandler)
+		r.POST("/graphql", graphqlHandler)
+		r.GET("/graphql/schema", graphqlSchemaHandler)
+	}
+
+	// 历史摘要归档
+	r.GET("/digest/:date", digestHandler)
+
+	// RSS/Atom订阅，使用 ?format=atom 切换为Atom 1.0
+	r.GET("/feed/repos.xml", repoFeedHandler)
+	r.GET("/feed/papers.xml", paperFeedHandler)
+	r.GET("/feed/model/:model", modelFeedHandler)
 
 	// Start the server
 	localIP := getLocalIP()
@@ -342,6 +476,81 @@ func main() {
 	}
 }
 
+// renderIndex renders the home page for repos and papers collected at
+// updated. archive is nil for the live page.
+func renderIndex(c *gin.Context, repos []models.Repository, papersList []models.Paper, updated time.Time, archive *archiveInfo) {
+	// 根据?lang=、cookie和Accept-Language选择界面语言
+	lang := requestLang(c)
+	title := i18n.T(lang, "site.title")
+
+	// 处理仓库和论文数据，按所选策略排序，无效的?rank=按A/B分组选择策略
+	ranker := pageRanker(c)
+	// 存档页按快照时间排序，保证同一天的页面不随访问时间变化
+	rankAt := time.Now()
+	if archive != nil {
+		rankAt = updated
+	}
+	combinedRepos := mergeRepositories(repos, []models.Repository{})
+	sortedRepos := i18n.LocalizeRepos(ranking.Rank(ranker, combinedRepos, rankAt), lang)
+
+	// 实时页面标出上次访问后新增的仓库和论文
+	var newRepos, newPapers map[string]bool
+	var lastVisit string
+	if archive == nil {
+		newRepos, newPapers, lastVisit = visitChanges(c, ranker)
+	}
+
+	// 准备模板数据
+	data := gin.H{
+		"title":       title,
+		"lang":        lang,
+		"switchLang":  otherLang(lang),
+		"jsMessages":  i18n.Messages(lang, "js."),
+		"lastUpdated": updated.Format("2006-01-02 15:04:05"),
+		"now":         time.Now(),
+		"repos":       sortedRepos,
+		"rankOptions": rankOptions(ranker),
+		"breakouts":   i18n.LocalizeRepos(trend.Breakouts(repos), lang),
+		"papers":      i18n.LocalizePapers(papersWithURL(papersList), lang),
+		"archive":     archive,
+		"newRepos":    newRepos,
+		"newPapers":   newPapers,
+		"newCount":    len(newRepos) + len(newPapers),
+		"lastVisit":   lastVisit,
+	}
+
+	c.HTML(200, "index.html", data)
+}
+
+// papersWithURL returns a copy of list in which every paper has a URL
+func papersWithURL(list []models.Paper) []models.Paper {
+	result := make([]models.Paper, len(list))
+	copy(result, list)
+
+	for i := range result {
+		// 如果URL为空，设置一个默认值
+		if result[i].URL == "" {
+			result[i].URL = "https://arxiv.org/search/?query=" + url.QueryEscape(result[i].Title)
+		}
+	}
+	return result
+}
+
+// saveSnapshot persists the current repositories and papers
+func saveSnapshot() {
+	if snapshotStore == nil {
+		return
+	}
+	snap := store.Snapshot{
+		TakenAt: time.Now(),
+		Repos:   githubRepos,
+		Papers:  researchPapers,
+	}
+	if err := snapshotStore.Save(snap); err != nil {
+		log.Printf("Error: Failed to save snapshot: %v", err)
+	}
+}
+
 // mergeRepositories combines repositories from different sources and removes duplicates
 func mergeRepositories(repos1, repos2 []models.Repository) []models.Repository {
 	// Create a map to detect duplicates
@@ -498,6 +707,7 @@ func directSearchGitHub(query string) []models.Repository {
 		updatedAt, _ := time.Parse(time.RFC3339, item.UpdatedAt)
 
 		repo := models.Repository{
+			ID:          models.RepoID(item.FullName),
 			Name:        item.FullName,
 			URL:         item.HTMLURL,
 			Description: item.Description,
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/gerryyang2025/llm-news/internal/ranking"
+	"github.com/gin-gonic/gin"
+)
+
+// rankCookie remembers the A/B arm a visitor was assigned to
+const rankCookie = "rank"
+
+// requestRanker picks the ranking strategy of an API request: the one named
+// by ?rank=, or the first strategy of LLM_NEWS_RANKERS. API responses do not
+// take part in the A/B test, so the same URL always returns the same order.
+func requestRanker(c *gin.Context) (ranking.Ranker, error) {
+	if name := c.Query("rank"); name != "" {
+		r, ok := ranking.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("rank must be one of %s", strings.Join(ranking.Names(), ", "))
+		}
+		return r, nil
+	}
+	return ranking.Arms()[0], nil
+}
+
+// pageRanker picks the ranking strategy of a page view. A valid ?rank= wins;
+// otherwise visitors are split between the strategies listed in
+// LLM_NEWS_RANKERS and keep their arm through a cookie.
+func pageRanker(c *gin.Context) ranking.Ranker {
+	if r, ok := ranking.Get(c.Query("rank")); ok {
+		return r
+	}
+
+	arms := ranking.Arms()
+	if len(arms) == 1 {
+		return arms[0]
+	}
+	if cookie, _ := c.Cookie(rankCookie); cookie != "" {
+		for _, arm := range arms {
+			if arm.Name() == cookie {
+				return arm
+			}
+		}
+	}
+	arm := arms[rand.Intn(len(arms))]
+	c.SetCookie(rankCookie, arm.Name(), 30*24*3600, "/", "", false, true)
+	return arm
+}
+
+// rankOption is a ranking strategy offered on the page
+type rankOption struct {
+	Name   string
+	Active bool
+}
+
+// rankOptions lists the strategies with the current one marked active
+func rankOptions(current ranking.Ranker) []rankOption {
+	var options []rankOption
+	for _, name := range ranking.Names() {
+		options = append(options, rankOption{Name: name, Active: name == current.Name()})
+	}
+	return options
+}
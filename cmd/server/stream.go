@@ -0,0 +1,80 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/events"
+	"github.com/gerryyang2025/llm-news/internal/health"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat is the interval of the keep-alive events, short enough for
+// proxies that close idle connections
+const streamHeartbeat = 30 * time.Second
+
+// liveEvents feeds /api/stream
+var liveEvents = events.NewBroker()
+
+// publishRefresh announces that source was refreshed successfully
+func publishRefresh(source string) {
+	liveEvents.Publish(events.Refresh, gin.H{
+		"source":       source,
+		"last_updated": lastUpdated,
+		"repos":        len(githubRepos),
+		"papers":       len(researchPapers),
+	})
+}
+
+// publishBreakouts announces repositories that started breaking out
+func publishBreakouts(repos []models.Repository) {
+	if len(repos) == 0 {
+		return
+	}
+	list := make([]gin.H, len(repos))
+	for i, repo := range repos {
+		list[i] = gin.H{"id": repo.ID, "name": repo.Name, "url": repo.URL, "velocity": repo.Breakout.Velocity}
+	}
+	liveEvents.Publish(events.Breakout, gin.H{"repos": list})
+}
+
+// publishHealth announces a source whose health status changed
+func publishHealth(rec health.Record, changed bool) {
+	if changed {
+		liveEvents.Publish(events.Health, gin.H{"status": sourceHealth.Status(), "source": rec})
+	}
+}
+
+// streamHandler serves /api/stream, a Server-Sent Events stream of refresh,
+// breakout and health events. Events carry JSON data; a ping event is sent
+// when nothing else happened for streamHeartbeat.
+func streamHandler(c *gin.Context) {
+	ch := liveEvents.Subscribe()
+	defer liveEvents.Unsubscribe(ch)
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭nginx的响应缓冲
+	c.Status(http.StatusOK)
+	c.SSEvent("ping", gin.H{"time": time.Now()})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event.Data)
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{"time": time.Now()})
+		case <-c.Request.Context().Done():
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/notifier"
+	"github.com/gerryyang2025/llm-news/internal/watchlist"
+	"github.com/gin-gonic/gin"
+)
+
+var watchlists *watchlist.Manager
+
+// evaluateWatchlists runs the saved watchlists against the current data and
+// pushes new hits of watchlists with notify enabled
+func evaluateWatchlists() {
+	if watchlists == nil {
+		return
+	}
+
+	hits, err := watchlists.Evaluate(githubRepos, researchPapers)
+	if err != nil {
+		log.Printf("Error: Failed to record watchlist hits: %v", err)
+	}
+	if len(hits) == 0 {
+		return
+	}
+	if verboseLogging {
+		log.Printf("Watchlists produced %d new hits", len(hits))
+	}
+
+	if repoNotifier == nil {
+		return
+	}
+	var events []notifier.Event
+	for _, hit := range hits {
+		w, err := watchlists.Get(hit.WatchlistID)
+		if err != nil || !w.Notify {
+			continue
+		}
+		events = append(events, notifier.Event{
+			Kind:   notifier.EventWatchlist,
+			Title:  hit.Title,
+			URL:    hit.URL,
+			Reason: fmt.Sprintf("Matched watchlist %q", w.Name),
+			Text:   w.Rule,
+			Time:   hit.MatchedAt,
+		})
+	}
+	repoNotifier.Notify(events)
+}
+
+// listWatchlistsHandler returns all watchlists, or those of ?owner=
+func listWatchlistsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, watchlists.List(c.Query("owner")))
+}
+
+func getWatchlistHandler(c *gin.Context) {
+	w, err := watchlists.Get(c.Param("id"))
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+func createWatchlistHandler(c *gin.Context) {
+	var w watchlist.Watchlist
+	if err := c.ShouldBindJSON(&w); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON: " + err.Error()})
+		return
+	}
+	created, err := watchlists.Create(w)
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func updateWatchlistHandler(c *gin.Context) {
+	var w watchlist.Watchlist
+	if err := c.ShouldBindJSON(&w); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON: " + err.Error()})
+		return
+	}
+	updated, err := watchlists.Update(c.Param("id"), w)
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func deleteWatchlistHandler(c *gin.Context) {
+	if err := watchlists.Delete(c.Param("id")); err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// watchlistHitsHandler returns the recorded hits of a watchlist, newest first
+func watchlistHitsHandler(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := watchlists.Get(id); err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"hits":       watchlists.Hits(id),
+		"checked_at": lastUpdated.Format(time.RFC3339),
+	})
+}
+
+// writeWatchlistError maps a manager error to 404 for unknown watchlists,
+// 400 for invalid input and 500 for storage failures
+func writeWatchlistError(c *gin.Context, err error) {
+	var invalid *watchlist.ValidationError
+	switch {
+	case errors.Is(err, watchlist.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("Error: Failed to store watchlists: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store watchlists"})
+	}
+}
@@ -0,0 +1,142 @@
+// Package changes compares two snapshots and reports what changed between
+// them: repositories entering and leaving the trending list, rank movements,
+// star deltas and new papers.
+package changes
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/store"
+)
+
+// RankMove is a repository whose position changed. Ranks start at 1 and a
+// positive Delta means the repository moved up.
+type RankMove struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	FromRank int    `json:"from_rank"`
+	ToRank   int    `json:"to_rank"`
+	Delta    int    `json:"delta"`
+}
+
+// StarDelta is the change of the star count of a repository present in
+// both snapshots
+type StarDelta struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Stars int    `json:"stars"`
+	Delta int    `json:"delta"`
+}
+
+// Changes is the difference between a base and a current snapshot
+type Changes struct {
+	From       time.Time           `json:"from"` // 基准快照时间，没有基准时为零值
+	To         time.Time           `json:"to"`
+	Entered    []models.Repository `json:"entered"` // 新进入趋势榜的仓库，按当前排名
+	Left       []models.Repository `json:"left"`    // 离开趋势榜的仓库，按原排名
+	Moved      []RankMove          `json:"moved"`
+	StarDeltas []StarDelta         `json:"star_deltas"`
+	NewPapers  []models.Paper      `json:"new_papers"`
+}
+
+// Count returns the number of entered repositories and new papers, the
+// items a visitor has not seen before
+func (c Changes) Count() int {
+	return len(c.Entered) + len(c.NewPapers)
+}
+
+// Diff compares base with current. The order of the repositories in each
+// snapshot is taken as their rank, so callers rank both lists with the same
+// strategy first. Moved and StarDeltas are sorted by the size of the change
+// and cut to limit entries when limit > 0.
+func Diff(base, current store.Snapshot, limit int) Changes {
+	c := Changes{
+		From:       base.TakenAt,
+		To:         current.TakenAt,
+		Entered:    []models.Repository{},
+		Left:       []models.Repository{},
+		Moved:      []RankMove{},
+		StarDeltas: []StarDelta{},
+		NewPapers:  []models.Paper{},
+	}
+
+	baseRank := make(map[string]int, len(base.Repos))
+	for i, repo := range base.Repos {
+		baseRank[repoKey(repo)] = i
+	}
+	currentKeys := make(map[string]bool, len(current.Repos))
+
+	for i, repo := range current.Repos {
+		key := repoKey(repo)
+		currentKeys[key] = true
+		j, existed := baseRank[key]
+		if !existed {
+			c.Entered = append(c.Entered, repo)
+			continue
+		}
+		if i != j {
+			c.Moved = append(c.Moved, RankMove{ID: key, Name: repo.Name, FromRank: j + 1, ToRank: i + 1, Delta: j - i})
+		}
+		// 星标数缺失时无法比较
+		previous := base.Repos[j]
+		if previous.Stars > 0 && repo.Stars > 0 && repo.Stars != previous.Stars {
+			c.StarDeltas = append(c.StarDeltas, StarDelta{ID: key, Name: repo.Name, Stars: repo.Stars, Delta: repo.Stars - previous.Stars})
+		}
+	}
+	for _, repo := range base.Repos {
+		if !currentKeys[repoKey(repo)] {
+			c.Left = append(c.Left, repo)
+		}
+	}
+
+	sort.SliceStable(c.Moved, func(i, j int) bool {
+		return abs(c.Moved[i].Delta) > abs(c.Moved[j].Delta)
+	})
+	sort.SliceStable(c.StarDeltas, func(i, j int) bool {
+		return c.StarDeltas[i].Delta > c.StarDeltas[j].Delta
+	})
+	if limit > 0 && len(c.Moved) > limit {
+		c.Moved = c.Moved[:limit]
+	}
+	if limit > 0 && len(c.StarDeltas) > limit {
+		c.StarDeltas = c.StarDeltas[:limit]
+	}
+
+	basePapers := make(map[string]bool, len(base.Papers))
+	for _, paper := range base.Papers {
+		basePapers[paperKey(paper)] = true
+	}
+	for _, paper := range current.Papers {
+		if !basePapers[paperKey(paper)] {
+			c.NewPapers = append(c.NewPapers, paper)
+		}
+	}
+
+	return c
+}
+
+// repoKey identifies a repository across snapshots; older snapshots were
+// stored before repositories had an ID
+func repoKey(repo models.Repository) string {
+	if repo.ID != "" {
+		return repo.ID
+	}
+	return models.RepoID(repo.Name)
+}
+
+// paperKey identifies a paper across snapshots
+func paperKey(paper models.Paper) string {
+	if paper.ID != "" {
+		return paper.ID
+	}
+	return models.PaperID(paper.URL, paper.Title)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
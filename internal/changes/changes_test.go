@@ -0,0 +1,79 @@
+package changes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/store"
+)
+
+func repo(name string, stars int) models.Repository {
+	return models.Repository{ID: models.RepoID(name), Name: name, Stars: stars}
+}
+
+func TestDiff(t *testing.T) {
+	base := store.Snapshot{
+		TakenAt: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		Repos:   []models.Repository{repo("a/a", 100), repo("b/b", 200), repo("c/c", 300), repo("d/d", 0)},
+		Papers:  []models.Paper{{Title: "Old", URL: "https://arxiv.org/abs/2401.00001"}},
+	}
+	current := store.Snapshot{
+		TakenAt: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Repos:   []models.Repository{repo("c/c", 350), repo("e/e", 10), repo("a/a", 90), repo("D/D", 50)},
+		Papers: []models.Paper{
+			{ID: "2401.00001", Title: "Old", URL: "https://arxiv.org/abs/2401.00001"},
+			{Title: "New", URL: "https://arxiv.org/abs/2410.00002"},
+		},
+	}
+
+	c := Diff(base, current, 0)
+
+	if len(c.Entered) != 1 || c.Entered[0].Name != "e/e" {
+		t.Errorf("entered = %v, want e/e", c.Entered)
+	}
+	if len(c.Left) != 1 || c.Left[0].Name != "b/b" {
+		t.Errorf("left = %v, want b/b", c.Left)
+	}
+
+	wantMoves := []RankMove{
+		{ID: "c/c", Name: "c/c", FromRank: 3, ToRank: 1, Delta: 2},
+		{ID: "a/a", Name: "a/a", FromRank: 1, ToRank: 3, Delta: -2},
+	}
+	if len(c.Moved) != len(wantMoves) {
+		t.Fatalf("moved = %v, want %v", c.Moved, wantMoves)
+	}
+	for i, want := range wantMoves {
+		if c.Moved[i] != want {
+			t.Errorf("move %d = %+v, want %+v", i, c.Moved[i], want)
+		}
+	}
+
+	// d/d的旧星标数缺失，不计算增量
+	if len(c.StarDeltas) != 2 || c.StarDeltas[0].Name != "c/c" || c.StarDeltas[0].Delta != 50 || c.StarDeltas[1].Delta != -10 {
+		t.Errorf("star deltas = %+v", c.StarDeltas)
+	}
+
+	// 旧快照中的论文没有ID，按URL推导后仍能匹配
+	if len(c.NewPapers) != 1 || c.NewPapers[0].Title != "New" {
+		t.Errorf("new papers = %v, want New", c.NewPapers)
+	}
+	if c.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", c.Count())
+	}
+
+	if limited := Diff(base, current, 1); len(limited.Moved) != 1 || len(limited.StarDeltas) != 1 {
+		t.Errorf("limit 1 kept %d moves and %d star deltas", len(limited.Moved), len(limited.StarDeltas))
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	snap := store.Snapshot{Repos: []models.Repository{repo("a/a", 1)}}
+	c := Diff(snap, snap, 10)
+	if c.Count() != 0 || len(c.Left) != 0 || len(c.Moved) != 0 || len(c.StarDeltas) != 0 {
+		t.Errorf("identical snapshots differ: %+v", c)
+	}
+	if c.Entered == nil || c.NewPapers == nil {
+		t.Error("empty lists should encode as [] rather than null")
+	}
+}
@@ -0,0 +1,260 @@
+// Package chart renders time series such as star history as standalone SVG
+// images, so they can be embedded in pages, README badges and digests
+// without client-side chart libraries.
+package chart
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// Style selects how a series is drawn
+type Style string
+
+const (
+	StyleSparkline Style = "sparkline" // 无坐标轴的迷你折线，适合卡片和徽章
+	StyleFull      Style = "full"      // 带标题、坐标轴和刻度的完整图表
+)
+
+// ParseStyle maps a query value to a Style, falling back to a sparkline
+func ParseStyle(value string) Style {
+	if strings.EqualFold(strings.TrimSpace(value), string(StyleFull)) {
+		return StyleFull
+	}
+	return StyleSparkline
+}
+
+// Point is one sample of a series
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Options controls the size and decoration of a chart. Zero values fall
+// back to the defaults of the style.
+type Options struct {
+	Style  Style
+	Width  int
+	Height int
+	Title  string // 仅完整图表显示
+	Color  string // 折线颜色，默认为站点主色
+}
+
+// Size limits of a chart in pixels
+const (
+	MinSize = 16
+	MaxSize = 2000
+)
+
+const defaultColor = "#4f46e5"
+
+func (o Options) withDefaults() Options {
+	if o.Style != StyleFull {
+		o.Style = StyleSparkline
+	}
+	if o.Width <= 0 {
+		o.Width = 120
+		if o.Style == StyleFull {
+			o.Width = 600
+		}
+	}
+	if o.Height <= 0 {
+		o.Height = 30
+		if o.Style == StyleFull {
+			o.Height = 240
+		}
+	}
+	o.Width = clamp(o.Width, MinSize, MaxSize)
+	o.Height = clamp(o.Height, MinSize, MaxSize)
+	if o.Color == "" {
+		o.Color = defaultColor
+	}
+	return o
+}
+
+func clamp(v, low, high int) int {
+	if v < low {
+		return low
+	}
+	if v > high {
+		return high
+	}
+	return v
+}
+
+// box is the plot area of a chart
+type box struct {
+	left, top, width, height float64
+}
+
+// scale maps the points into the box. Times are spread over the width and
+// values in [low, high] over the height, higher values towards the top. A
+// flat series is drawn through the middle and a single point as a
+// horizontal line.
+func scale(points []Point, b box, low, high float64) []string {
+	if len(points) == 0 {
+		return nil
+	}
+	start := points[0].Time
+	span := points[len(points)-1].Time.Sub(start)
+
+	coords := make([]string, 0, len(points)+1)
+	y := 0.0
+	for i, p := range points {
+		x := b.left
+		switch {
+		case span > 0:
+			x += float64(p.Time.Sub(start)) / float64(span) * b.width
+		case len(points) > 1:
+			x += float64(i) / float64(len(points)-1) * b.width
+		}
+		y = b.top + b.height/2
+		if high > low {
+			y = b.top + b.height - (p.Value-low)/(high-low)*b.height
+		}
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	if len(coords) == 1 {
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", b.left+b.width, y))
+	}
+	return coords
+}
+
+func valueRange(points []Point) (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		low = math.Min(low, p.Value)
+		high = math.Max(high, p.Value)
+	}
+	return low, high
+}
+
+// Render writes the points, oldest first, as an SVG document
+func Render(w io.Writer, points []Point, opts Options) error {
+	opts = opts.withDefaults()
+	bw := bufio.NewWriter(w)
+	if opts.Style == StyleFull {
+		renderFull(bw, points, opts)
+	} else {
+		renderSparkline(bw, points, opts)
+	}
+	return bw.Flush()
+}
+
+func renderSparkline(w *bufio.Writer, points []Point, opts Options) {
+	width, height := float64(opts.Width), float64(opts.Height)
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		opts.Width, opts.Height, opts.Width, opts.Height)
+	low, high := valueRange(points)
+	coords := scale(points, box{left: 1, top: 2, width: width - 2, height: height - 4}, low, high)
+	if len(coords) > 0 {
+		writeArea(w, coords, height, opts.Color)
+		fmt.Fprintf(w, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5" stroke-linejoin="round"/>`,
+			strings.Join(coords, " "), escape(opts.Color))
+	}
+	w.WriteString(`</svg>`)
+}
+
+// Margins of a full chart, leaving room for the title and axis labels
+const (
+	marginLeft   = 56.0
+	marginRight  = 16.0
+	marginTop    = 32.0
+	marginBottom = 28.0
+	yTicks       = 4
+)
+
+func renderFull(w *bufio.Writer, points []Point, opts Options) {
+	width, height := float64(opts.Width), float64(opts.Height)
+	plot := box{
+		left:   marginLeft,
+		top:    marginTop,
+		width:  math.Max(width-marginLeft-marginRight, 1),
+		height: math.Max(height-marginTop-marginBottom, 1),
+	}
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="-apple-system, Segoe UI, Roboto, Arial, sans-serif" font-size="11" fill="#6b7280">`,
+		opts.Width, opts.Height, opts.Width, opts.Height)
+	fmt.Fprintf(w, `<rect width="%d" height="%d" fill="#ffffff"/>`, opts.Width, opts.Height)
+	if opts.Title != "" {
+		fmt.Fprintf(w, `<text x="%.1f" y="20" font-size="13" font-weight="600" fill="#1f2937">%s</text>`, marginLeft, escape(opts.Title))
+	}
+
+	if len(points) == 0 {
+		fmt.Fprintf(w, `<text x="%.1f" y="%.1f" text-anchor="middle">no data</text></svg>`,
+			plot.left+plot.width/2, plot.top+plot.height/2)
+		return
+	}
+
+	// 纵轴刻度和网格线
+	// 取值范围太小时放大到每个刻度至少相差1，避免刻度标签重复
+	low, high := valueRange(points)
+	if high-low < yTicks {
+		low = math.Floor(low)
+		high = low + yTicks
+	}
+	for i := 0; i <= yTicks; i++ {
+		y := plot.top + plot.height - float64(i)/yTicks*plot.height
+		value := low + (high-low)*float64(i)/yTicks
+		fmt.Fprintf(w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e5e7eb"/>`, plot.left, y, plot.left+plot.width, y)
+		fmt.Fprintf(w, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`, plot.left-6, y+4, FormatValue(value))
+	}
+
+	// 横轴只标注首尾日期
+	first, last := points[0].Time, points[len(points)-1].Time
+	layout := "Jan 02"
+	if last.Sub(first) < 48*time.Hour {
+		layout = "Jan 02 15:04"
+	}
+	labelY := plot.top + plot.height + 18
+	fmt.Fprintf(w, `<text x="%.1f" y="%.1f">%s</text>`, plot.left, labelY, first.UTC().Format(layout))
+	if !last.Equal(first) {
+		fmt.Fprintf(w, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`, plot.left+plot.width, labelY, last.UTC().Format(layout))
+	}
+
+	coords := scale(points, plot, low, high)
+	writeArea(w, coords, plot.top+plot.height, opts.Color)
+	fmt.Fprintf(w, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round"/>`,
+		strings.Join(coords, " "), escape(opts.Color))
+
+	end := strings.SplitN(coords[len(coords)-1], ",", 2)
+	fmt.Fprintf(w, `<circle cx="%s" cy="%s" r="3" fill="%s"/>`, end[0], end[1], escape(opts.Color))
+	fmt.Fprintf(w, `<text x="%.1f" y="20" text-anchor="end" font-size="12" fill="#1f2937">%s</text>`,
+		plot.left+plot.width, FormatValue(points[len(points)-1].Value))
+	w.WriteString(`</svg>`)
+}
+
+// writeArea fills the region below the line with a translucent color
+func writeArea(w *bufio.Writer, coords []string, bottom float64, color string) {
+	first := strings.SplitN(coords[0], ",", 2)[0]
+	last := strings.SplitN(coords[len(coords)-1], ",", 2)[0]
+	fmt.Fprintf(w, `<polygon points="%s,%.1f %s %s,%.1f" fill="%s" fill-opacity="0.12" stroke="none"/>`,
+		first, bottom, strings.Join(coords, " "), last, bottom, escape(color))
+}
+
+// FormatValue formats a count compactly, e.g. 950, 12.3k or 1.2M
+func FormatValue(v float64) string {
+	switch abs := math.Abs(v); {
+	case abs >= 1e6:
+		return trimZero(fmt.Sprintf("%.1f", v/1e6)) + "M"
+	case abs >= 1e4:
+		return trimZero(fmt.Sprintf("%.1f", v/1e3)) + "k"
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
+
+func trimZero(s string) string {
+	return strings.TrimSuffix(s, ".0")
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func samplePoints() []Point {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	return []Point{
+		{Time: start, Value: 1000},
+		{Time: start.Add(24 * time.Hour), Value: 1500},
+		{Time: start.Add(72 * time.Hour), Value: 3000},
+	}
+}
+
+// render renders the chart and checks that the result is well-formed XML
+func render(t *testing.T, points []Point, opts Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Render(&buf, points, opts); err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, buf.String())
+		}
+	}
+	return buf.String()
+}
+
+func TestSparkline(t *testing.T) {
+	svg := render(t, samplePoints(), Options{})
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="120" height="30"`) {
+		t.Errorf("unexpected header: %s", svg)
+	}
+	// 按时间比例分布横坐标，最高值在顶部
+	if !strings.Contains(svg, `points="1.0,28.0 40.3,21.5 119.0,2.0"`) {
+		t.Errorf("unexpected polyline: %s", svg)
+	}
+	if strings.Contains(svg, "<text") {
+		t.Error("sparklines should not have labels")
+	}
+}
+
+func TestSparklineEdgeCases(t *testing.T) {
+	if svg := render(t, nil, Options{}); strings.Contains(svg, "polyline") {
+		t.Errorf("empty series should draw nothing: %s", svg)
+	}
+	single := render(t, samplePoints()[:1], Options{Width: 100, Height: 20})
+	if !strings.Contains(single, `points="1.0,10.0 99.0,10.0"`) {
+		t.Errorf("single point should be a flat line: %s", single)
+	}
+	huge := render(t, samplePoints(), Options{Width: 100000, Height: -1})
+	if !strings.Contains(huge, `width="2000" height="30"`) {
+		t.Errorf("size should be clamped: %s", huge[:80])
+	}
+}
+
+func TestFullChart(t *testing.T) {
+	svg := render(t, samplePoints(), Options{Style: StyleFull, Title: `a/b <"stars">`})
+	for _, want := range []string{
+		`width="600" height="240"`,
+		`a/b &lt;&#34;stars&#34;&gt;`,
+		`>Oct 01</text>`,
+		`>Oct 04</text>`,
+		`>1000</text>`,
+		`>3000</text>`,
+		`<circle`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("full chart is missing %q:\n%s", want, svg)
+		}
+	}
+
+	if svg := render(t, nil, Options{Style: StyleFull}); !strings.Contains(svg, "no data") {
+		t.Errorf("empty full chart should say so: %s", svg)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := map[float64]string{
+		950:     "950",
+		12000:   "12k",
+		12345:   "12.3k",
+		1250000: "1.2M",
+	}
+	for v, want := range tests {
+		if got := FormatValue(v); got != want {
+			t.Errorf("FormatValue(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	if ParseStyle("FULL") != StyleFull || ParseStyle("") != StyleSparkline || ParseStyle("pie") != StyleSparkline {
+		t.Error("unexpected style parsing")
+	}
+}
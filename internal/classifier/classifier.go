@@ -0,0 +1,167 @@
+package classifier
+
+import (
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/textmatch"
+)
+
+// Thresholds applied to classification results
+const (
+	AIThreshold       = 0.5 // AI概率超过该值才视为AI相关
+	CategoryThreshold = 0.3 // 分类概率达到该值才打上对应标签
+)
+
+// OtherCategory is the label used when no model category applies
+const OtherCategory = "其他"
+
+// Result holds the probabilities produced for one text
+type Result struct {
+	AI         float64            `json:"ai"`         // 属于AI/ML领域的概率
+	Categories map[string]float64 `json:"categories"` // AIModelKeywords各分类的概率
+}
+
+// Labels returns the categories whose probability reaches min, most likely
+// first, or OtherCategory when none does
+func (r Result) Labels(min float64) []string {
+	var labels []string
+	for category, p := range r.Categories {
+		if category != OtherCategory && p >= min {
+			labels = append(labels, category)
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if r.Categories[labels[i]] != r.Categories[labels[j]] {
+			return r.Categories[labels[i]] > r.Categories[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+	if len(labels) == 0 {
+		labels = []string{OtherCategory}
+	}
+	return labels
+}
+
+// Classifier assigns AI relevance and model category probabilities to texts
+type Classifier interface {
+	Name() string
+	Classify(texts []string) ([]Result, error)
+}
+
+// KeywordClassifier is the keyword matcher used by the scrapers, expressed
+// as a Classifier. Probabilities are either 0 or 1.
+type KeywordClassifier struct {
+	keywords   *textmatch.Matcher
+	categories map[string]*textmatch.Matcher
+}
+
+// NewKeywordClassifier returns a keyword classifier over models.AIKeywords
+// and models.AIModelKeywords
+func NewKeywordClassifier() *KeywordClassifier {
+	k := &KeywordClassifier{
+		keywords:   textmatch.New(models.AIKeywords...),
+		categories: make(map[string]*textmatch.Matcher, len(models.AIModelKeywords)),
+	}
+	for category, keywords := range models.AIModelKeywords {
+		k.categories[category] = textmatch.New(keywords...)
+	}
+	return k
+}
+
+func (k *KeywordClassifier) Name() string { return "keyword" }
+
+func (k *KeywordClassifier) Classify(texts []string) ([]Result, error) {
+	results := make([]Result, len(texts))
+	for i, text := range texts {
+		tokens := textmatch.Tokenize(text)
+		result := Result{Categories: make(map[string]float64)}
+
+		if k.keywords.MatchAnyTokens(tokens) {
+			result.AI = 1
+		}
+		for category, matcher := range k.categories {
+			if matcher.MatchAnyTokens(tokens) {
+				result.Categories[category] = 1
+				result.AI = 1
+			}
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// Fallback classifies with Primary and falls back to Secondary when Primary
+// fails, e.g. when the embedding endpoint is unreachable
+type Fallback struct {
+	Primary   Classifier
+	Secondary Classifier
+}
+
+func (f *Fallback) Name() string { return f.Primary.Name() }
+
+func (f *Fallback) Classify(texts []string) ([]Result, error) {
+	results, err := f.Primary.Classify(texts)
+	if err == nil {
+		return results, nil
+	}
+	log.Printf("Warning: %s classifier failed, falling back to %s: %v", f.Primary.Name(), f.Secondary.Name(), err)
+	return f.Secondary.Classify(texts)
+}
+
+var (
+	defaultOnce       sync.Once
+	defaultClassifier Classifier
+)
+
+// Default returns the classifier configured through the environment, built
+// once per process. It returns nil when semantic classification is disabled,
+// in which case callers keep using their keyword matching.
+func Default() Classifier {
+	defaultOnce.Do(func() {
+		defaultClassifier = FromEnv()
+		if defaultClassifier != nil {
+			log.Printf("Using %s classifier for AI relevance and model categories", defaultClassifier.Name())
+		}
+	})
+	return defaultClassifier
+}
+
+// FromEnv builds a classifier from LLM_NEWS_CLASSIFIER:
+//
+//	keyword (default)  keyword matching only, returns nil
+//	openai             embeddings from an OpenAI-compatible /embeddings endpoint
+//	stub               local hashed bag-of-words embeddings, no network needed
+//
+// Embedding classifiers fall back to keyword matching on errors.
+func FromEnv() Classifier {
+	var embedder Embedder
+	switch backend := strings.ToLower(strings.TrimSpace(os.Getenv("LLM_NEWS_CLASSIFIER"))); backend {
+	case "", "keyword":
+		return nil
+	case "openai":
+		e := &OpenAIEmbedder{
+			BaseURL: os.Getenv("LLM_NEWS_EMBEDDING_URL"),
+			APIKey:  os.Getenv("LLM_NEWS_EMBEDDING_API_KEY"),
+			Model:   os.Getenv("LLM_NEWS_EMBEDDING_MODEL"),
+		}
+		if e.APIKey == "" {
+			e.APIKey = os.Getenv("OPENAI_API_KEY")
+		}
+		embedder = e
+	case "stub":
+		embedder = &HashEmbedder{}
+	default:
+		log.Printf("Warning: Unknown classifier %q in LLM_NEWS_CLASSIFIER, using keyword matching", backend)
+		return nil
+	}
+
+	return &Fallback{
+		Primary:   NewEmbeddingClassifier(embedder, models.AIModelKeywords),
+		Secondary: NewKeywordClassifier(),
+	}
+}
@@ -0,0 +1,195 @@
+package classifier
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// fakeEmbedder maps each text to a fixed unit vector by prefix, so that the
+// expected similarities are known exactly
+type fakeEmbedder struct {
+	vectors map[string][]float64 // 文本前缀 -> 向量
+	err     error
+	calls   [][]string
+}
+
+func (f *fakeEmbedder) Embed(texts []string) ([][]float64, error) {
+	f.calls = append(f.calls, texts)
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := make([][]float64, len(texts))
+	for i, text := range texts {
+		result[i] = []float64{0, 0, 0, 0}
+		for prefix, vec := range f.vectors {
+			if strings.HasPrefix(text, prefix) {
+				result[i] = vec
+			}
+		}
+	}
+	return result, nil
+}
+
+var testCategories = map[string][]string{
+	"OpenAI": {"gpt"},
+	"Llama":  {"llama"},
+}
+
+func newFakeEmbedder() *fakeEmbedder {
+	return &fakeEmbedder{vectors: map[string][]float64{
+		// 分类原型
+		"OpenAI:":                 {1, 0, 0, 0},
+		"Llama:":                  {0, 1, 0, 0},
+		"artificial intelligence": {0, 0, 1, 0},
+		"web framework":           {0, 0, 0, 1},
+		// 待分类文本
+		"gpt":     {1, 0, 0, 0},
+		"llama":   {0, 1, 0, 0},
+		"agents":  {0, 0, 1, 0},
+		"css":     {0, 0, 0, 1},
+		"unknown": {0, 0, 0, 0},
+	}}
+}
+
+func TestEmbeddingClassifier(t *testing.T) {
+	embedder := newFakeEmbedder()
+	c := NewEmbeddingClassifier(embedder, testCategories)
+
+	texts := []string{"gpt wrapper", "llama runner", "agents toolkit", "css grid", "unknown"}
+	results, err := c.Classify(texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text   string
+		ai     bool
+		labels []string
+	}{
+		{"gpt wrapper", true, []string{"OpenAI"}},
+		{"llama runner", true, []string{"Llama"}},
+		{"agents toolkit", true, []string{OtherCategory}},
+		{"css grid", false, nil},
+		{"unknown", false, nil}, // 零向量与所有原型相似度都为0
+	}
+	for i, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			r := results[i]
+			if got := r.AI > AIThreshold; got != tt.ai {
+				t.Errorf("AI = %.3f, want above threshold %v", r.AI, tt.ai)
+			}
+			var sum float64
+			for _, p := range r.Categories {
+				sum += p
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("category probabilities sum to %f", sum)
+			}
+			if tt.labels != nil && !reflect.DeepEqual(r.Labels(CategoryThreshold), tt.labels) {
+				t.Errorf("Labels = %v, want %v", r.Labels(CategoryThreshold), tt.labels)
+			}
+		})
+	}
+
+	// 原型只嵌入一次，已分类的文本从缓存读取
+	if _, err := c.Classify([]string{"gpt wrapper", "llama 2"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(embedder.calls) != 3 || !reflect.DeepEqual(embedder.calls[2], []string{"llama 2"}) {
+		t.Errorf("unexpected Embed calls %q", embedder.calls)
+	}
+}
+
+func TestEmbeddingClassifierErrors(t *testing.T) {
+	c := NewEmbeddingClassifier(&fakeEmbedder{err: errors.New("connection refused")}, testCategories)
+	if _, err := c.Classify([]string{"gpt"}); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("got error %v, want the embedder error", err)
+	}
+
+	c = NewEmbeddingClassifier(shortEmbedder{}, testCategories)
+	if _, err := c.Classify([]string{"gpt"}); err == nil || !strings.Contains(err.Error(), "vectors for") {
+		t.Errorf("got error %v, want a vector count mismatch", err)
+	}
+}
+
+// shortEmbedder always returns one vector too few
+type shortEmbedder struct{}
+
+func (shortEmbedder) Embed(texts []string) ([][]float64, error) {
+	return make([][]float64, len(texts)-1), nil
+}
+
+// TestHashEmbedder runs the local stand-in embedder through the real
+// categories to check that it separates obvious cases
+func TestHashEmbedder(t *testing.T) {
+	c := NewEmbeddingClassifier(&HashEmbedder{}, models.AIModelKeywords)
+	results, err := c.Classify([]string{
+		"deep learning neural networks for large language models",
+		"kubernetes deployment devops command line tool",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].AI <= AIThreshold || results[1].AI >= AIThreshold {
+		t.Errorf("AI probabilities = %.3f, %.3f, want the first above and the second below %.1f",
+			results[0].AI, results[1].AI, AIThreshold)
+	}
+
+	a, _ := (&HashEmbedder{Dim: 64}).Embed([]string{"Fine-tuning 大语言模型"})
+	b, _ := (&HashEmbedder{Dim: 64}).Embed([]string{"fine tuning 大语言模型"})
+	if len(a[0]) != 64 || !reflect.DeepEqual(a, b) {
+		t.Error("HashEmbedder is not deterministic over case and punctuation")
+	}
+}
+
+func TestHashTokens(t *testing.T) {
+	got := hashTokens("GPT-4 大语言模型, 中")
+	want := []string{"gpt", "4", "大语", "语言", "言模", "模型", "中"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hashTokens = %q, want %q", got, want)
+	}
+}
+
+func TestKeywordClassifier(t *testing.T) {
+	results, err := NewKeywordClassifier().Classify([]string{
+		"Chat UI for GPT-4 and Claude",
+		"A fast web framework",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := results[0].Labels(CategoryThreshold); results[0].AI != 1 || !reflect.DeepEqual(got, []string{"Claude", "OpenAI"}) {
+		t.Errorf("got AI %v and labels %v", results[0].AI, got)
+	}
+	if results[1].AI != 0 || !reflect.DeepEqual(results[1].Labels(CategoryThreshold), []string{OtherCategory}) {
+		t.Errorf("non-AI text classified as %+v", results[1])
+	}
+}
+
+// TestFallback checks that a failing embedding endpoint degrades to keyword
+// matching instead of failing the refresh
+func TestFallback(t *testing.T) {
+	embedder := &fakeEmbedder{err: errors.New("status 503")}
+	f := &Fallback{
+		Primary:   NewEmbeddingClassifier(embedder, models.AIModelKeywords),
+		Secondary: NewKeywordClassifier(),
+	}
+	if f.Name() != "embedding" {
+		t.Errorf("Name = %q", f.Name())
+	}
+
+	texts := []string{"Llama inference server", "Todo app"}
+	got, err := f.Classify(texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := NewKeywordClassifier().Classify(texts)
+	if len(embedder.calls) != 1 || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want the keyword results %+v", got, want)
+	}
+}
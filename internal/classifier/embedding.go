@@ -0,0 +1,348 @@
+package classifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// Embedder turns texts into vectors. Implementations may call a remote
+// OpenAI-compatible endpoint or run a local model.
+type Embedder interface {
+	Embed(texts []string) ([][]float64, error)
+}
+
+// aiPrototype describes the AI/ML domain as a whole. It is also the
+// prototype of OtherCategory: AI texts that are not about a specific model.
+var aiPrototype = "artificial intelligence, machine learning, deep learning, large language models, " +
+	"neural networks, generative AI, AI agents, retrieval augmented generation. " + strings.Join(models.AIKeywords, ", ")
+
+// nonAIPrototype describes software that is commonly trending but unrelated to AI
+const nonAIPrototype = "web framework, frontend UI components, CSS library, HTML templates, database, " +
+	"operating system, command line tool, shell scripts, terminal emulator, text editor theme, game, " +
+	"blockchain, cryptocurrency, networking, proxy, VPN, email client, URL shortener, devops, " +
+	"kubernetes deployment, awesome list of resources, interview questions, programming tutorial"
+
+// maxCacheEntries bounds the memory used by the classification cache
+const maxCacheEntries = 10000
+
+// EmbeddingClassifier classifies texts by cosine similarity between their
+// embeddings and prototype embeddings of the AI domain and of each model
+// category. Similarities are turned into probabilities with a softmax.
+type EmbeddingClassifier struct {
+	embedder    Embedder
+	categories  map[string][]string
+	Temperature float64 // softmax缩放系数，余弦相似度差异通常很小，需要放大
+
+	mu         sync.Mutex
+	names      []string    // 分类名，最后一个为OtherCategory
+	prototypes [][]float64 // 与names一一对应
+	nonAI      []float64
+	cache      map[string]Result
+}
+
+// NewEmbeddingClassifier creates a classifier for the given categories,
+// usually models.AIModelKeywords
+func NewEmbeddingClassifier(embedder Embedder, categories map[string][]string) *EmbeddingClassifier {
+	return &EmbeddingClassifier{
+		embedder:    embedder,
+		categories:  categories,
+		Temperature: 20,
+		cache:       make(map[string]Result),
+	}
+}
+
+func (e *EmbeddingClassifier) Name() string { return "embedding" }
+
+// Classify returns the AI and category probabilities of each text
+func (e *EmbeddingClassifier) Classify(texts []string) ([]Result, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.prototypes == nil {
+		if err := e.buildPrototypes(); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]Result, len(texts))
+	var pending []string
+	var pendingIdx []int
+	for i, text := range texts {
+		if cached, ok := e.cache[text]; ok {
+			results[i] = cached
+			continue
+		}
+		pending = append(pending, text)
+		pendingIdx = append(pendingIdx, i)
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	vectors, err := e.embedder.Embed(pending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed texts: %w", err)
+	}
+	if len(vectors) != len(pending) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(pending))
+	}
+
+	if len(e.cache)+len(pending) > maxCacheEntries {
+		e.cache = make(map[string]Result)
+	}
+	for j, vec := range vectors {
+		result := e.score(vec)
+		results[pendingIdx[j]] = result
+		e.cache[pending[j]] = result
+	}
+	return results, nil
+}
+
+// buildPrototypes embeds the category descriptions once
+func (e *EmbeddingClassifier) buildPrototypes() error {
+	var names, texts []string
+	for category, keywords := range e.categories {
+		names = append(names, category)
+		texts = append(texts, category+": "+strings.Join(keywords, ", "))
+	}
+	names = append(names, OtherCategory)
+	texts = append(texts, aiPrototype, nonAIPrototype)
+
+	vectors, err := e.embedder.Embed(texts)
+	if err != nil {
+		return fmt.Errorf("failed to embed category prototypes: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return fmt.Errorf("embedder returned %d vectors for %d prototypes", len(vectors), len(texts))
+	}
+
+	e.names = names
+	e.prototypes = vectors[:len(names)]
+	e.nonAI = vectors[len(names)]
+	return nil
+}
+
+func (e *EmbeddingClassifier) score(vec []float64) Result {
+	// 与任一分类或通用AI原型最接近的相似度，和非AI原型比较得到AI概率
+	sims := make([]float64, len(e.prototypes))
+	aiSim := math.Inf(-1)
+	for i, proto := range e.prototypes {
+		sims[i] = cosine(vec, proto)
+		aiSim = math.Max(aiSim, sims[i])
+	}
+	aiProbs := softmax([]float64{aiSim, cosine(vec, e.nonAI)}, e.Temperature)
+	probs := softmax(sims, e.Temperature)
+
+	result := Result{AI: aiProbs[0], Categories: make(map[string]float64, len(e.names))}
+	for i, name := range e.names {
+		result.Categories[name] = probs[i]
+	}
+	return result
+}
+
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func softmax(values []float64, temperature float64) []float64 {
+	maxValue := math.Inf(-1)
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v)
+	}
+	probs := make([]float64, len(values))
+	var sum float64
+	for i, v := range values {
+		probs[i] = math.Exp((v - maxValue) * temperature)
+		sum += probs[i]
+	}
+	for i := range probs {
+		probs[i] /= sum
+	}
+	return probs
+}
+
+// OpenAIEmbedder calls an OpenAI-compatible /embeddings endpoint. Local
+// servers such as Ollama, llama.cpp or text-embeddings-inference expose the
+// same API, so BaseURL can point at them instead.
+type OpenAIEmbedder struct {
+	BaseURL string // 默认 https://api.openai.com/v1
+	APIKey  string
+	Model   string // 默认 text-embedding-3-small
+}
+
+// embeddingBatchSize limits the number of inputs per request
+const embeddingBatchSize = 96
+
+func (o *OpenAIEmbedder) Embed(texts []string) ([][]float64, error) {
+	baseURL := strings.TrimRight(o.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	model := o.Model
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	vectors := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		end := start + embeddingBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		batch, err := o.embedBatch(client, baseURL+"/embeddings", model, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+func (o *OpenAIEmbedder) embedBatch(client *http.Client, endpoint, model string, texts []string) ([][]float64, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model": model,
+		"input": texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call embedding endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("embedding endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode embedding response: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding endpoint returned %d vectors for %d inputs", len(result.Data), len(texts))
+	}
+
+	vectors := make([][]float64, len(texts))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding endpoint returned invalid index %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
+
+// HashEmbedder is a local stand-in for a real embedding model. It hashes
+// word tokens (and CJK character bigrams) into a fixed-size vector, so texts
+// sharing whole words end up close together. It needs no network access and
+// is deterministic, which makes it useful offline and in tests.
+type HashEmbedder struct {
+	Dim int // 向量维度，默认512
+}
+
+func (h *HashEmbedder) Embed(texts []string) ([][]float64, error) {
+	dim := h.Dim
+	if dim <= 0 {
+		dim = 512
+	}
+
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vec := make([]float64, dim)
+		for _, token := range hashTokens(text) {
+			hasher := fnv.New64a()
+			hasher.Write([]byte(token))
+			sum := hasher.Sum64()
+			sign := 1.0
+			if sum&1 == 1 {
+				sign = -1
+			}
+			vec[(sum>>1)%uint64(dim)] += sign
+		}
+		vectors[i] = vec
+	}
+	return vectors, nil
+}
+
+// hashTokens splits text into lowercase words on non-alphanumeric runes.
+// Runs of Han characters are split into overlapping bigrams.
+func hashTokens(text string) []string {
+	var tokens []string
+	var word []rune
+	var han []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+	flushHan := func() {
+		if len(han) == 1 {
+			tokens = append(tokens, string(han))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			tokens = append(tokens, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return tokens
+}
@@ -0,0 +1,64 @@
+package digest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Archive stores rendered digests on disk as <date>-<period>.md/.html
+type Archive struct {
+	dir string
+}
+
+// NewArchive returns an archive rooted at dir, creating it if needed
+func NewArchive(dir string) (*Archive, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create digest archive: %w", err)
+	}
+	return &Archive{dir: dir}, nil
+}
+
+// Save renders the digest and writes both the Markdown and HTML versions
+func (a *Archive) Save(d Digest) error {
+	markdown, err := RenderMarkdown(d)
+	if err != nil {
+		return fmt.Errorf("failed to render markdown digest: %w", err)
+	}
+	html, err := RenderHTML(d)
+	if err != nil {
+		return fmt.Errorf("failed to render html digest: %w", err)
+	}
+
+	base := a.basePath(d.Date(), d.Period)
+	if err := os.WriteFile(base+".md", []byte(markdown), 0644); err != nil {
+		return fmt.Errorf("failed to archive digest: %w", err)
+	}
+	if err := os.WriteFile(base+".html", []byte(html), 0644); err != nil {
+		return fmt.Errorf("failed to archive digest: %w", err)
+	}
+	return nil
+}
+
+// LoadHTML returns the archived HTML digest for a date formatted as 2006-01-02
+func (a *Archive) LoadHTML(date string, period Period) ([]byte, error) {
+	return a.load(date, period, ".html")
+}
+
+// LoadMarkdown returns the archived Markdown digest for a date formatted as 2006-01-02
+func (a *Archive) LoadMarkdown(date string, period Period) ([]byte, error) {
+	return a.load(date, period, ".md")
+}
+
+func (a *Archive) load(date string, period Period, ext string) ([]byte, error) {
+	// 严格校验日期格式，防止路径穿越
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("invalid digest date %q", date)
+	}
+	return os.ReadFile(a.basePath(date, period) + ext)
+}
+
+func (a *Archive) basePath(date string, period Period) string {
+	return filepath.Join(a.dir, fmt.Sprintf("%s-%s", date, period))
+}
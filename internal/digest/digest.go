@@ -0,0 +1,187 @@
+package digest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/store"
+)
+
+// Period is the time span covered by a digest
+type Period string
+
+const (
+	Daily  Period = "daily"
+	Weekly Period = "weekly"
+)
+
+// maxItems limits every section of a digest
+const maxItems = 10
+
+// ParsePeriod converts a query value into a Period, defaulting to Daily
+func ParsePeriod(value string) Period {
+	if strings.EqualFold(strings.TrimSpace(value), string(Weekly)) {
+		return Weekly
+	}
+	return Daily
+}
+
+// Duration returns the length of the period
+func (p Period) Duration() time.Duration {
+	if p == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// RepoGain is a repository together with the stars it gained during the period
+type RepoGain struct {
+	Repo      models.Repository
+	StarDelta int
+}
+
+// Digest summarizes what changed between two snapshots
+type Digest struct {
+	Period     Period
+	From       time.Time
+	To         time.Time
+	NewRepos   []models.Repository // 本期新进入趋势榜的仓库
+	TopGainers []RepoGain          // 星标增长最多的仓库
+	TopPapers  []models.Paper      // 本期新增的高分论文
+
+	// ChartBaseURL is the public URL of the server. When set, repositories
+	// are shown with their star history sparkline served at /chart/.
+	ChartBaseURL string
+}
+
+// Date returns the archive key of the digest, the day it was generated
+func (d Digest) Date() string {
+	return d.To.Format("2006-01-02")
+}
+
+// Title returns a human readable title such as "LLM News daily digest 2026-10-18"
+func (d Digest) Title() string {
+	return fmt.Sprintf("LLM News %s digest %s", d.Period, d.Date())
+}
+
+// ChartURL returns the star history sparkline URL of a repository, or an
+// empty string when ChartBaseURL is not set
+func (d Digest) ChartURL(repo models.Repository) string {
+	if d.ChartBaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(d.ChartBaseURL, "/") + "/chart/" + models.RepoID(repo.Name) + ".svg"
+}
+
+// IsEmpty reports whether the digest has nothing to show
+func (d Digest) IsEmpty() bool {
+	return len(d.NewRepos) == 0 && len(d.TopGainers) == 0 && len(d.TopPapers) == 0
+}
+
+// FromStore builds the digest for the period ending at now, comparing the
+// latest snapshot with the one taken at the start of the period
+func FromStore(st *store.Store, period Period, now time.Time) (Digest, error) {
+	current, ok := st.At(now)
+	if !ok {
+		return Digest{}, fmt.Errorf("no snapshot available before %s", now.Format(time.RFC3339))
+	}
+
+	from := now.Add(-period.Duration())
+	base, ok := st.At(from)
+	if !ok {
+		// 历史不足一个周期时，使用周期内最早的快照作为基准
+		inRange := st.Between(from, now)
+		if len(inRange) > 0 {
+			base = inRange[0]
+		}
+	}
+
+	d := Build(period, base, current)
+	d.From = from
+	d.To = now
+	return d, nil
+}
+
+// Build compares two snapshots and collects new repositories, the biggest
+// star gainers and the best new papers
+func Build(period Period, base, current store.Snapshot) Digest {
+	d := Digest{
+		Period: period,
+		From:   base.TakenAt,
+		To:     current.TakenAt,
+	}
+
+	baseRepos := make(map[string]models.Repository, len(base.Repos))
+	for _, repo := range base.Repos {
+		baseRepos[strings.ToLower(repo.Name)] = repo
+	}
+
+	for _, repo := range current.Repos {
+		previous, existed := baseRepos[strings.ToLower(repo.Name)]
+		if !existed {
+			d.NewRepos = append(d.NewRepos, repo)
+		}
+
+		// 对比两次快照的星标数，新仓库则使用趋势榜上报告的增长数
+		delta := repo.GainedStars
+		if existed && previous.Stars > 0 && repo.Stars >= previous.Stars {
+			delta = repo.Stars - previous.Stars
+		}
+		if delta > 0 {
+			d.TopGainers = append(d.TopGainers, RepoGain{Repo: repo, StarDelta: delta})
+		}
+	}
+
+	sort.SliceStable(d.NewRepos, func(i, j int) bool {
+		return d.NewRepos[i].GainedStars > d.NewRepos[j].GainedStars
+	})
+	sort.SliceStable(d.TopGainers, func(i, j int) bool {
+		return d.TopGainers[i].StarDelta > d.TopGainers[j].StarDelta
+	})
+
+	basePapers := make(map[string]bool, len(base.Papers))
+	for _, paper := range base.Papers {
+		basePapers[paperKey(paper)] = true
+	}
+	for _, paper := range current.Papers {
+		if !basePapers[paperKey(paper)] {
+			d.TopPapers = append(d.TopPapers, paper)
+		}
+	}
+	sort.SliceStable(d.TopPapers, func(i, j int) bool {
+		return paperScore(d.TopPapers[i]) > paperScore(d.TopPapers[j])
+	})
+
+	d.NewRepos = limitRepos(d.NewRepos)
+	if len(d.TopGainers) > maxItems {
+		d.TopGainers = d.TopGainers[:maxItems]
+	}
+	if len(d.TopPapers) > maxItems {
+		d.TopPapers = d.TopPapers[:maxItems]
+	}
+
+	return d
+}
+
+// paperKey identifies a paper across snapshots
+func paperKey(paper models.Paper) string {
+	if paper.URL != "" {
+		return paper.URL
+	}
+	return strings.ToLower(strings.TrimSpace(paper.Title))
+}
+
+// paperScore ranks papers for the digest by novelty and citation velocity
+func paperScore(paper models.Paper) float64 {
+	return paper.NoveltyScore + paper.CitationVelocity
+}
+
+func limitRepos(repos []models.Repository) []models.Repository {
+	if len(repos) > maxItems {
+		return repos[:maxItems]
+	}
+	return repos
+}
@@ -0,0 +1,335 @@
+package digest
+
+import (
+	"bufio"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/store"
+)
+
+func testSnapshots() (store.Snapshot, store.Snapshot) {
+	day := time.Date(2026, 10, 17, 0, 30, 0, 0, time.UTC)
+	base := store.Snapshot{
+		TakenAt: day,
+		Repos: []models.Repository{
+			{Name: "ollama/ollama", URL: "https://github.com/ollama/ollama", Stars: 1000},
+			{Name: "old/leaving", URL: "https://github.com/old/leaving", Stars: 50},
+		},
+		Papers: []models.Paper{
+			{Title: "Known paper", URL: "https://arxiv.org/abs/1", NoveltyScore: 4.9},
+		},
+	}
+	current := store.Snapshot{
+		TakenAt: day.Add(24 * time.Hour),
+		Repos: []models.Repository{
+			{Name: "ollama/ollama", URL: "https://github.com/ollama/ollama", Stars: 1300},
+			{Name: "new/agent", URL: "https://github.com/new/agent", Stars: 800, GainedStars: 500, Description: "An AI agent"},
+			{Name: "new/small", URL: "https://github.com/new/small", Stars: 20, GainedStars: 5},
+		},
+		Papers: []models.Paper{
+			{Title: "Known paper", URL: "https://arxiv.org/abs/1", NoveltyScore: 4.9},
+			{Title: "Low novelty", URL: "https://arxiv.org/abs/2", NoveltyScore: 3.0, Authors: []string{"A"}},
+			{Title: "High novelty", URL: "https://arxiv.org/abs/3", NoveltyScore: 4.5, Authors: []string{"B", "C"}},
+		},
+	}
+	return base, current
+}
+
+func TestBuild(t *testing.T) {
+	base, current := testSnapshots()
+	d := Build(Daily, base, current)
+
+	if len(d.NewRepos) != 2 || d.NewRepos[0].Name != "new/agent" || d.NewRepos[1].Name != "new/small" {
+		t.Errorf("unexpected new repos: %+v", d.NewRepos)
+	}
+
+	wantGainers := []struct {
+		name  string
+		delta int
+	}{{"new/agent", 500}, {"ollama/ollama", 300}, {"new/small", 5}}
+	if len(d.TopGainers) != len(wantGainers) {
+		t.Fatalf("got %d gainers, want %d", len(d.TopGainers), len(wantGainers))
+	}
+	for i, want := range wantGainers {
+		if d.TopGainers[i].Repo.Name != want.name || d.TopGainers[i].StarDelta != want.delta {
+			t.Errorf("gainer %d = %s +%d, want %s +%d", i, d.TopGainers[i].Repo.Name, d.TopGainers[i].StarDelta, want.name, want.delta)
+		}
+	}
+
+	if len(d.TopPapers) != 2 || d.TopPapers[0].Title != "High novelty" {
+		t.Errorf("unexpected top papers: %+v", d.TopPapers)
+	}
+	if d.Date() != "2026-10-18" {
+		t.Errorf("Date() = %s, want 2026-10-18", d.Date())
+	}
+}
+
+func TestFromStore(t *testing.T) {
+	st, err := store.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromStore(st, Daily, time.Now()); err == nil {
+		t.Error("expected an error for an empty store")
+	}
+
+	base, current := testSnapshots()
+	for _, snap := range []store.Snapshot{base, current} {
+		if err := st.Save(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := FromStore(st, Daily, current.TakenAt.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("FromStore returned error: %v", err)
+	}
+	if len(d.NewRepos) != 2 {
+		t.Errorf("got %d new repos, want 2", len(d.NewRepos))
+	}
+}
+
+func TestRender(t *testing.T) {
+	base, current := testSnapshots()
+	d := Build(Weekly, base, current)
+
+	markdown, err := RenderMarkdown(d)
+	if err != nil {
+		t.Fatalf("RenderMarkdown returned error: %v", err)
+	}
+	for _, want := range []string{
+		"# LLM News weekly digest 2026-10-18",
+		"## New trending repositories",
+		"- [new/agent](https://github.com/new/agent) ★ 800 (+500)",
+		"- [ollama/ollama](https://github.com/ollama/ollama) +300 ★ (total 1300)",
+		"- [High novelty](https://arxiv.org/abs/3)",
+		"B, C",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("markdown digest is missing %q:\n%s", want, markdown)
+		}
+	}
+
+	html, err := RenderHTML(d)
+	if err != nil {
+		t.Fatalf("RenderHTML returned error: %v", err)
+	}
+	if !strings.Contains(html, `<a href="https://github.com/new/agent">new/agent</a>`) {
+		t.Errorf("html digest is missing the new repository:\n%s", html)
+	}
+
+	empty, err := RenderMarkdown(Digest{Period: Daily, To: current.TakenAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(empty, "Nothing new this day.") {
+		t.Errorf("empty digest should say so:\n%s", empty)
+	}
+}
+
+func TestRenderCharts(t *testing.T) {
+	base, current := testSnapshots()
+	d := Build(Daily, base, current)
+	if strings.Contains(mustRenderHTML(t, d), "/chart/") {
+		t.Error("charts should only be embedded when ChartBaseURL is set")
+	}
+
+	d.ChartBaseURL = "https://news.example.com/"
+	if html := mustRenderHTML(t, d); !strings.Contains(html, `<img class="chart" src="https://news.example.com/chart/new/agent.svg"`) {
+		t.Errorf("html digest is missing the sparkline:\n%s", html)
+	}
+	markdown, err := RenderMarkdown(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(markdown, "(total 1300) ![star history](https://news.example.com/chart/ollama/ollama.svg)") {
+		t.Errorf("markdown digest is missing the sparkline:\n%s", markdown)
+	}
+}
+
+func mustRenderHTML(t *testing.T, d Digest) string {
+	t.Helper()
+	html, err := RenderHTML(d)
+	if err != nil {
+		t.Fatalf("RenderHTML returned error: %v", err)
+	}
+	return html
+}
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := NewArchive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, current := testSnapshots()
+	d := Build(Daily, base, current)
+	if err := archive.Save(d); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "2026-10-18-daily.md")); err != nil {
+		t.Errorf("markdown digest was not archived: %v", err)
+	}
+	html, err := archive.LoadHTML("2026-10-18", Daily)
+	if err != nil || !strings.Contains(string(html), "new/agent") {
+		t.Errorf("LoadHTML = %q, %v", html, err)
+	}
+	if _, err := archive.LoadHTML("2026-10-18", Weekly); err == nil {
+		t.Error("expected an error for a missing weekly digest")
+	}
+	if _, err := archive.LoadHTML("../../etc/passwd", Daily); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+}
+
+// fakeSMTPServer is a minimal SMTP stand-in that records one message
+type fakeSMTPServer struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     chan string
+}
+
+func startFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := &fakeSMTPServer{listener: listener, data: make(chan string, 1)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost fake SMTP")
+
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimSpace(line)
+			upper := strings.ToUpper(cmd)
+			switch {
+			case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(upper, "MAIL FROM:"):
+				srv.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+				reply("250 OK")
+			case strings.HasPrefix(upper, "RCPT TO:"):
+				srv.to = append(srv.to, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+				reply("250 OK")
+			case upper == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var body strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					body.WriteString(dataLine)
+				}
+				srv.data <- body.String()
+				reply("250 OK")
+			case upper == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	return srv
+}
+
+func TestMailerSend(t *testing.T) {
+	srv := startFakeSMTPServer(t)
+	host, portStr, _ := net.SplitHostPort(srv.listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	mailer := NewMailer(SMTPConfig{
+		Host: host,
+		Port: port,
+		From: "digest@example.com",
+		To:   []string{"team@example.com", "lead@example.com"},
+	})
+
+	base, current := testSnapshots()
+	if err := mailer.Send(Build(Daily, base, current)); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	var message string
+	select {
+	case message = <-srv.data:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fake SMTP server did not receive a message")
+	}
+
+	if srv.from != "digest@example.com" {
+		t.Errorf("MAIL FROM = %q", srv.from)
+	}
+	if strings.Join(srv.to, ",") != "team@example.com,lead@example.com" {
+		t.Errorf("RCPT TO = %v", srv.to)
+	}
+	for _, want := range []string{
+		"Subject: LLM News daily digest 2026-10-18",
+		"Content-Type: multipart/alternative",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Type: text/html; charset=utf-8",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message is missing %q", want)
+		}
+	}
+
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(message)))
+	if err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	if !strings.Contains(string(decoded), "[new/agent](https://github.com/new/agent)") {
+		t.Errorf("message does not contain the markdown digest:\n%s", decoded)
+	}
+}
+
+func TestSMTPConfigFromEnv(t *testing.T) {
+	t.Setenv("LLM_NEWS_SMTP_HOST", "")
+	t.Setenv("LLM_NEWS_DIGEST_RECIPIENTS", "")
+	if _, ok := SMTPConfigFromEnv(); ok {
+		t.Error("expected SMTP to be disabled without a host")
+	}
+
+	t.Setenv("LLM_NEWS_SMTP_HOST", "smtp.example.com")
+	t.Setenv("LLM_NEWS_SMTP_PORT", "2525")
+	t.Setenv("LLM_NEWS_SMTP_USERNAME", "bot@example.com")
+	t.Setenv("LLM_NEWS_SMTP_FROM", "")
+	t.Setenv("LLM_NEWS_DIGEST_RECIPIENTS", "a@example.com, b@example.com")
+	cfg, ok := SMTPConfigFromEnv()
+	if !ok {
+		t.Fatal("expected SMTP to be enabled")
+	}
+	if cfg.Port != 2525 || cfg.From != "bot@example.com" || len(cfg.To) != 2 {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+}
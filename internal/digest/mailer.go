@@ -0,0 +1,130 @@
+package digest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig holds the settings used to deliver digests by email
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// SMTPConfigFromEnv reads the SMTP settings from LLM_NEWS_SMTP_* variables.
+// The second return value is false when no host or recipient is configured.
+func SMTPConfigFromEnv() (SMTPConfig, bool) {
+	cfg := SMTPConfig{
+		Host:     strings.TrimSpace(os.Getenv("LLM_NEWS_SMTP_HOST")),
+		Port:     587,
+		Username: os.Getenv("LLM_NEWS_SMTP_USERNAME"),
+		Password: os.Getenv("LLM_NEWS_SMTP_PASSWORD"),
+		From:     strings.TrimSpace(os.Getenv("LLM_NEWS_SMTP_FROM")),
+	}
+	if port, err := strconv.Atoi(os.Getenv("LLM_NEWS_SMTP_PORT")); err == nil && port > 0 {
+		cfg.Port = port
+	}
+	for _, addr := range strings.Split(os.Getenv("LLM_NEWS_DIGEST_RECIPIENTS"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			cfg.To = append(cfg.To, addr)
+		}
+	}
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+
+	return cfg, cfg.Host != "" && len(cfg.To) > 0 && cfg.From != ""
+}
+
+// Mailer delivers rendered digests over SMTP
+type Mailer struct {
+	cfg SMTPConfig
+}
+
+// NewMailer creates a mailer for the given SMTP settings
+func NewMailer(cfg SMTPConfig) *Mailer {
+	return &Mailer{cfg: cfg}
+}
+
+// Send renders the digest and mails it to every configured recipient as a
+// multipart/alternative message with Markdown and HTML parts
+func (m *Mailer) Send(d Digest) error {
+	text, err := RenderMarkdown(d)
+	if err != nil {
+		return fmt.Errorf("failed to render markdown digest: %w", err)
+	}
+	html, err := RenderHTML(d)
+	if err != nil {
+		return fmt.Errorf("failed to render html digest: %w", err)
+	}
+
+	msg, err := buildMessage(m.cfg.From, m.cfg.To, d.Title(), text, html)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	if err := smtp.SendMail(addr, auth, m.cfg.From, m.cfg.To, msg); err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+	return nil
+}
+
+// buildMessage assembles an RFC 5322 message with text and HTML alternatives
+func buildMessage(from string, to []string, subject, text, html string) ([]byte, error) {
+	var boundaryBytes [12]byte
+	if _, err := rand.Read(boundaryBytes[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate MIME boundary: %w", err)
+	}
+	boundary := "llmnews-" + hex.EncodeToString(boundaryBytes[:])
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
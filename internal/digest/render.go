@@ -0,0 +1,41 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var (
+	markdownTemplate = texttemplate.Must(texttemplate.New("digest.md.tmpl").
+				Funcs(texttemplate.FuncMap{"join": strings.Join}).
+				ParseFS(templateFS, "templates/digest.md.tmpl"))
+
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("digest.html.tmpl").
+			Funcs(htmltemplate.FuncMap{"join": strings.Join}).
+			ParseFS(templateFS, "templates/digest.html.tmpl"))
+)
+
+// RenderMarkdown renders the digest as Markdown
+func RenderMarkdown(d Digest) (string, error) {
+	var buf bytes.Buffer
+	if err := markdownTemplate.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderHTML renders the digest as a standalone HTML page, suitable for
+// both email bodies and the /digest/:date archive
+func RenderHTML(d Digest) (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,262 @@
+// Package evaluate measures the filtering, categorization and ranking quality
+// of the scrapers against a labeled fixture set, so changes to keyword lists
+// and weights can be compared and regression tested.
+package evaluate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/papers"
+	"github.com/gerryyang2025/llm-news/internal/ranking"
+	"github.com/gerryyang2025/llm-news/internal/scrapers"
+)
+
+// DefaultK is the number of results NDCG is computed over
+const DefaultK = 10
+
+// LabeledRepo is a repository with its expected classification
+type LabeledRepo struct {
+	Repo       models.Repository `json:"repo"`
+	Relevant   bool              `json:"relevant"`
+	Categories []string          `json:"categories,omitempty"` // 期望的模型分类，为空时不参与分类评估
+	Grade      int               `json:"grade,omitempty"`      // 排序用的相关度0-3，相关但未填写时按1计算
+}
+
+// LabeledPaper is a paper or article with its expected classification
+type LabeledPaper struct {
+	Paper    models.Paper `json:"paper"`
+	Relevant bool         `json:"relevant"`
+	Grade    int          `json:"grade,omitempty"`
+}
+
+// Fixtures is a labeled data set. Now is the time the data was captured,
+// used instead of the current time so results do not drift.
+type Fixtures struct {
+	Now    time.Time      `json:"now"`
+	Repos  []LabeledRepo  `json:"repos"`
+	Papers []LabeledPaper `json:"papers"`
+}
+
+// Report holds the results of an evaluation run
+type Report struct {
+	K            int                `json:"k"`
+	Repos        int                `json:"repos"`
+	Papers       int                `json:"papers"`
+	RepoKeywords Metrics            `json:"repo_keywords"` // 关键词或语义分类器过滤
+	RepoFilter   Metrics            `json:"repo_filter"`   // 再加上DefaultFilterCriteria
+	Categories   Metrics            `json:"categories"`    // 按(仓库, 分类)对计算
+	PaperFilter  Metrics            `json:"paper_filter"`
+	RepoNDCG     map[string]float64 `json:"repo_ndcg"` // 按排序策略索引
+	PaperNDCG    float64            `json:"paper_ndcg"`
+}
+
+// Load reads a fixture file
+func Load(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	var f Fixtures
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures %s: %w", path, err)
+	}
+	if f.Now.IsZero() {
+		return nil, fmt.Errorf("fixtures %s have no \"now\" time", path)
+	}
+	return &f, nil
+}
+
+// LoadReport reads a report saved with -json, used as a baseline
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// Run evaluates the filters, the model categories and every ranking
+// strategy over the fixtures, computing NDCG over the first k results
+func Run(f *Fixtures, k int) Report {
+	if k <= 0 {
+		k = DefaultK
+	}
+	report := Report{
+		K:        k,
+		Repos:    len(f.Repos),
+		Papers:   len(f.Papers),
+		RepoNDCG: make(map[string]float64),
+	}
+
+	repos := make([]models.Repository, len(f.Repos))
+	labels := make(map[string]LabeledRepo, len(f.Repos))
+	for i, l := range f.Repos {
+		repos[i] = l.Repo
+		repos[i].ModelCategories = nil
+		labels[l.Repo.Name] = l
+	}
+	scrapers.CalculateRelevanceScores(repos, f.Now)
+
+	// 过滤：与抓取流程相同，先按关键词或分类器过滤，再应用过滤条件
+	kept := make(map[string]models.Repository)
+	for _, repo := range scrapers.FilterAIRepos(repos) {
+		kept[repo.Name] = repo
+	}
+	criteria := models.DefaultFilterCriteria()
+	for _, repo := range repos {
+		label := labels[repo.Name]
+		filtered, ok := kept[repo.Name]
+		report.RepoKeywords.Add(ok, label.Relevant)
+		report.RepoFilter.Add(ok && criteria.MatchesAt(repo, f.Now), label.Relevant)
+
+		if len(label.Categories) == 0 {
+			continue
+		}
+		if !ok {
+			filtered = repo
+		}
+		predicted := filtered.GetModelCategories()
+		for _, category := range union(predicted, label.Categories) {
+			report.Categories.Add(contains(predicted, category), contains(label.Categories, category))
+		}
+	}
+	report.RepoKeywords.finish()
+	report.RepoFilter.finish()
+	report.Categories.finish()
+
+	for _, name := range ranking.Names() {
+		r, _ := ranking.Get(name)
+		var grades []int
+		for _, repo := range ranking.Rank(r, repos, f.Now) {
+			grades = append(grades, labels[repo.Name].grade())
+		}
+		report.RepoNDCG[name] = NDCG(grades, k)
+	}
+
+	ranked := make([]LabeledPaper, len(f.Papers))
+	copy(ranked, f.Papers)
+	for _, l := range ranked {
+		text := l.Paper.Title + " " + l.Paper.Summary + " " + strings.Join(l.Paper.Keywords, " ")
+		report.PaperFilter.Add(papers.IsAIRelated(text), l.Relevant)
+	}
+	report.PaperFilter.finish()
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return papers.RankingScore(ranked[i].Paper, f.Now) > papers.RankingScore(ranked[j].Paper, f.Now)
+	})
+	var grades []int
+	for _, l := range ranked {
+		grades = append(grades, gradeOf(l.Relevant, l.Grade))
+	}
+	report.PaperNDCG = NDCG(grades, k)
+
+	return report
+}
+
+// Compare returns the metrics of current that are more than tolerance below
+// the baseline. Ranking strategies missing from the baseline are skipped.
+func Compare(baseline, current Report, tolerance float64) []string {
+	var regressions []string
+	check := func(name string, before, after float64) {
+		if after < before-tolerance {
+			regressions = append(regressions, fmt.Sprintf("%s: %.4f -> %.4f", name, before, after))
+		}
+	}
+	metrics := []struct {
+		name            string
+		before, current Metrics
+	}{
+		{"repo_keywords", baseline.RepoKeywords, current.RepoKeywords},
+		{"repo_filter", baseline.RepoFilter, current.RepoFilter},
+		{"categories", baseline.Categories, current.Categories},
+		{"paper_filter", baseline.PaperFilter, current.PaperFilter},
+	}
+	for _, m := range metrics {
+		check(m.name+" precision", m.before.Precision, m.current.Precision)
+		check(m.name+" recall", m.before.Recall, m.current.Recall)
+	}
+	for _, name := range ranking.Names() {
+		if before, ok := baseline.RepoNDCG[name]; ok {
+			check("repo_ndcg "+name, before, current.RepoNDCG[name])
+		}
+	}
+	check("paper_ndcg", baseline.PaperNDCG, current.PaperNDCG)
+	return regressions
+}
+
+// WriteText writes the report as aligned tables
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Fixtures: %d repositories, %d papers\n\n", r.Repos, r.Papers)
+	fmt.Fprintln(tw, "Classification\tTP\tFP\tFN\tPrecision\tRecall\tF1")
+	rows := []struct {
+		name string
+		m    Metrics
+	}{
+		{"repo keywords", r.RepoKeywords},
+		{"repo filter", r.RepoFilter},
+		{"model categories", r.Categories},
+		{"paper filter", r.PaperFilter},
+	}
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.3f\t%.3f\t%.3f\n", row.name, row.m.TP, row.m.FP, row.m.FN, row.m.Precision, row.m.Recall, row.m.F1)
+	}
+
+	fmt.Fprintf(tw, "\nRanking\tNDCG@%d\n", r.K)
+	names := make([]string, 0, len(r.RepoNDCG))
+	for name := range r.RepoNDCG {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(tw, "repos (%s)\t%.3f\n", name, r.RepoNDCG[name])
+	}
+	fmt.Fprintf(tw, "papers\t%.3f\n", r.PaperNDCG)
+	return tw.Flush()
+}
+
+func (l LabeledRepo) grade() int {
+	return gradeOf(l.Relevant, l.Grade)
+}
+
+func gradeOf(relevant bool, grade int) int {
+	if !relevant {
+		return 0
+	}
+	if grade <= 0 {
+		return 1
+	}
+	return grade
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// union returns the values of a followed by those of b not in a
+func union(a, b []string) []string {
+	result := append([]string(nil), a...)
+	for _, v := range b {
+		if !contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package evaluate
+
+import (
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name                  string
+		decisions             [][2]bool // predicted, actual
+		precision, recall, f1 float64
+	}{
+		{"perfect", [][2]bool{{true, true}, {false, false}}, 1, 1, 1},
+		{"mixed", [][2]bool{{true, true}, {true, true}, {true, false}, {false, true}}, 0.6667, 0.6667, 0.6667},
+		{"no predictions", [][2]bool{{false, true}}, 1, 0, 0},
+		{"nothing relevant", [][2]bool{{false, false}}, 1, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Metrics
+			for _, d := range tt.decisions {
+				m.Add(d[0], d[1])
+			}
+			m.finish()
+			if m.Precision != tt.precision || m.Recall != tt.recall || m.F1 != tt.f1 {
+				t.Errorf("got %+v, want precision %v recall %v f1 %v", m, tt.precision, tt.recall, tt.f1)
+			}
+		})
+	}
+}
+
+func TestNDCG(t *testing.T) {
+	tests := []struct {
+		name   string
+		grades []int
+		k      int
+		want   float64
+	}{
+		{"ideal order", []int{3, 2, 0, 0}, 10, 1},
+		{"reversed", []int{0, 0, 2, 3}, 10, 0.5077},
+		{"cut off at k", []int{0, 3, 3}, 1, 0},
+		{"nothing relevant", []int{0, 0}, 10, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NDCG(tt.grades, tt.k); got != tt.want {
+				t.Errorf("NDCG(%v, %d) = %v, want %v", tt.grades, tt.k, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := Report{
+		RepoKeywords: Metrics{Precision: 0.9, Recall: 0.8},
+		RepoNDCG:     map[string]float64{"weighted": 0.7},
+		PaperNDCG:    0.9,
+	}
+	current := baseline
+	current.RepoKeywords.Recall = 0.85 // 提升不算回退
+	current.RepoNDCG = map[string]float64{"weighted": 0.6, "velocity": 0.1}
+	current.PaperNDCG = 0.8995
+
+	got := Compare(baseline, current, 0.001)
+	if len(got) != 1 || got[0] != "repo_ndcg weighted: 0.7000 -> 0.6000" {
+		t.Errorf("Compare = %q", got)
+	}
+}
+
+// TestFixturesAgainstBaseline fails when a change to the keyword lists or
+// weights lowers a metric. Regenerate the baseline after intended changes:
+//
+//	go run ./cmd/evaluate -json > internal/evaluate/testdata/baseline.json
+func TestFixturesAgainstBaseline(t *testing.T) {
+	t.Setenv("LLM_NEWS_CLASSIFIER", "")
+
+	f, err := Load("testdata/labeled.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadReport("testdata/baseline.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := Run(f, baseline.K)
+	if report.Repos != baseline.Repos || report.Papers != baseline.Papers {
+		t.Fatalf("fixtures changed (%d repos, %d papers), regenerate the baseline", report.Repos, report.Papers)
+	}
+	for _, r := range Compare(*baseline, report, 0.001) {
+		t.Errorf("regression: %s", r)
+	}
+}
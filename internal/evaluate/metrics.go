@@ -0,0 +1,76 @@
+package evaluate
+
+import (
+	"math"
+	"sort"
+)
+
+// Metrics holds the confusion counts of a binary decision and the scores
+// derived from them
+type Metrics struct {
+	TP        int     `json:"tp"`
+	FP        int     `json:"fp"`
+	FN        int     `json:"fn"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+}
+
+// Add records one decision
+func (m *Metrics) Add(predicted, actual bool) {
+	switch {
+	case predicted && actual:
+		m.TP++
+	case predicted:
+		m.FP++
+	case actual:
+		m.FN++
+	}
+}
+
+// finish computes precision, recall and F1 from the counts. Without any
+// positive prediction precision is 1, without any positive label recall is 1.
+func (m *Metrics) finish() {
+	precision, recall := 1.0, 1.0
+	if m.TP+m.FP > 0 {
+		precision = float64(m.TP) / float64(m.TP+m.FP)
+	}
+	if m.TP+m.FN > 0 {
+		recall = float64(m.TP) / float64(m.TP+m.FN)
+	}
+	m.Precision, m.Recall, m.F1 = round(precision), round(recall), 0
+	if precision+recall > 0 {
+		m.F1 = round(2 * precision * recall / (precision + recall))
+	}
+}
+
+// NDCG returns the normalized discounted cumulative gain of the first k
+// results, given the relevance grade of every result in ranked order. It is
+// 1 when no result is relevant.
+func NDCG(grades []int, k int) float64 {
+	ideal := append([]int(nil), grades...)
+	sort.Sort(sort.Reverse(sort.IntSlice(ideal)))
+
+	best := dcg(ideal, k)
+	if best == 0 {
+		return 1
+	}
+	return round(dcg(grades, k) / best)
+}
+
+// dcg uses the exponential gain 2^grade - 1 so highly relevant results
+// weigh more than several marginal ones
+func dcg(grades []int, k int) float64 {
+	sum := 0.0
+	for i, g := range grades {
+		if k > 0 && i >= k {
+			break
+		}
+		sum += (math.Pow(2, float64(g)) - 1) / math.Log2(float64(i+2))
+	}
+	return sum
+}
+
+func round(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,88 @@
+// Package events fans out server events, such as a completed refresh, to
+// the clients of the live update stream.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	Refresh  = "refresh"  // 数据源刷新完成
+	Breakout = "breakout" // 新的突破仓库
+	Health   = "health"   // 数据源健康状态变化
+)
+
+// subscriberBuffer is the number of events queued for a slow subscriber
+// before further events are dropped for it
+const subscriberBuffer = 16
+
+// Event is one message of the stream
+type Event struct {
+	Type string
+	Time time.Time
+	Data interface{}
+}
+
+// Broker delivers every published event to all current subscribers.
+// Publishing never blocks: a subscriber whose buffer is full misses the
+// event instead of holding up the scheduler.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]bool
+	dropped     int
+}
+
+// NewBroker returns a broker without subscribers
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan Event]bool)}
+}
+
+// Subscribe returns a channel receiving the events published from now on.
+// Call Unsubscribe with it when done.
+func (b *Broker) Subscribe() chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[ch] = true
+	b.mu.Unlock()
+	return ch
+}
+
+// Unsubscribe stops the delivery to ch and closes it
+func (b *Broker) Unsubscribe(ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers[ch] {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Publish sends an event of type typ to every subscriber
+func (b *Broker) Publish(typ string, data interface{}) {
+	event := Event{Type: typ, Time: time.Now(), Data: data}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			b.dropped++
+		}
+	}
+}
+
+// Subscribers returns the number of current subscribers
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Dropped returns the number of events not delivered to full subscribers
+func (b *Broker) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
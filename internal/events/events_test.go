@@ -0,0 +1,44 @@
+package events
+
+import "testing"
+
+func TestBroker(t *testing.T) {
+	b := NewBroker()
+	first, second := b.Subscribe(), b.Subscribe()
+	if b.Subscribers() != 2 {
+		t.Fatalf("got %d subscribers, want 2", b.Subscribers())
+	}
+
+	b.Publish(Refresh, "repos")
+	for _, ch := range []chan Event{first, second} {
+		if e := <-ch; e.Type != Refresh || e.Data != "repos" || e.Time.IsZero() {
+			t.Errorf("got event %+v", e)
+		}
+	}
+
+	b.Unsubscribe(first)
+	if _, open := <-first; open {
+		t.Error("channel is still open after Unsubscribe")
+	}
+	b.Unsubscribe(first) // 重复取消订阅不应panic
+	b.Publish(Health, nil)
+	if e := <-second; e.Type != Health {
+		t.Errorf("got event %+v, want health", e)
+	}
+}
+
+// TestBrokerSlowSubscriber checks that a full subscriber does not block
+// Publish and only misses the overflowing events
+func TestBrokerSlowSubscriber(t *testing.T) {
+	b := NewBroker()
+	ch := b.Subscribe()
+	for i := 0; i < subscriberBuffer+5; i++ {
+		b.Publish(Refresh, i)
+	}
+	if b.Dropped() != 5 {
+		t.Errorf("dropped %d events, want 5", b.Dropped())
+	}
+	if e := <-ch; e.Data != 0 {
+		t.Errorf("first queued event = %v, want 0", e.Data)
+	}
+}
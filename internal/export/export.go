@@ -0,0 +1,208 @@
+// Package export writes repositories and papers as JSON, CSV, NDJSON,
+// Parquet or Markdown. CSV, NDJSON and Parquet rows use a fixed column schema
+// with nested fields flattened, so the output loads into spreadsheets and
+// pandas as is.
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// Output formats
+const (
+	JSON     = "json"
+	CSV      = "csv"
+	NDJSON   = "ndjson"
+	Parquet  = "parquet"
+	Markdown = "markdown"
+)
+
+// Formats lists the supported output formats
+func Formats() []string {
+	return []string{JSON, CSV, NDJSON, Parquet, Markdown}
+}
+
+// ContentType returns the MIME type of format
+func ContentType(format string) string {
+	switch format {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case NDJSON:
+		return "application/x-ndjson"
+	case Parquet:
+		return "application/vnd.apache.parquet"
+	case Markdown:
+		return "text/markdown; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// Column types
+const (
+	String = "string"
+	Int    = "int"
+	Float  = "float"
+	Bool   = "bool"
+	Time   = "time" // RFC 3339，零值输出为空
+	List   = "list" // 字符串列表，CSV中以";"分隔
+)
+
+// Column is one field of the flattened schema
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// repoColumn pairs a column with the function extracting its value
+type repoColumn struct {
+	Column
+	value func(r models.Repository) interface{}
+}
+
+type paperColumn struct {
+	Column
+	value func(p models.Paper) interface{}
+}
+
+// repoColumns is the repository schema. New columns are only ever appended,
+// so existing consumers keep working.
+var repoColumns = []repoColumn{
+	{Column{"id", String}, func(r models.Repository) interface{} { return r.ID }},
+	{Column{"name", String}, func(r models.Repository) interface{} { return r.Name }},
+	{Column{"url", String}, func(r models.Repository) interface{} { return r.URL }},
+	{Column{"description", String}, func(r models.Repository) interface{} { return r.Description }},
+	{Column{"language", String}, func(r models.Repository) interface{} { return r.Language }},
+	{Column{"stars", Int}, func(r models.Repository) interface{} { return r.Stars }},
+	{Column{"forks", Int}, func(r models.Repository) interface{} { return r.Forks }},
+	{Column{"gained_stars", Int}, func(r models.Repository) interface{} { return r.GainedStars }},
+	{Column{"gained_forks", Int}, func(r models.Repository) interface{} { return r.GainedForks }},
+	{Column{"stars_24h", Int}, func(r models.Repository) interface{} { return r.TrendMetrics.Stars24h }},
+	{Column{"forks_24h", Int}, func(r models.Repository) interface{} { return r.TrendMetrics.Forks24h }},
+	{Column{"views_7d", Int}, func(r models.Repository) interface{} { return r.TrendMetrics.Views7d }},
+	{Column{"relevance_score", Float}, func(r models.Repository) interface{} { return r.RelevanceScore }},
+	{Column{"breakout_velocity", Float}, func(r models.Repository) interface{} {
+		if r.Breakout == nil {
+			return 0.0
+		}
+		return r.Breakout.Velocity
+	}},
+	{Column{"tech_stack", List}, func(r models.Repository) interface{} { return r.TechStack }},
+	{Column{"model_categories", List}, func(r models.Repository) interface{} { return r.ModelCategories }},
+	{Column{"doc_quality", Float}, func(r models.Repository) interface{} {
+		if r.DocQuality == nil {
+			return 0.0
+		}
+		return r.DocQuality.Score
+	}},
+	{Column{"has_readme", Bool}, func(r models.Repository) interface{} { return r.HasReadme }},
+	{Column{"source", String}, func(r models.Repository) interface{} { return r.Source }},
+	{Column{"paper_url", String}, func(r models.Repository) interface{} { return r.PaperURL }},
+	{Column{"authors", List}, func(r models.Repository) interface{} { return r.Authors }},
+	{Column{"created_at", Time}, func(r models.Repository) interface{} { return r.CreatedAt }},
+	{Column{"last_commit", Time}, func(r models.Repository) interface{} { return r.LastCommit }},
+	{Column{"last_updated", Time}, func(r models.Repository) interface{} { return r.LastUpdated }},
+}
+
+// paperColumns is the paper schema, append-only like repoColumns
+var paperColumns = []paperColumn{
+	{Column{"id", String}, func(p models.Paper) interface{} { return p.ID }},
+	{Column{"title", String}, func(p models.Paper) interface{} { return p.Title }},
+	{Column{"url", String}, func(p models.Paper) interface{} { return p.URL }},
+	{Column{"authors", List}, func(p models.Paper) interface{} { return p.Authors }},
+	{Column{"published_date", Time}, func(p models.Paper) interface{} { return p.PublishedDate }},
+	{Column{"source", String}, func(p models.Paper) interface{} { return p.Source }},
+	{Column{"summary", String}, func(p models.Paper) interface{} { return p.Summary }},
+	{Column{"tldr", String}, func(p models.Paper) interface{} { return p.TLDR }},
+	{Column{"keywords", List}, func(p models.Paper) interface{} { return p.Keywords }},
+	{Column{"citation_count", Int}, func(p models.Paper) interface{} { return p.CitationCount }},
+	{Column{"citation_velocity", Float}, func(p models.Paper) interface{} { return p.CitationVelocity }},
+	{Column{"novelty_score", Float}, func(p models.Paper) interface{} { return p.NoveltyScore }},
+	{Column{"reproducibility_score", Float}, func(p models.Paper) interface{} { return p.ReproducibilityScore }},
+	{Column{"key_techniques", List}, func(p models.Paper) interface{} { return p.KeyTechniques }},
+}
+
+// RepoColumns returns the repository schema
+func RepoColumns() []Column {
+	columns := make([]Column, len(repoColumns))
+	for i, c := range repoColumns {
+		columns[i] = c.Column
+	}
+	return columns
+}
+
+// PaperColumns returns the paper schema
+func PaperColumns() []Column {
+	columns := make([]Column, len(paperColumns))
+	for i, c := range paperColumns {
+		columns[i] = c.Column
+	}
+	return columns
+}
+
+// RepoRow returns the values of repo in schema order
+func RepoRow(repo models.Repository) []interface{} {
+	row := make([]interface{}, len(repoColumns))
+	for i, c := range repoColumns {
+		row[i] = c.value(repo)
+	}
+	return row
+}
+
+// PaperRow returns the values of paper in schema order
+func PaperRow(paper models.Paper) []interface{} {
+	row := make([]interface{}, len(paperColumns))
+	for i, c := range paperColumns {
+		row[i] = c.value(paper)
+	}
+	return row
+}
+
+// formatValue renders a value as CSV text
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.UTC().Format(time.RFC3339)
+	case []string:
+		return strings.Join(x, ";")
+	}
+	return fmt.Sprint(v)
+}
+
+// jsonValue converts a value for NDJSON rows: zero times become null and
+// nil lists empty lists
+func jsonValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case time.Time:
+		if x.IsZero() {
+			return nil
+		}
+		return x.UTC().Format(time.RFC3339)
+	case []string:
+		if x == nil {
+			return []string{}
+		}
+	}
+	return v
+}
+
+// checkFormat returns an error for unsupported formats
+func checkFormat(format string) error {
+	for _, f := range Formats() {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported format %q, expected one of %s", format, strings.Join(Formats(), ", "))
+}
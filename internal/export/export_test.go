@@ -0,0 +1,191 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+var testRepos = []models.Repository{
+	{
+		ID:           "ollama/ollama",
+		Name:         "ollama/ollama",
+		URL:          "https://github.com/ollama/ollama",
+		Description:  "Get up and running with large language models | locally",
+		Stars:        128502,
+		GainedStars:  1021,
+		TechStack:    []string{"go", "llm"},
+		TrendMetrics: models.TrendMetrics{Stars24h: 1021},
+		Breakout:     &models.Breakout{Velocity: 1021.5},
+		CreatedAt:    time.Date(2023, 6, 26, 19, 27, 24, 0, time.UTC),
+	},
+	{ID: "owner/empty", Name: "owner/empty"},
+}
+
+var testPapers = []models.Paper{
+	{
+		ID:            "2302.13971",
+		Title:         "LLaMA: Open and Efficient Foundation Language Models",
+		URL:           "https://arxiv.org/abs/2302.13971",
+		Authors:       []string{"Hugo Touvron", "Thibaut Lavril", "Gautier Izacard", "Xavier Martinet"},
+		PublishedDate: time.Date(2023, 2, 27, 0, 0, 0, 0, time.UTC),
+		Source:        "Papers with Code",
+	},
+}
+
+// TestRepoSchema guards the column order consumers depend on. Append new
+// columns at the end instead of changing this list.
+func TestRepoSchema(t *testing.T) {
+	want := "id,name,url,description,language,stars,forks,gained_stars,gained_forks,stars_24h,forks_24h,views_7d," +
+		"relevance_score,breakout_velocity,tech_stack,model_categories,doc_quality,has_readme,source,paper_url,authors," +
+		"created_at,last_commit,last_updated"
+	var names []string
+	for _, c := range RepoColumns() {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("repository columns changed:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestWriteReposCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteRepos(&buf, CSV, testRepos); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want header and 2 rows", len(records))
+	}
+	row := make(map[string]string)
+	for i, name := range records[0] {
+		row[name] = records[1][i]
+	}
+	for column, want := range map[string]string{
+		"stars":             "128502",
+		"stars_24h":         "1021",
+		"breakout_velocity": "1021.5",
+		"tech_stack":        "go;llm",
+		"created_at":        "2023-06-26T19:27:24Z",
+		"last_commit":       "",
+		"has_readme":        "false",
+	} {
+		if row[column] != want {
+			t.Errorf("%s = %q, want %q", column, row[column], want)
+		}
+	}
+}
+
+func TestWriteReposNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteRepos(&buf, NDJSON, testRepos); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], `{"id":"ollama/ollama","name":"ollama/ollama",`) {
+		t.Errorf("keys are not in schema order: %s", lines[0])
+	}
+
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &row); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+	if row["created_at"] != nil {
+		t.Errorf("zero time should be null, got %v", row["created_at"])
+	}
+	if list, ok := row["tech_stack"].([]interface{}); !ok || len(list) != 0 {
+		t.Errorf("nil list should be [], got %v", row["tech_stack"])
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteRepos(&buf, Markdown, testRepos); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `models \| locally`) {
+		t.Errorf("pipe in a cell is not escaped:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WritePapers(&buf, Markdown, testPapers); err != nil {
+		t.Fatal(err)
+	}
+	want := "| 1 | [LLaMA: Open and Efficient Foundation Language Models](https://arxiv.org/abs/2302.13971) | Papers with Code | 2023-02-27 | Hugo Touvron, Thibaut Lavril, Gautier Izacard, et al. |"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("unexpected paper table:\n%s", buf.String())
+	}
+	if len(testPapers[0].Authors) != 4 {
+		t.Error("WritePapers modified the authors of the paper")
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteRepos(&buf, "xlsx", testRepos); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("nothing should be written, got %q", buf.String())
+	}
+}
+
+// TestParquet compares the output with a file verified with a Parquet reader.
+// Every WriteRows call is a row group, empty batches add none.
+func TestParquet(t *testing.T) {
+	var buf bytes.Buffer
+	rw, err := NewRowWriter(&buf, Parquet, RepoColumns())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rows := range [][][]interface{}{
+		{RepoRow(testRepos[0]), RepoRow(testRepos[1])},
+		nil,
+		{RepoRow(testRepos[0])},
+	} {
+		if err := rw.WriteRows(rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.Bytes()
+	if !bytes.HasPrefix(got, []byte("PAR1")) || !bytes.HasSuffix(got, []byte("PAR1")) {
+		t.Fatal("missing Parquet magic bytes")
+	}
+	path := filepath.Join("testdata", "golden", "repos.parquet")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Parquet output changed, check it with a Parquet reader and run with -update")
+	}
+}
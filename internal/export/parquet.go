@@ -0,0 +1,341 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Parquet enum values, see parquet.thrift of the format specification
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+	parquetRepeated = 2
+
+	convertedUTF8            = 0
+	convertedList            = 3
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageData = 0
+)
+
+// parquetMagic starts and ends every Parquet file
+const parquetMagic = "PAR1"
+
+// parquetWriter writes uncompressed Parquet files with PLAIN encoded values.
+// Every WriteRows call becomes one row group, so large exports are streamed
+// without holding the whole file in memory; only the footer is kept.
+type parquetWriter struct {
+	w         io.Writer
+	columns   []Column
+	offset    int64
+	numRows   int64
+	rowGroups [][]byte // 已编码的RowGroup元数据
+}
+
+func newParquetWriter(w io.Writer, columns []Column) (*parquetWriter, error) {
+	if _, err := io.WriteString(w, parquetMagic); err != nil {
+		return nil, fmt.Errorf("failed to write Parquet header: %w", err)
+	}
+	return &parquetWriter{w: w, columns: columns, offset: int64(len(parquetMagic))}, nil
+}
+
+// WriteRows writes rows as one row group
+func (p *parquetWriter) WriteRows(rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var group thriftWriter
+	group.listBegin(1, thriftStruct, len(p.columns))
+	var totalSize int64
+	for i, column := range p.columns {
+		page, numValues := encodeColumn(column, rows, i)
+
+		var header thriftWriter
+		header.i32(1, pageData)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.structBegin(5) // DataPageHeader
+		header.i32(1, int32(numValues))
+		header.i32(2, encodingPlain)
+		header.i32(3, encodingRLE)
+		header.i32(4, encodingRLE)
+		header.structEnd()
+		header.buf.WriteByte(0)
+
+		chunkOffset := p.offset
+		chunkSize := int64(header.buf.Len() + len(page))
+		if _, err := p.w.Write(header.buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write Parquet page: %w", err)
+		}
+		if _, err := p.w.Write(page); err != nil {
+			return fmt.Errorf("failed to write Parquet page: %w", err)
+		}
+		p.offset += chunkSize
+		totalSize += chunkSize
+
+		// ColumnChunk
+		group.structBegin(0)
+		group.i64(2, chunkOffset)
+		group.structBegin(3) // ColumnMetaData
+		group.i32(1, physicalType(column.Type))
+		group.listBegin(2, thriftI32, 2)
+		group.varint(zigzag(encodingPlain))
+		group.varint(zigzag(encodingRLE))
+		path := columnPath(column)
+		group.listBegin(3, thriftBinary, len(path))
+		for _, name := range path {
+			group.rawString(name)
+		}
+		group.i32(4, 0) // UNCOMPRESSED
+		group.i64(5, int64(numValues))
+		group.i64(6, chunkSize)
+		group.i64(7, chunkSize)
+		group.i64(9, chunkOffset)
+		group.structEnd()
+		group.structEnd()
+	}
+	group.i64(2, totalSize)
+	group.i64(3, int64(len(rows)))
+
+	p.rowGroups = append(p.rowGroups, group.buf.Bytes())
+	p.numRows += int64(len(rows))
+	return nil
+}
+
+// Close writes the footer. It does not close the underlying writer.
+func (p *parquetWriter) Close() error {
+	var meta thriftWriter
+	meta.i32(1, 1) // version
+
+	elements := 1
+	for _, column := range p.columns {
+		elements += len(columnPath(column))
+	}
+	meta.listBegin(2, thriftStruct, elements)
+	meta.structBegin(0)
+	meta.string(4, "schema")
+	meta.i32(5, int32(len(p.columns)))
+	meta.structEnd()
+	for _, column := range p.columns {
+		writeSchemaElements(&meta, column)
+	}
+
+	meta.i64(3, p.numRows)
+	meta.listBegin(4, thriftStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		// RowGroup在WriteRows中已编码好，只需补上结束符
+		meta.buf.Write(group)
+		meta.buf.WriteByte(0)
+	}
+	meta.string(6, "llm-news")
+	meta.buf.WriteByte(0)
+
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(meta.buf.Len()))
+	for _, b := range [][]byte{meta.buf.Bytes(), length[:], []byte(parquetMagic)} {
+		if _, err := p.w.Write(b); err != nil {
+			return fmt.Errorf("failed to write Parquet footer: %w", err)
+		}
+	}
+	return nil
+}
+
+// columnPath is the schema path of the leaf holding the values of column.
+// Lists use the standard three-level LIST layout.
+func columnPath(column Column) []string {
+	if column.Type == List {
+		return []string{column.Name, "list", "element"}
+	}
+	return []string{column.Name}
+}
+
+func physicalType(typ string) int32 {
+	switch typ {
+	case Int, Time:
+		return parquetInt64
+	case Float:
+		return parquetDouble
+	case Bool:
+		return parquetBoolean
+	}
+	return parquetByteArray
+}
+
+// writeSchemaElements writes the SchemaElements of column
+func writeSchemaElements(t *thriftWriter, column Column) {
+	switch column.Type {
+	case List:
+		t.structBegin(0)
+		t.i32(3, parquetRequired)
+		t.string(4, column.Name)
+		t.i32(5, 1)
+		t.i32(6, convertedList)
+		t.structBegin(10)
+		t.emptyStruct(3) // LIST
+		t.structEnd()
+		t.structEnd()
+
+		t.structBegin(0)
+		t.i32(3, parquetRepeated)
+		t.string(4, "list")
+		t.i32(5, 1)
+		t.structEnd()
+
+		t.structBegin(0)
+		t.i32(1, parquetByteArray)
+		t.i32(3, parquetRequired)
+		t.string(4, "element")
+		t.i32(6, convertedUTF8)
+		t.structBegin(10)
+		t.emptyStruct(1) // STRING
+		t.structEnd()
+		t.structEnd()
+
+	case Time:
+		t.structBegin(0)
+		t.i32(1, parquetInt64)
+		t.i32(3, parquetOptional)
+		t.string(4, column.Name)
+		t.i32(6, convertedTimestampMillis)
+		t.structBegin(10)
+		t.structBegin(8) // TIMESTAMP
+		t.bool(1, true)  // isAdjustedToUTC
+		t.structBegin(2)
+		t.emptyStruct(1) // MILLIS
+		t.structEnd()
+		t.structEnd()
+		t.structEnd()
+		t.structEnd()
+
+	default:
+		t.structBegin(0)
+		t.i32(1, physicalType(column.Type))
+		t.i32(3, parquetRequired)
+		t.string(4, column.Name)
+		if column.Type == String {
+			t.i32(6, convertedUTF8)
+			t.structBegin(10)
+			t.emptyStruct(1) // STRING
+			t.structEnd()
+		}
+		t.structEnd()
+	}
+}
+
+// encodeColumn encodes column i of rows as the body of a data page and
+// returns it with the number of level entries
+func encodeColumn(column Column, rows [][]interface{}, i int) ([]byte, int) {
+	var values bytes.Buffer
+	var repLevels, defLevels []int
+
+	switch column.Type {
+	case List:
+		for _, row := range rows {
+			list, _ := row[i].([]string)
+			if len(list) == 0 {
+				repLevels = append(repLevels, 0)
+				defLevels = append(defLevels, 0)
+				continue
+			}
+			for j, s := range list {
+				rep := 1
+				if j == 0 {
+					rep = 0
+				}
+				repLevels = append(repLevels, rep)
+				defLevels = append(defLevels, 1)
+				writeByteArray(&values, s)
+			}
+		}
+
+	case Time:
+		for _, row := range rows {
+			t, _ := row[i].(time.Time)
+			if t.IsZero() {
+				defLevels = append(defLevels, 0)
+				continue
+			}
+			defLevels = append(defLevels, 1)
+			binary.Write(&values, binary.LittleEndian, t.UnixMilli())
+		}
+
+	case Bool:
+		packed := make([]byte, (len(rows)+7)/8)
+		for j, row := range rows {
+			if b, _ := row[i].(bool); b {
+				packed[j/8] |= 1 << (j % 8)
+			}
+		}
+		values.Write(packed)
+
+	default:
+		for _, row := range rows {
+			switch v := row[i].(type) {
+			case int:
+				binary.Write(&values, binary.LittleEndian, int64(v))
+			case float64:
+				binary.Write(&values, binary.LittleEndian, math.Float64bits(v))
+			case string:
+				writeByteArray(&values, v)
+			default:
+				writeByteArray(&values, fmt.Sprint(v))
+			}
+		}
+	}
+
+	var page bytes.Buffer
+	if repLevels != nil {
+		writeLevels(&page, repLevels)
+	}
+	if defLevels != nil {
+		writeLevels(&page, defLevels)
+	}
+	page.Write(values.Bytes())
+
+	numValues := len(rows)
+	if defLevels != nil {
+		numValues = len(defLevels)
+	}
+	return page.Bytes(), numValues
+}
+
+func writeByteArray(buf *bytes.Buffer, s string) {
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(s)))
+	buf.Write(length[:])
+	buf.WriteString(s)
+}
+
+// writeLevels writes 0/1 levels with the RLE/bit-packing hybrid encoding,
+// as RLE runs of bit width 1, prefixed with their byte length
+func writeLevels(page *bytes.Buffer, levels []int) {
+	var runs bytes.Buffer
+	var b [binary.MaxVarintLen64]byte
+	for start := 0; start < len(levels); {
+		end := start
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		n := binary.PutUvarint(b[:], uint64(end-start)<<1)
+		runs.Write(b[:n])
+		runs.WriteByte(byte(levels[start]))
+		start = end
+	}
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(runs.Len()))
+	page.Write(length[:])
+	page.Write(runs.Bytes())
+}
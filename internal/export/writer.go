@@ -0,0 +1,238 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// markdownDescriptionLen bounds descriptions in Markdown tables
+const markdownDescriptionLen = 100
+
+// WriteRepos writes repos to w in format. JSON keeps the full API objects,
+// the other formats use the flattened schema of RepoColumns.
+func WriteRepos(w io.Writer, format string, repos []models.Repository) error {
+	if err := checkFormat(format); err != nil {
+		return err
+	}
+	rows := make([][]interface{}, len(repos))
+	for i, repo := range repos {
+		rows[i] = RepoRow(repo)
+	}
+
+	switch format {
+	case JSON:
+		return writeJSON(w, repos)
+	case CSV, NDJSON, Parquet:
+		return writeRows(w, format, RepoColumns(), rows)
+	}
+
+	header := []string{"#", "Repository", "Stars", "Gained", "Language", "Description"}
+	lines := make([][]string, len(repos))
+	for i, repo := range repos {
+		lines[i] = []string{
+			fmt.Sprint(i + 1),
+			fmt.Sprintf("[%s](%s)", repo.Name, repo.URL),
+			fmt.Sprint(repo.Stars),
+			fmt.Sprint(repo.GainedStars),
+			repo.Language,
+			truncate(repo.Description, markdownDescriptionLen),
+		}
+	}
+	return writeMarkdown(w, header, lines)
+}
+
+// WritePapers writes papers to w in format, like WriteRepos
+func WritePapers(w io.Writer, format string, papers []models.Paper) error {
+	if err := checkFormat(format); err != nil {
+		return err
+	}
+	rows := make([][]interface{}, len(papers))
+	for i, paper := range papers {
+		rows[i] = PaperRow(paper)
+	}
+
+	switch format {
+	case JSON:
+		return writeJSON(w, papers)
+	case CSV, NDJSON, Parquet:
+		return writeRows(w, format, PaperColumns(), rows)
+	}
+
+	header := []string{"#", "Title", "Source", "Published", "Authors"}
+	lines := make([][]string, len(papers))
+	for i, paper := range papers {
+		published := ""
+		if !paper.PublishedDate.IsZero() {
+			published = paper.PublishedDate.UTC().Format("2006-01-02")
+		}
+		authors := paper.Authors
+		if len(authors) > 3 {
+			authors = append(authors[:3:3], "et al.")
+		}
+		lines[i] = []string{
+			fmt.Sprint(i + 1),
+			fmt.Sprintf("[%s](%s)", paper.Title, paper.URL),
+			paper.Source,
+			published,
+			strings.Join(authors, ", "),
+		}
+	}
+	return writeMarkdown(w, header, lines)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
+
+// RowWriter writes rows of a fixed schema in batches, so large exports are
+// streamed instead of built in memory
+type RowWriter interface {
+	WriteRows(rows [][]interface{}) error
+	// Close finishes the output, such as the Parquet footer
+	Close() error
+}
+
+// NewRowWriter returns a RowWriter for the CSV, NDJSON or Parquet format.
+// The CSV header is written immediately.
+func NewRowWriter(w io.Writer, format string, columns []Column) (RowWriter, error) {
+	switch format {
+	case CSV:
+		cw := csv.NewWriter(w)
+		header := make([]string, len(columns))
+		for i, c := range columns {
+			header[i] = c.Name
+		}
+		if err := cw.Write(header); err != nil {
+			return nil, fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		return &csvWriter{w: cw}, nil
+	case NDJSON:
+		return &ndjsonWriter{w: w, columns: columns}, nil
+	case Parquet:
+		return newParquetWriter(w, columns)
+	}
+	return nil, fmt.Errorf("format %q does not support row output", format)
+}
+
+// writeRows writes all rows through a RowWriter
+func writeRows(w io.Writer, format string, columns []Column, rows [][]interface{}) error {
+	rw, err := NewRowWriter(w, format, columns)
+	if err != nil {
+		return err
+	}
+	if err := rw.WriteRows(rows); err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (c *csvWriter) WriteRows(rows [][]interface{}) error {
+	for _, row := range rows {
+		if len(c.record) != len(row) {
+			c.record = make([]string, len(row))
+		}
+		for i, v := range row {
+			c.record[i] = formatValue(v)
+		}
+		if err := c.w.Write(c.record); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonWriter writes one JSON object per line with the keys in schema order
+type ndjsonWriter struct {
+	w       io.Writer
+	columns []Column
+	line    bytes.Buffer
+}
+
+func (n *ndjsonWriter) WriteRows(rows [][]interface{}) error {
+	bw := bufio.NewWriter(n.w)
+	for _, row := range rows {
+		n.line.Reset()
+		n.line.WriteByte('{')
+		for i, v := range row {
+			if i > 0 {
+				n.line.WriteByte(',')
+			}
+			key, _ := json.Marshal(n.columns[i].Name)
+			value, err := json.Marshal(jsonValue(v))
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", n.columns[i].Name, err)
+			}
+			n.line.Write(key)
+			n.line.WriteByte(':')
+			n.line.Write(value)
+		}
+		n.line.WriteString("}\n")
+		if _, err := bw.Write(n.line.Bytes()); err != nil {
+			return fmt.Errorf("failed to write NDJSON row: %w", err)
+		}
+	}
+	return bw.Flush()
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+func writeMarkdown(w io.Writer, header []string, lines [][]string) error {
+	bw := bufio.NewWriter(w)
+	writeRow := func(cells []string) {
+		bw.WriteString("|")
+		for _, cell := range cells {
+			bw.WriteString(" " + escapeCell(cell) + " |")
+		}
+		bw.WriteString("\n")
+	}
+	writeRow(header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeRow(separator)
+	for _, line := range lines {
+		writeRow(line)
+	}
+	return bw.Flush()
+}
+
+// escapeCell keeps a value on one table row
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
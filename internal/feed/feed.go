@@ -0,0 +1,309 @@
+package feed
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// Format identifies the syndication format used when rendering a feed
+type Format string
+
+const (
+	FormatRSS  Format = "rss"
+	FormatAtom Format = "atom"
+)
+
+// ParseFormat maps a query value such as "atom" or "rss" to a Format,
+// falling back to RSS 2.0 for anything unknown
+func ParseFormat(value string) Format {
+	if strings.EqualFold(strings.TrimSpace(value), string(FormatAtom)) {
+		return FormatAtom
+	}
+	return FormatRSS
+}
+
+// ContentType returns the MIME type for the format
+func (f Format) ContentType() string {
+	if f == FormatAtom {
+		return "application/atom+xml; charset=utf-8"
+	}
+	return "application/rss+xml; charset=utf-8"
+}
+
+// Item is a single entry of a feed, independent of the output format
+type Item struct {
+	GUID        string
+	Title       string
+	Link        string
+	Description string
+	Authors     []string
+	Categories  []string
+	Published   time.Time
+}
+
+// Feed is a format-neutral description of a feed
+type Feed struct {
+	Title       string
+	Link        string // 站点链接
+	SelfLink    string // 订阅地址本身
+	Description string
+	Updated     time.Time
+	Items       []Item
+}
+
+// Render writes the feed in the requested format
+func Render(w io.Writer, f Feed, format Format) error {
+	if format == FormatAtom {
+		return RenderAtom(w, f)
+	}
+	return RenderRSS(w, f)
+}
+
+// RepoGUID returns the stable identifier of a repository entry.
+// The GUID only depends on owner/name so that a repository staying on the
+// trending list across refreshes is not reported as a new item.
+func RepoGUID(repo models.Repository) string {
+	return "urn:llm-news:repo:" + strings.ToLower(repo.Name)
+}
+
+// PaperGUID returns the stable identifier of a paper entry, derived from
+// its URL (or title when the URL is missing)
+func PaperGUID(paper models.Paper) string {
+	key := strings.TrimSpace(paper.URL)
+	if key == "" {
+		key = strings.ToLower(strings.TrimSpace(paper.Title))
+	}
+	sum := sha1.Sum([]byte(key))
+	return "urn:llm-news:paper:" + hex.EncodeToString(sum[:])
+}
+
+// RepositoryItems converts repositories into feed items
+func RepositoryItems(repos []models.Repository) []Item {
+	items := make([]Item, 0, len(repos))
+	for i := range repos {
+		repo := repos[i]
+		desc := repo.Description
+		if repo.Stars > 0 || repo.GainedStars > 0 {
+			desc = fmt.Sprintf("%s\n\n★ %d (+%d)", desc, repo.Stars, repo.GainedStars)
+		}
+		if repo.Language != "" {
+			desc = fmt.Sprintf("%s · %s", desc, repo.Language)
+		}
+
+		items = append(items, Item{
+			GUID:        RepoGUID(repo),
+			Title:       repo.Name,
+			Link:        repo.URL,
+			Description: strings.TrimSpace(desc),
+			Authors:     []string{strings.Split(repo.Name, "/")[0]},
+			Categories:  repo.GetModelCategories(),
+			Published:   repo.LastUpdated,
+		})
+	}
+	return items
+}
+
+// PaperItems converts papers into feed items
+func PaperItems(papers []models.Paper) []Item {
+	items := make([]Item, 0, len(papers))
+	for _, paper := range papers {
+		items = append(items, Item{
+			GUID:        PaperGUID(paper),
+			Title:       paper.Title,
+			Link:        paper.URL,
+			Description: paper.Summary,
+			Authors:     paper.Authors,
+			Categories:  paper.Keywords,
+			Published:   paper.PublishedDate,
+		})
+	}
+	return items
+}
+
+// RSS 2.0 文档结构
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	AtomLink      *atomLink `xml:"atom:link,omitempty"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Creators    []string `xml:"dc:creator"` // RSS的author只能是邮箱，作者名用Dublin Core
+	Categories  []string `xml:"category"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RenderRSS writes the feed as an RSS 2.0 document
+func RenderRSS(w io.Writer, f Feed) error {
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+		},
+	}
+	if f.SelfLink != "" {
+		doc.Channel.AtomLink = &atomLink{Href: f.SelfLink, Rel: "self", Type: FormatRSS.ContentType()}
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, item := range f.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			Categories:  item.Categories,
+			GUID:        rssGUID{Value: item.GUID},
+		}
+		for _, author := range item.Authors {
+			if strings.TrimSpace(author) != "" {
+				entry.Creators = append(entry.Creators, author)
+			}
+		}
+		if !item.Published.IsZero() {
+			entry.PubDate = item.Published.UTC().Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, entry)
+	}
+
+	return writeXML(w, doc)
+}
+
+// Atom 1.0 文档结构
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Summary    string         `xml:"summary,omitempty"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// RenderAtom writes the feed as an Atom 1.0 document
+func RenderAtom(w io.Writer, f Feed) error {
+	updated := f.Updated
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	doc := atomFeed{
+		ID:       f.SelfLink,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Links:    []atomLink{{Href: f.Link, Rel: "alternate"}},
+	}
+	if doc.ID == "" {
+		doc.ID = f.Link
+	}
+	if f.SelfLink != "" {
+		doc.Links = append(doc.Links, atomLink{Href: f.SelfLink, Rel: "self", Type: FormatAtom.ContentType()})
+	}
+
+	for _, item := range f.Items {
+		// Atom要求每个条目都有updated，缺失时退回到feed的更新时间
+		entryUpdated := item.Published
+		if entryUpdated.IsZero() {
+			entryUpdated = updated
+		}
+
+		entry := atomEntry{
+			ID:      item.GUID,
+			Title:   item.Title,
+			Updated: entryUpdated.UTC().Format(time.RFC3339),
+			Summary: item.Description,
+		}
+		if !item.Published.IsZero() {
+			entry.Published = item.Published.UTC().Format(time.RFC3339)
+		}
+		if item.Link != "" {
+			entry.Links = []atomLink{{Href: item.Link, Rel: "alternate"}}
+		}
+		for _, author := range item.Authors {
+			if strings.TrimSpace(author) != "" {
+				entry.Authors = append(entry.Authors, atomPerson{Name: author})
+			}
+		}
+		// Atom条目必须有作者，或者由feed级别提供
+		if len(entry.Authors) == 0 {
+			entry.Authors = []atomPerson{{Name: "LLM News"}}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return writeXML(w, doc)
+}
+
+// writeXML encodes v with an XML declaration and indentation
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return enc.Flush()
+}
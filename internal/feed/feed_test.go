@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// TestGUIDs pins the GUIDs: feed readers use them to recognise items they
+// have already shown, so a change marks every item as new
+func TestGUIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"repo", RepoGUID(models.Repository{Name: "Meta-Llama/llama3", Stars: 1}), "urn:llm-news:repo:meta-llama/llama3"},
+		{"repo ignores stats", RepoGUID(models.Repository{Name: "meta-llama/Llama3", Stars: 99, Description: "changed"}), "urn:llm-news:repo:meta-llama/llama3"},
+		{"paper by url", PaperGUID(models.Paper{URL: "https://arxiv.org/abs/2302.13971", Title: "LLaMA"}), "urn:llm-news:paper:0e792eba0b1c00b360bf55e1d40aab7255af313f"},
+		{"paper url is trimmed", PaperGUID(models.Paper{URL: " https://arxiv.org/abs/2302.13971\n", Title: "Other"}), "urn:llm-news:paper:0e792eba0b1c00b360bf55e1d40aab7255af313f"},
+		{"paper by title", PaperGUID(models.Paper{Title: " Attention Is All You Need "}), PaperGUID(models.Paper{Title: "attention is all you need"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func testFeed() Feed {
+	published := time.Date(2026, 9, 1, 8, 30, 0, 0, time.UTC)
+	return Feed{
+		Title:       "LLM News - Test",
+		Link:        "https://example.com/",
+		SelfLink:    "https://example.com/feed/repos.xml",
+		Description: "Test feed",
+		Updated:     time.Date(2026, 9, 2, 0, 0, 0, 0, time.UTC),
+		Items: append(RepositoryItems([]models.Repository{{
+			Name:        "owner/repo",
+			URL:         "https://github.com/owner/repo",
+			Description: "A <fast> model server",
+			Language:    "Go",
+			Stars:       1200,
+			GainedStars: 30,
+			LastUpdated: published,
+		}}), PaperItems([]models.Paper{{
+			Title:   "A Paper",
+			URL:     "https://arxiv.org/abs/2401.00001",
+			Authors: []string{"Ada Lovelace", " ", "Alan Turing"},
+			Summary: "Summary & results",
+		}})...),
+	}
+}
+
+func TestRender(t *testing.T) {
+	for _, format := range []Format{FormatRSS, FormatAtom} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, testFeed(), format); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join("testdata", "golden", "feed."+string(format)+".xml")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("output changed:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for value, want := range map[string]Format{"atom": FormatAtom, " ATOM ": FormatAtom, "rss": FormatRSS, "": FormatRSS, "json": FormatRSS} {
+		if got := ParseFormat(value); got != want {
+			t.Errorf("ParseFormat(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
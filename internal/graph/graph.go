@@ -0,0 +1,368 @@
+// Package graph connects papers, repositories, Hugging Face models, model
+// categories and authors into a relationship graph. It is rebuilt from the
+// collected data on every refresh.
+package graph
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/readme"
+)
+
+// Node kinds
+const (
+	KindPaper    = "paper"
+	KindRepo     = "repo"
+	KindModel    = "model" // Hugging Face Hub上的模型、数据集或Space
+	KindCategory = "category"
+	KindAuthor   = "author"
+)
+
+// Edge kinds
+const (
+	EdgeImplements = "implements"  // 仓库或Hub模型 -> 论文
+	EdgeHosts      = "hosts"       // Hub模型 -> GitHub仓库
+	EdgeCategory   = "in_category" // 论文、仓库或Hub模型 -> 模型分类
+	EdgeAuthored   = "authored"    // 作者 -> 论文或仓库
+)
+
+// Node is a paper, repository, Hub item, model category or author
+type Node struct {
+	ID    string `json:"id"` // 如"paper:2401.01234"、"repo:owner/name"
+	Kind  string `json:"kind"`
+	Label string `json:"label"`
+	URL   string `json:"url,omitempty"`
+}
+
+// Edge is a directed relationship between two nodes
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Kind   string `json:"kind"`
+}
+
+// Graph is an immutable relationship graph
+type Graph struct {
+	nodes   map[string]*Node
+	order   []string // 节点插入顺序，保证导出结果稳定
+	edges   []Edge
+	edgeSet map[Edge]bool
+	out     map[string][]Edge
+	in      map[string][]Edge
+
+	repos  map[string]models.Repository
+	papers map[string]models.Paper
+}
+
+// Export is the JSON form of a graph used for visualization
+type Export struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// PaperNodeID returns the node ID of a paper, see models.PaperID
+func PaperNodeID(key string) string { return KindPaper + ":" + key }
+
+// RepoNodeID returns the node ID of a repository named owner/name
+func RepoNodeID(name string) string { return KindRepo + ":" + models.RepoID(name) }
+
+// githubRepoRe extracts owner/name from a GitHub URL
+var githubRepoRe = regexp.MustCompile(`github\.com/([\w.-]+/[\w.-]+)`)
+
+// Build creates the graph of the given data
+func Build(repos []models.Repository, papers []models.Paper, hubItems []models.HubItem) *Graph {
+	g := &Graph{
+		nodes:   make(map[string]*Node),
+		edgeSet: make(map[Edge]bool),
+		out:     make(map[string][]Edge),
+		in:      make(map[string][]Edge),
+		repos:   make(map[string]models.Repository),
+		papers:  make(map[string]models.Paper),
+	}
+
+	for _, p := range papers {
+		id := g.addPaper(p.URL, p.Title)
+		g.papers[id] = p
+		for _, category := range models.ModelCategoriesOf(p.Title + " " + p.Summary) {
+			g.link(id, g.addCategory(category), EdgeCategory)
+		}
+		for _, author := range p.Authors {
+			if a := g.addAuthor(author); a != "" {
+				g.link(a, id, EdgeAuthored)
+			}
+		}
+		// 摘要中常写明代码地址，如"Code is available at https://github.com/..."
+		for _, link := range readme.GitHubRepos(p.Summary) {
+			if name := repoName(link); name != "" {
+				g.link(g.addRepo(name, link), id, EdgeImplements)
+			}
+		}
+	}
+
+	for _, r := range repos {
+		id := g.addRepo(r.Name, r.URL)
+		g.repos[id] = r
+		for _, category := range r.GetModelCategories() {
+			g.link(id, g.addCategory(category), EdgeCategory)
+		}
+		for _, author := range r.Authors {
+			if a := g.addAuthor(author); a != "" {
+				g.link(a, id, EdgeAuthored)
+			}
+		}
+		// Papers with Code等来源给出的论文链接
+		if isPaperURL(r.PaperURL) {
+			g.link(id, g.addPaper(r.PaperURL, r.PaperTitle), EdgeImplements)
+		}
+		// README中引用的论文
+		if r.DocQuality != nil {
+			for _, link := range r.DocQuality.PaperLinks {
+				g.link(id, g.addPaper(link, ""), EdgeImplements)
+			}
+		}
+	}
+
+	for _, item := range hubItems {
+		id := KindModel + ":" + item.Kind + "/" + item.ID
+		g.addNode(Node{ID: id, Kind: KindModel, Label: item.ID, URL: item.URL})
+		for _, category := range models.ModelCategoriesOf(item.ID) {
+			g.link(id, g.addCategory(category), EdgeCategory)
+		}
+		if name := repoName(item.GitHubURL); name != "" {
+			g.link(id, g.addRepo(name, item.GitHubURL), EdgeHosts)
+		}
+		for _, link := range item.PaperURLs {
+			g.link(id, g.addPaper(link, ""), EdgeImplements)
+		}
+	}
+
+	return g
+}
+
+// paperHosts are the sites whose links are treated as papers
+var paperHosts = []string{"arxiv.org", "openreview.net", "aclanthology.org", "paperswithcode.com/paper", "proceedings.neurips.cc"}
+
+func isPaperURL(url string) bool {
+	for _, host := range paperHosts {
+		if strings.Contains(url, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func repoName(url string) string {
+	if m := githubRepoRe.FindStringSubmatch(url); m != nil {
+		return strings.TrimSuffix(m[1], ".git")
+	}
+	return ""
+}
+
+func (g *Graph) addNode(n Node) string {
+	if existing, ok := g.nodes[n.ID]; ok {
+		// 后出现的数据可能带有更完整的标题
+		if existing.Label == "" || existing.Label == strings.TrimPrefix(existing.ID, existing.Kind+":") {
+			if n.Label != "" {
+				existing.Label = n.Label
+			}
+		}
+		if existing.URL == "" {
+			existing.URL = n.URL
+		}
+		return n.ID
+	}
+	node := n
+	g.nodes[n.ID] = &node
+	g.order = append(g.order, n.ID)
+	return n.ID
+}
+
+func (g *Graph) addPaper(url, title string) string {
+	key := models.PaperID(url, title)
+	if title == "" {
+		title = key
+	}
+	if strings.HasPrefix(url, "https://arxiv.org/pdf/") || strings.HasPrefix(url, "http://arxiv.org/pdf/") {
+		url = "https://arxiv.org/abs/" + key
+	}
+	return g.addNode(Node{ID: PaperNodeID(key), Kind: KindPaper, Label: title, URL: url})
+}
+
+func (g *Graph) addRepo(name, url string) string {
+	if url == "" {
+		url = "https://github.com/" + name
+	}
+	return g.addNode(Node{ID: RepoNodeID(name), Kind: KindRepo, Label: name, URL: url})
+}
+
+func (g *Graph) addCategory(category string) string {
+	return g.addNode(Node{ID: KindCategory + ":" + category, Kind: KindCategory, Label: category})
+}
+
+func (g *Graph) addAuthor(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return ""
+	}
+	return g.addNode(Node{ID: KindAuthor + ":" + strings.ToLower(name), Kind: KindAuthor, Label: name})
+}
+
+func (g *Graph) link(source, target, kind string) {
+	e := Edge{Source: source, Target: target, Kind: kind}
+	if source == target || g.edgeSet[e] {
+		return
+	}
+	g.edgeSet[e] = true
+	g.edges = append(g.edges, e)
+	g.out[source] = append(g.out[source], e)
+	g.in[target] = append(g.in[target], e)
+}
+
+// Node returns a node by ID
+func (g *Graph) Node(id string) (Node, bool) {
+	n, ok := g.nodes[id]
+	if !ok {
+		return Node{}, false
+	}
+	return *n, true
+}
+
+// Neighbors returns the nodes of the given kind connected to id by an edge
+// in either direction, in insertion order
+func (g *Graph) Neighbors(id, kind string) []Node {
+	seen := make(map[string]bool)
+	var result []Node
+	add := func(other string) {
+		if seen[other] {
+			return
+		}
+		if n, ok := g.nodes[other]; ok && n.Kind == kind {
+			seen[other] = true
+			result = append(result, *n)
+		}
+	}
+	for _, e := range g.out[id] {
+		add(e.Target)
+	}
+	for _, e := range g.in[id] {
+		add(e.Source)
+	}
+	return result
+}
+
+// ReposForPaper returns the repositories implementing a paper. Repositories
+// that were not collected themselves only carry their name and URL.
+func (g *Graph) ReposForPaper(key string) []models.Repository {
+	var result []models.Repository
+	for _, n := range g.Neighbors(PaperNodeID(key), KindRepo) {
+		result = append(result, g.repo(n))
+	}
+	// 通过Hub模型间接关联的仓库：论文 <- 模型 -> 仓库
+	for _, m := range g.Neighbors(PaperNodeID(key), KindModel) {
+		for _, n := range g.Neighbors(m.ID, KindRepo) {
+			if !containsRepo(result, n.Label) {
+				result = append(result, g.repo(n))
+			}
+		}
+	}
+	return result
+}
+
+// PapersForRepo returns the papers a repository implements, including the
+// papers of Hugging Face models hosted by the repository
+func (g *Graph) PapersForRepo(name string) []models.Paper {
+	var result []models.Paper
+	seen := make(map[string]bool)
+	add := func(n Node) {
+		if seen[n.ID] {
+			return
+		}
+		seen[n.ID] = true
+		result = append(result, g.paper(n))
+	}
+	for _, n := range g.Neighbors(RepoNodeID(name), KindPaper) {
+		add(n)
+	}
+	for _, m := range g.Neighbors(RepoNodeID(name), KindModel) {
+		for _, n := range g.Neighbors(m.ID, KindPaper) {
+			add(n)
+		}
+	}
+	return result
+}
+
+func (g *Graph) repo(n Node) models.Repository {
+	if r, ok := g.repos[n.ID]; ok {
+		return r
+	}
+	return models.Repository{Name: n.Label, URL: n.URL}
+}
+
+func (g *Graph) paper(n Node) models.Paper {
+	if p, ok := g.papers[n.ID]; ok {
+		return p
+	}
+	return models.Paper{Title: n.Label, URL: n.URL}
+}
+
+func containsRepo(repos []models.Repository, name string) bool {
+	for _, r := range repos {
+		if strings.EqualFold(r.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPaper reports whether the graph has a node for the paper key
+func (g *Graph) HasPaper(key string) bool {
+	_, ok := g.nodes[PaperNodeID(key)]
+	return ok
+}
+
+// HasRepo reports whether the graph has a node for the repository
+func (g *Graph) HasRepo(name string) bool {
+	_, ok := g.nodes[RepoNodeID(name)]
+	return ok
+}
+
+// Export returns all nodes and edges. When kinds is not empty only nodes of
+// those kinds and the edges between them are included.
+func (g *Graph) Export(kinds ...string) Export {
+	keep := func(kind string) bool {
+		if len(kinds) == 0 {
+			return true
+		}
+		for _, k := range kinds {
+			if k == kind {
+				return true
+			}
+		}
+		return false
+	}
+
+	export := Export{Nodes: []Node{}, Edges: []Edge{}}
+	for _, id := range g.order {
+		if n := g.nodes[id]; keep(n.Kind) {
+			export.Nodes = append(export.Nodes, *n)
+		}
+	}
+	for _, e := range g.edges {
+		if keep(g.nodes[e.Source].Kind) && keep(g.nodes[e.Target].Kind) {
+			export.Edges = append(export.Edges, e)
+		}
+	}
+	return export
+}
+
+// Stats counts nodes per kind
+func (g *Graph) Stats() map[string]int {
+	stats := make(map[string]int)
+	for _, n := range g.nodes {
+		stats[n.Kind]++
+	}
+	stats["edges"] = len(g.edges)
+	return stats
+}
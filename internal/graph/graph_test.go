@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+func testGraph() *Graph {
+	repos := []models.Repository{
+		{
+			Name:       "meta-llama/llama",
+			URL:        "https://github.com/meta-llama/llama",
+			PaperURL:   "https://arxiv.org/abs/2302.13971",
+			PaperTitle: "LLaMA: Open and Efficient Foundation Language Models",
+		},
+		{
+			Name:       "chroma-core/chroma",
+			PaperURL:   "https://www.trychroma.com/",
+			PaperTitle: "Chroma",
+		},
+		{
+			Name:       "acme/agent",
+			DocQuality: &models.DocQuality{PaperLinks: []string{"https://arxiv.org/abs/2401.00001"}},
+			Authors:    []string{"Jane Doe"},
+		},
+	}
+	papers := []models.Paper{
+		{
+			Title:   "An Agent Framework",
+			URL:     "http://arxiv.org/abs/2401.00001v2",
+			Summary: "Code is available at https://github.com/acme/agent-tools.",
+			Authors: []string{"Jane  Doe", "John Roe"},
+		},
+		{Title: "A blog post", URL: "https://example.com/post"},
+	}
+	hub := []models.HubItem{
+		{
+			ID:        "deepseek-ai/DeepSeek-R1",
+			Kind:      models.HubModel,
+			URL:       "https://huggingface.co/deepseek-ai/DeepSeek-R1",
+			GitHubURL: "https://github.com/deepseek-ai/DeepSeek-R1",
+			PaperURLs: []string{"https://arxiv.org/abs/2501.12948"},
+		},
+	}
+	return Build(repos, papers, hub)
+}
+
+func TestReposAndPapers(t *testing.T) {
+	g := testGraph()
+
+	// README引用和论文摘要中的代码链接都应关联
+	repos := g.ReposForPaper("2401.00001")
+	if len(repos) != 2 || repos[0].Name != "acme/agent-tools" || repos[1].Name != "acme/agent" {
+		t.Fatalf("unexpected repos %+v", repos)
+	}
+	if repos[0].URL != "https://github.com/acme/agent-tools" {
+		t.Errorf("uncollected repo should keep its URL, got %q", repos[0].URL)
+	}
+
+	papers := g.PapersForRepo("Meta-Llama/Llama")
+	if len(papers) != 1 || papers[0].Title != "LLaMA: Open and Efficient Foundation Language Models" {
+		t.Errorf("unexpected papers %+v", papers)
+	}
+	if got := g.PapersForRepo("chroma-core/chroma"); len(got) != 0 {
+		t.Errorf("product pages should not become papers: %+v", got)
+	}
+
+	// 通过Hub模型关联的仓库和论文
+	if got := g.PapersForRepo("deepseek-ai/DeepSeek-R1"); len(got) != 1 || got[0].URL != "https://arxiv.org/abs/2501.12948" {
+		t.Errorf("unexpected papers via hub model %+v", got)
+	}
+	if got := g.ReposForPaper("2501.12948"); len(got) != 1 || got[0].Name != "deepseek-ai/DeepSeek-R1" {
+		t.Errorf("unexpected repos via hub model %+v", got)
+	}
+}
+
+func TestAuthorsAndExport(t *testing.T) {
+	g := testGraph()
+
+	// 作者名中的多余空白应归一化，论文和仓库共享同一作者节点
+	authored := g.Neighbors("author:jane doe", KindPaper)
+	if len(authored) != 1 || len(g.Neighbors("author:jane doe", KindRepo)) != 1 {
+		t.Errorf("expected Jane Doe to author one paper and one repo")
+	}
+
+	export := g.Export(KindPaper, KindRepo)
+	for _, n := range export.Nodes {
+		if n.Kind != KindPaper && n.Kind != KindRepo {
+			t.Errorf("unexpected node kind %q in filtered export", n.Kind)
+		}
+	}
+	for _, e := range export.Edges {
+		if e.Kind != EdgeImplements {
+			t.Errorf("unexpected edge %+v in filtered export", e)
+		}
+	}
+	if full := g.Export(); len(full.Nodes) <= len(export.Nodes) || len(full.Edges) == 0 {
+		t.Errorf("full export should include every node kind")
+	}
+}
@@ -0,0 +1,787 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxDepth limits the nesting of selections, so cyclic relations such as
+// paper → repos → papers cannot be expanded without bound
+const MaxDepth = 10
+
+// MaxNodes limits the number of objects in a response. Nested lists
+// multiply: 100 repositories with 100 papers each, with 100 repositories
+// each, would otherwise build a million objects within MaxDepth.
+const MaxNodes = 10000
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+
+	Context interface{} `json:"-"` // 传给所有解析函数的请求上下文
+}
+
+// Response is the result of a request. Data is nil when the request failed
+// before execution, e.g. with a syntax or validation error.
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Location is a position in the query text
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error is a request or field error
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func errorAt(loc Location, format string, args ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{loc}}
+}
+
+// Execute parses, validates and executes a query against the schema. Field
+// errors do not fail the request: the field is null and the error is listed.
+// Unlike the specification, a null in a non-null field is reported but not
+// propagated to the parent.
+func Execute(schema *Schema, req Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{asError(err)}}
+	}
+
+	var op *operation
+	for _, candidate := range doc.operations {
+		if req.OperationName == "" || candidate.name == req.OperationName {
+			if op != nil {
+				return &Response{Errors: []*Error{{Message: "Must provide operation name if query contains multiple operations."}}}
+			}
+			op = candidate
+		}
+	}
+	if op == nil {
+		return &Response{Errors: []*Error{{Message: fmt.Sprintf("Unknown operation named %q.", req.OperationName)}}}
+	}
+
+	e := &executor{schema: schema, doc: doc, ctx: req.Context, defined: make(map[string]string), vars: make(map[string]interface{})}
+	for _, def := range op.variables {
+		if err := e.defineVariable(def, req.Variables); err != nil {
+			e.errors = append(e.errors, err)
+		}
+	}
+	if len(e.errors) == 0 {
+		e.validateSet(schema.query, op.selections, nil, 1)
+	}
+	if len(e.errors) > 0 {
+		return &Response{Errors: e.errors}
+	}
+
+	data := e.executeSet(schema.query, op.selections, []interface{}{nil}, [][]interface{}{nil})[0]
+	if e.tooLarge != nil {
+		// 部分结果没有意义，整个请求失败
+		return &Response{Errors: []*Error{e.tooLarge}}
+	}
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func asError(err error) *Error {
+	if gqlErr, ok := err.(*Error); ok {
+		return gqlErr
+	}
+	return &Error{Message: err.Error()}
+}
+
+type executor struct {
+	schema  *Schema
+	doc     *document
+	ctx     interface{}
+	defined map[string]string      // 变量名 -> 类型
+	vars    map[string]interface{} // 已转换的变量值，未提供且无默认值的变量不在其中
+	errors  []*Error
+
+	nodes    int    // 已生成的对象数
+	tooLarge *Error // 超过MaxNodes时的错误，之后不再解析任何字段
+}
+
+func (e *executor) defineVariable(def variableDefinition, provided map[string]interface{}) *Error {
+	if _, dup := e.defined[def.name]; dup {
+		return errorAt(def.location, "There can be only one variable named \"$%s\".", def.name)
+	}
+	if !scalars[namedType(def.typ)] {
+		return errorAt(def.location, "Variable \"$%s\" cannot be of non-input type %q.", def.name, def.typ)
+	}
+	e.defined[def.name] = def.typ
+
+	raw, ok := provided[def.name]
+	switch {
+	case ok:
+		v, err := coerceVariable(def.typ, raw)
+		if err != nil {
+			return errorAt(def.location, "Variable \"$%s\" got invalid value: %v", def.name, err)
+		}
+		e.vars[def.name] = v
+	case def.def != nil:
+		v, err := e.coerceLiteral(def.typ, *def.def)
+		if err != nil {
+			return err
+		}
+		e.vars[def.name] = v
+	default:
+		if _, nonNull := isNonNull(def.typ); nonNull {
+			return errorAt(def.location, "Variable \"$%s\" of required type %q was not provided.", def.name, def.typ)
+		}
+	}
+	return nil
+}
+
+// validateSet checks the selections on typ before anything is executed
+func (e *executor) validateSet(typ *Object, set []selection, fragments []string, depth int) {
+	if depth > MaxDepth {
+		e.errors = append(e.errors, errorAt(set[0].location, "Query is nested deeper than %d levels.", MaxDepth))
+		return
+	}
+	for _, sel := range set {
+		if _, err := e.skip(sel.directives); err != nil {
+			e.errors = append(e.errors, err)
+		}
+
+		if sel.fragment {
+			selections, condition, path := sel.selections, sel.typeCondition, fragments
+			if sel.spread != "" {
+				f, ok := e.doc.fragments[sel.spread]
+				if !ok {
+					e.errors = append(e.errors, errorAt(sel.location, "Unknown fragment %q.", sel.spread))
+					continue
+				}
+				for _, name := range fragments {
+					if name == f.name {
+						e.errors = append(e.errors, errorAt(sel.location, "Cannot spread fragment %q within itself.", f.name))
+						return
+					}
+				}
+				selections, condition = f.selections, f.typeCondition
+				path = append(fragments[:len(fragments):len(fragments)], f.name)
+			}
+			if condition != "" && condition != typ.Name {
+				e.errors = append(e.errors, errorAt(sel.location, "Fragment cannot be spread here as objects of type %q can never be of type %q.", typ.Name, condition))
+				continue
+			}
+			e.validateSet(typ, selections, path, depth)
+			continue
+		}
+
+		if sel.name == "__typename" {
+			if len(sel.selections) > 0 {
+				e.errors = append(e.errors, errorAt(sel.location, "Field \"__typename\" must not have a selection since type \"String!\" has no subfields."))
+			}
+			continue
+		}
+		f, ok := typ.field(sel.name)
+		if !ok {
+			e.errors = append(e.errors, errorAt(sel.location, "Cannot query field %q on type %q.", sel.name, typ.Name))
+			continue
+		}
+		for _, a := range sel.arguments {
+			if _, ok := f.arg(a.name); !ok {
+				e.errors = append(e.errors, errorAt(a.value.location, "Unknown argument %q on field \"%s.%s\".", a.name, typ.Name, f.Name))
+			}
+		}
+		if _, err := e.coerceArgs(f, sel); err != nil {
+			e.errors = append(e.errors, err)
+		}
+
+		child, isObject := e.schema.types[namedType(f.Type)]
+		switch {
+		case isObject && len(sel.selections) == 0:
+			e.errors = append(e.errors, errorAt(sel.location, "Field %q of type %q must have a selection of subfields.", sel.name, f.Type))
+		case !isObject && len(sel.selections) > 0:
+			e.errors = append(e.errors, errorAt(sel.location, "Field %q must not have a selection since type %q has no subfields.", sel.name, f.Type))
+		case isObject:
+			e.validateSet(child, sel.selections, fragments, depth+1)
+		}
+	}
+}
+
+// skip evaluates the @skip and @include directives
+func (e *executor) skip(directives []directive) (bool, *Error) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			return false, errorAt(d.location, "Unknown directive \"@%s\".", d.name)
+		}
+		if len(d.arguments) != 1 || d.arguments[0].name != "if" {
+			return false, errorAt(d.location, "Directive \"@%s\" requires the argument \"if\".", d.name)
+		}
+		v, err := e.coerceLiteral("Boolean!", d.arguments[0].value)
+		if err != nil {
+			return false, err
+		}
+		if v.(bool) == (d.name == "skip") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// coerceArgs converts the arguments of a field selection, filling in the
+// defaults of the arguments that were not given or given as null
+func (e *executor) coerceArgs(f *Field, sel selection) (map[string]interface{}, *Error) {
+	args := make(map[string]interface{}, len(f.Args))
+	for _, def := range f.Args {
+		var given *value
+		for i := range sel.arguments {
+			if sel.arguments[i].name == def.Name {
+				given = &sel.arguments[i].value
+			}
+		}
+		if given != nil && given.kind == valueVariable {
+			if _, provided := e.vars[given.raw]; !provided {
+				if _, defined := e.defined[given.raw]; !defined {
+					return nil, errorAt(given.location, "Variable \"$%s\" is not defined.", given.raw)
+				}
+				given = nil // 未提供的变量视为未传该参数
+			}
+		}
+		if given == nil {
+			if def.Default != nil {
+				args[def.Name] = def.Default
+			} else if _, nonNull := isNonNull(def.Type); nonNull {
+				return nil, errorAt(sel.location, "Field %q argument %q of type %q is required, but it was not provided.", f.Name, def.Name, def.Type)
+			}
+			continue
+		}
+		v, err := e.coerceLiteral(def.Type, *given)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil && def.Default != nil {
+			v = def.Default // 显式传入null时同样使用默认值，解析函数不必处理nil
+		}
+		args[def.Name] = v
+	}
+	return args, nil
+}
+
+// coerceLiteral converts a query value to the Go value of typ
+func (e *executor) coerceLiteral(typ string, v value) (interface{}, *Error) {
+	if v.kind == valueVariable {
+		if _, defined := e.defined[v.raw]; !defined {
+			return nil, errorAt(v.location, "Variable \"$%s\" is not defined.", v.raw)
+		}
+		result := e.vars[v.raw]
+		if _, nonNull := isNonNull(typ); nonNull && result == nil {
+			return nil, errorAt(v.location, "Variable \"$%s\" of type %q used in position expecting %q.", v.raw, e.defined[v.raw], typ)
+		}
+		return result, nil
+	}
+
+	nullable, nonNull := isNonNull(typ)
+	if v.kind == valueNull {
+		if nonNull {
+			return nil, errorAt(v.location, "Expected value of type %q, found null.", typ)
+		}
+		return nil, nil
+	}
+	if item, ok := listItem(nullable); ok {
+		items := v.list
+		if v.kind != valueList {
+			items = []value{v} // 单个值按只有一项的列表处理
+		}
+		list := make([]interface{}, len(items))
+		for i, itemValue := range items {
+			converted, err := e.coerceLiteral(item, itemValue)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = converted
+		}
+		return list, nil
+	}
+
+	invalid := errorAt(v.location, "Expected value of type %q, found %s.", typ, describeValue(v))
+	switch nullable {
+	case "Int":
+		if v.kind == tokenInt {
+			n, _ := strconv.Atoi(v.raw)
+			return n, nil
+		}
+	case "Float":
+		if v.kind == tokenInt || v.kind == tokenFloat {
+			f, _ := strconv.ParseFloat(v.raw, 64)
+			return f, nil
+		}
+	case "String":
+		if v.kind == tokenString {
+			return v.raw, nil
+		}
+	case "ID":
+		if v.kind == tokenString || v.kind == tokenInt {
+			return v.raw, nil
+		}
+	case "Boolean":
+		if v.kind == valueBool {
+			return v.raw == "true", nil
+		}
+	case "Time":
+		if v.kind == tokenString {
+			t, err := time.Parse(time.RFC3339, v.raw)
+			if err == nil {
+				return t, nil
+			}
+		}
+	}
+	return nil, invalid
+}
+
+func describeValue(v value) string {
+	switch v.kind {
+	case tokenString:
+		return strconv.Quote(v.raw)
+	case valueList:
+		return "a list"
+	case valueObject:
+		return "an object"
+	}
+	return v.raw
+}
+
+// coerceVariable converts a variable value decoded from JSON
+func coerceVariable(typ string, raw interface{}) (interface{}, error) {
+	nullable, nonNull := isNonNull(typ)
+	if raw == nil {
+		if nonNull {
+			return nil, fmt.Errorf("expected non-null value of type %s", typ)
+		}
+		return nil, nil
+	}
+	if item, ok := listItem(nullable); ok {
+		items, isList := raw.([]interface{})
+		if !isList {
+			items = []interface{}{raw}
+		}
+		list := make([]interface{}, len(items))
+		for i, itemValue := range items {
+			converted, err := coerceVariable(item, itemValue)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = converted
+		}
+		return list, nil
+	}
+
+	switch v := raw.(type) {
+	case float64:
+		switch nullable {
+		case "Int":
+			if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32 {
+				return int(v), nil
+			}
+		case "Float":
+			return v, nil
+		case "ID":
+			if v == math.Trunc(v) {
+				return strconv.FormatFloat(v, 'f', -1, 64), nil
+			}
+		}
+	case int:
+		switch nullable {
+		case "Int":
+			return v, nil
+		case "Float":
+			return float64(v), nil
+		case "ID":
+			return strconv.Itoa(v), nil
+		}
+	case string:
+		switch nullable {
+		case "String", "ID":
+			return v, nil
+		case "Time":
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return t, nil
+			}
+		}
+	case bool:
+		if nullable == "Boolean" {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("expected value of type %s, found %v", typ, raw)
+}
+
+// fieldGroup is a response key with the field selections merged into it
+type fieldGroup struct {
+	key    string
+	fields []selection
+}
+
+// collectFields flattens fragments and groups the fields by response key
+func (e *executor) collectFields(set []selection, groups []fieldGroup, visited map[string]bool) []fieldGroup {
+	for _, sel := range set {
+		if skipped, _ := e.skip(sel.directives); skipped {
+			continue
+		}
+		if sel.fragment {
+			selections := sel.selections
+			if sel.spread != "" {
+				if visited[sel.spread] {
+					continue
+				}
+				visited[sel.spread] = true
+				selections = e.doc.fragments[sel.spread].selections
+			}
+			groups = e.collectFields(selections, groups, visited)
+			continue
+		}
+		merged := false
+		for i := range groups {
+			if groups[i].key == sel.responseKey() {
+				groups[i].fields = append(groups[i].fields, sel)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			groups = append(groups, fieldGroup{key: sel.responseKey(), fields: []selection{sel}})
+		}
+	}
+	return groups
+}
+
+// executeSet executes the selections on every source object of type typ.
+// Each field is resolved for all sources together, so batch resolvers see
+// the whole list.
+func (e *executor) executeSet(typ *Object, set []selection, sources []interface{}, paths [][]interface{}) []*object {
+	results := make([]*object, len(sources))
+	for i := range results {
+		results[i] = &object{values: make(map[string]interface{})}
+	}
+
+	for _, group := range e.collectFields(set, nil, make(map[string]bool)) {
+		if e.tooLarge != nil {
+			break
+		}
+		sel := group.fields[0]
+		fieldPaths := make([][]interface{}, len(sources))
+		for i := range sources {
+			fieldPaths[i] = appendPath(paths[i], group.key)
+		}
+		if sel.name == "__typename" {
+			for _, result := range results {
+				result.set(group.key, typ.Name)
+			}
+			continue
+		}
+
+		f, _ := typ.field(sel.name)
+		var sub []selection
+		for _, s := range group.fields {
+			sub = append(sub, s.selections...)
+		}
+		values, failed := e.resolve(f, sel, sources, fieldPaths)
+		for i, v := range e.complete(f.Type, values, failed, sub, fieldPaths, sel.location) {
+			results[i].set(group.key, v)
+		}
+	}
+	return results
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	result := make([]interface{}, len(path)+1)
+	copy(result, path)
+	result[len(path)] = elem
+	return result
+}
+
+// resolve returns the raw value of field f for every source. failed marks
+// the sources whose resolver returned an error.
+func (e *executor) resolve(f *Field, sel selection, sources []interface{}, paths [][]interface{}) (values []interface{}, failed []bool) {
+	values = make([]interface{}, len(sources))
+	failed = make([]bool, len(sources))
+	fail := func(message string, i int) {
+		if i < 0 {
+			for j := range failed {
+				failed[j] = true
+			}
+			i = 0
+		} else {
+			failed[i] = true
+		}
+		e.fieldError(message, sel.location, paths[i])
+	}
+
+	args, argErr := e.coerceArgs(f, sel)
+	if argErr != nil {
+		fail(argErr.Message, -1)
+		return values, failed
+	}
+
+	if f.Batch != nil {
+		batch, err := f.Batch(Params{Args: args, Context: e.ctx}, sources)
+		switch {
+		case err != nil:
+			fail(err.Error(), -1)
+		case len(batch) != len(sources):
+			fail(fmt.Sprintf("batch resolver of %s returned %d values for %d sources", f.Name, len(batch), len(sources)), -1)
+		default:
+			copy(values, batch)
+		}
+		return values, failed
+	}
+
+	for i, source := range sources {
+		var v interface{}
+		var err error
+		if f.Resolve != nil {
+			v, err = f.Resolve(Params{Source: source, Args: args, Context: e.ctx})
+		} else {
+			v, err = defaultResolve(source, f.Name)
+		}
+		if err != nil {
+			fail(err.Error(), i)
+			continue
+		}
+		values[i] = v
+	}
+	return values, failed
+}
+
+func (e *executor) fieldError(message string, loc Location, path []interface{}) {
+	e.errors = append(e.errors, &Error{Message: message, Locations: []Location{loc}, Path: path})
+}
+
+// complete converts resolved values to their response form according to
+// typ. Lists are flattened before their items are completed, so the items
+// of all lists on one level are again resolved together. Values marked in
+// failed already have an error and are not reported again when null.
+func (e *executor) complete(typ string, values []interface{}, failed []bool, sub []selection, paths [][]interface{}, loc Location) []interface{} {
+	out := make([]interface{}, len(values))
+	nullable, nonNull := isNonNull(typ)
+	defer func() {
+		if !nonNull || e.tooLarge != nil {
+			return
+		}
+		for i := range out {
+			if out[i] == nil && (failed == nil || !failed[i]) {
+				e.fieldError(fmt.Sprintf("Cannot return null for non-nullable field of type %s.", typ), loc, paths[i])
+			}
+		}
+	}()
+
+	if item, ok := listItem(nullable); ok {
+		var flat []interface{}
+		var flatPaths [][]interface{}
+		counts := make([]int, len(values))
+		for i, v := range values {
+			if isNull(v) {
+				counts[i] = -1
+				continue
+			}
+			rv := reflect.ValueOf(v)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				counts[i] = -1
+				e.fieldError(fmt.Sprintf("Expected a list for type %s, got %T.", typ, v), loc, paths[i])
+				continue
+			}
+			counts[i] = rv.Len()
+			for j := 0; j < rv.Len(); j++ {
+				flat = append(flat, rv.Index(j).Interface())
+				flatPaths = append(flatPaths, appendPath(paths[i], j))
+			}
+		}
+		completed := e.complete(item, flat, nil, sub, flatPaths, loc)
+		offset := 0
+		for i, n := range counts {
+			if n < 0 {
+				continue
+			}
+			list := make([]interface{}, n)
+			copy(list, completed[offset:offset+n])
+			out[i] = list
+			offset += n
+		}
+		return out
+	}
+
+	if obj, ok := e.schema.types[nullable]; ok {
+		var sources []interface{}
+		var sourcePaths [][]interface{}
+		var index []int
+		for i, v := range values {
+			if !isNull(v) {
+				sources = append(sources, v)
+				sourcePaths = append(sourcePaths, paths[i])
+				index = append(index, i)
+			}
+		}
+		if e.nodes += len(sources); e.nodes > MaxNodes {
+			if e.tooLarge == nil {
+				e.tooLarge = &Error{
+					Message:   fmt.Sprintf("Query returns more than %d objects, use limit arguments to select fewer.", MaxNodes),
+					Locations: []Location{loc},
+					Path:      sourcePaths[0],
+				}
+			}
+			return out
+		}
+		if len(sources) > 0 {
+			for j, result := range e.executeSet(obj, sub, sources, sourcePaths) {
+				out[index[j]] = result
+			}
+		}
+		return out
+	}
+
+	for i, v := range values {
+		if isNull(v) {
+			continue
+		}
+		serialized, err := serialize(nullable, v)
+		if err != nil {
+			e.fieldError(err.Error(), loc, paths[i])
+			continue
+		}
+		out[i] = serialized
+	}
+	return out
+}
+
+// isNull reports whether a resolved value is null. Nil slices are empty
+// lists, not null.
+func isNull(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// serialize converts a leaf value to the JSON form of the scalar type
+func serialize(typ string, v interface{}) (interface{}, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch typ {
+	case "Int":
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return rv.Int(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return rv.Uint(), nil
+		case reflect.Float32, reflect.Float64:
+			if f := rv.Float(); f == math.Trunc(f) {
+				return int64(f), nil
+			}
+		}
+	case "Float":
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(rv.Int()), nil
+		case reflect.Float32, reflect.Float64:
+			if f := rv.Float(); !math.IsNaN(f) && !math.IsInf(f, 0) {
+				return f, nil
+			}
+		}
+	case "String", "ID":
+		switch rv.Kind() {
+		case reflect.String:
+			return rv.String(), nil
+		case reflect.Int, reflect.Int64:
+			if typ == "ID" {
+				return strconv.FormatInt(rv.Int(), 10), nil
+			}
+		}
+	case "Boolean":
+		if rv.Kind() == reflect.Bool {
+			return rv.Bool(), nil
+		}
+	case "Time":
+		if t, ok := rv.Interface().(time.Time); ok {
+			if t.IsZero() {
+				return nil, nil
+			}
+			return t.Format(time.RFC3339), nil
+		}
+	}
+	return nil, fmt.Errorf("%s cannot represent value %v", typ, v)
+}
+
+// defaultResolve reads field name from a map or from the struct field whose
+// json tag or Go name matches it
+func defaultResolve(source interface{}, name string) (interface{}, error) {
+	rv := reflect.Indirect(reflect.ValueOf(source))
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			if v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key())); v.IsValid() {
+				return v.Interface(), nil
+			}
+			return nil, nil
+		}
+	case reflect.Struct:
+		for _, sf := range reflect.VisibleFields(rv.Type()) {
+			if !sf.IsExported() || sf.Anonymous {
+				continue
+			}
+			tag := strings.Split(sf.Tag.Get("json"), ",")[0]
+			if tag == name || (tag == "" && sf.Name == name) {
+				return rv.FieldByIndex(sf.Index).Interface(), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no resolver for field %s on %T", name, source)
+}
+
+// object is a result object that keeps its keys in selection order
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *object) set(key string, v interface{}) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
+
+// MarshalJSON writes the keys in selection order, as the specification
+// requires
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,301 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testBook struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	AuthorID  string    `json:"-"`
+	Published time.Time `json:"published"`
+	Pages     int
+}
+
+type testAuthor struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+var (
+	testBooks = []testBook{
+		{ID: "1", Title: "Go", AuthorID: "a", Published: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Pages: 300},
+		{ID: "2", Title: "GraphQL", AuthorID: "b", Pages: 120},
+		{ID: "3", Title: "Gin", AuthorID: "a", Pages: 90},
+	}
+	testAuthors = map[string]testAuthor{"a": {ID: "a", Name: "Ada"}, "b": {ID: "b", Name: "Bob"}}
+)
+
+// testSchema returns a schema of books and authors and a counter of the
+// calls of the author loader
+func testSchema(t *testing.T) (*Schema, *int) {
+	calls := new(int)
+	author := &Object{Name: "Author", Fields: []*Field{
+		{Name: "id", Type: "ID!"},
+		{Name: "name", Type: "String!"},
+		{Name: "books", Type: "[Book!]!", Resolve: func(p Params) (interface{}, error) {
+			var list []testBook
+			for _, b := range testBooks {
+				if b.AuthorID == p.Source.(testAuthor).ID {
+					list = append(list, b)
+				}
+			}
+			return list, nil
+		}},
+	}}
+	book := &Object{Name: "Book", Fields: []*Field{
+		{Name: "id", Type: "ID!"},
+		{Name: "title", Type: "String!"},
+		{Name: "published", Type: "Time"},
+		{Name: "Pages", Type: "Int!"},
+		{Name: "author", Type: "Author", Batch: func(p Params, sources []interface{}) ([]interface{}, error) {
+			*calls++
+			result := make([]interface{}, len(sources))
+			for i, s := range sources {
+				result[i] = testAuthors[s.(testBook).AuthorID]
+			}
+			return result, nil
+		}},
+		{Name: "broken", Type: "String", Resolve: func(p Params) (interface{}, error) {
+			return nil, errors.New("boom")
+		}},
+		{Name: "missing", Type: "String!", Resolve: func(p Params) (interface{}, error) {
+			return nil, nil
+		}},
+		{Name: "strict", Type: "String!", Resolve: func(p Params) (interface{}, error) {
+			return nil, errors.New("strict boom")
+		}},
+	}}
+	query := &Object{Name: "Query", Fields: []*Field{
+		{Name: "books", Type: "[Book!]!", Args: []Arg{{Name: "limit", Type: "Int", Default: 10}, {Name: "prefix", Type: "String"}},
+			Resolve: func(p Params) (interface{}, error) {
+				var list []testBook
+				for _, b := range testBooks {
+					prefix, _ := p.Args["prefix"].(string)
+					if strings.HasPrefix(b.Title, prefix) && len(list) < p.Args["limit"].(int) {
+						list = append(list, b)
+					}
+				}
+				return list, nil
+			}},
+		{Name: "book", Type: "Book", Args: []Arg{{Name: "id", Type: "ID!"}}, Resolve: func(p Params) (interface{}, error) {
+			for _, b := range testBooks {
+				if b.ID == p.Args["id"] {
+					return &b, nil
+				}
+			}
+			return nil, nil
+		}},
+	}}
+	schema, err := NewSchema(query, book, author)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema, calls
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables string
+		want      string
+		errors    []string
+	}{
+		{
+			name:  "shorthand with alias and typename",
+			query: `{ first: book(id: "1") { __typename title published } none: book(id: 9) { id } }`,
+			want:  `{"first":{"__typename":"Book","title":"Go","published":"2024-01-02T00:00:00Z"},"none":null}`,
+		},
+		{
+			name:  "default argument and go field name",
+			query: `query { books(limit: 2) { id Pages } }`,
+			want:  `{"books":[{"id":"1","Pages":300},{"id":"2","Pages":120}]}`,
+		},
+		{
+			name:      "variables and fragments",
+			query:     `query Q($p: String, $withAuthor: Boolean!) { books(prefix: $p) { ...Info author @include(if: $withAuthor) { name } } } fragment Info on Book { title ... on Book { id } }`,
+			variables: `{"p": "Gi", "withAuthor": true}`,
+			want:      `{"books":[{"title":"Gin","id":"3","author":{"name":"Ada"}}]}`,
+		},
+		{
+			name:      "omitted variable uses argument default",
+			query:     `query ($n: Int) { books(limit: $n) { id } }`,
+			variables: `{}`,
+			want:      `{"books":[{"id":"1"},{"id":"2"},{"id":"3"}]}`,
+		},
+		{
+			name:      "null argument uses argument default",
+			query:     `query ($n: Int) { a: books(limit: null) { id } b: books(limit: $n) { id } }`,
+			variables: `{"n": null}`,
+			want:      `{"a":[{"id":"1"},{"id":"2"},{"id":"3"}],"b":[{"id":"1"},{"id":"2"},{"id":"3"}]}`,
+		},
+		{
+			name:   "field errors",
+			query:  `{ book(id: "2") { broken missing strict title } }`,
+			want:   `{"book":{"broken":null,"missing":null,"strict":null,"title":"GraphQL"}}`,
+			errors: []string{"boom", "Cannot return null for non-nullable field of type String!.", "strict boom"},
+		},
+		{
+			name:   "syntax error",
+			query:  `{ books { id }`,
+			errors: []string{`Syntax Error: expected "}", found <EOF>`},
+		},
+		{
+			name:   "unknown field and argument",
+			query:  `{ books(order: "x") { isbn } }`,
+			errors: []string{`Unknown argument "order" on field "Query.books".`, `Cannot query field "isbn" on type "Book".`},
+		},
+		{
+			name:   "missing selection and required argument",
+			query:  `{ book { id } books }`,
+			errors: []string{`Field "book" argument "id" of type "ID!" is required, but it was not provided.`, `Field "books" of type "[Book!]!" must have a selection of subfields.`},
+		},
+		{
+			name:      "invalid variable",
+			query:     `query ($n: Int!) { books(limit: $n) { id } }`,
+			variables: `{"n": 1.5}`,
+			errors:    []string{`Variable "$n" got invalid value: expected value of type Int!, found 1.5`},
+		},
+		{
+			name:   "undefined variable and bad literal",
+			query:  `{ a: books(limit: $n) { id } b: books(limit: "x") { id } }`,
+			errors: []string{`Variable "$n" is not defined.`, `Expected value of type "Int", found "x".`},
+		},
+		{
+			name:   "fragment cycle",
+			query:  `{ books { ...B ...B ...A } } fragment A on Book { ...A } fragment B on Book { id }`,
+			errors: []string{`Cannot spread fragment "A" within itself.`},
+		},
+		{
+			name:   "mutation",
+			query:  `mutation { books { id } }`,
+			errors: []string{"Syntax Error: only query operations are supported"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, _ := testSchema(t)
+			req := Request{Query: tt.query}
+			if tt.variables != "" {
+				if err := json.Unmarshal([]byte(tt.variables), &req.Variables); err != nil {
+					t.Fatal(err)
+				}
+			}
+			resp := Execute(schema, req)
+
+			var messages []string
+			for _, e := range resp.Errors {
+				messages = append(messages, e.Message)
+			}
+			if strings.Join(messages, "\n") != strings.Join(tt.errors, "\n") {
+				t.Errorf("errors = %q, want %q", messages, tt.errors)
+			}
+			if tt.want == "" {
+				if resp.Data != nil {
+					t.Errorf("data = %v, want none", resp.Data)
+				}
+				return
+			}
+			got, err := json.Marshal(resp.Data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("data = %s\nwant   %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExecuteBatch checks that a batched field is resolved once per level,
+// also when its sources come from several nested lists
+func TestExecuteBatch(t *testing.T) {
+	schema, calls := testSchema(t)
+	resp := Execute(schema, Request{Query: `{ books { author { name books { title author { id } } } } }`})
+	if len(resp.Errors) > 0 {
+		t.Fatal(resp.Errors[0])
+	}
+	if *calls != 2 {
+		t.Errorf("author loader called %d times, want 2", *calls)
+	}
+	got, _ := json.Marshal(resp.Data)
+	if !strings.Contains(string(got), `{"name":"Bob","books":[{"title":"GraphQL","author":{"id":"b"}}]}`) {
+		t.Errorf("unexpected data %s", got)
+	}
+}
+
+func TestExecuteErrorLocation(t *testing.T) {
+	schema, _ := testSchema(t)
+	resp := Execute(schema, Request{Query: "{\n  books {\n    broken\n  }\n}"})
+	if len(resp.Errors) != 3 {
+		t.Fatalf("got %d errors, want one per book", len(resp.Errors))
+	}
+	e := resp.Errors[1]
+	if e.Locations[0] != (Location{Line: 3, Column: 5}) {
+		t.Errorf("location = %+v", e.Locations[0])
+	}
+	if path, _ := json.Marshal(e.Path); string(path) != `["books",1,"broken"]` {
+		t.Errorf("path = %s", path)
+	}
+}
+
+func TestSDL(t *testing.T) {
+	schema, _ := testSchema(t)
+	sdl := schema.SDL()
+	for _, want := range []string{"type Query {", "books(limit: Int = 10, prefix: String): [Book!]!", "type Author {"} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL does not contain %q:\n%s", want, sdl)
+		}
+	}
+	if _, err := NewSchema(&Object{Name: "Query", Fields: []*Field{{Name: "x", Type: "Missing"}}}); err == nil {
+		t.Error("NewSchema accepted an unknown type")
+	}
+}
+
+// TestMaxNodes checks that nested lists cannot multiply into an unbounded
+// response
+func TestMaxNodes(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+	children := func(p Params) (interface{}, error) {
+		list := make([]item, p.Args["n"].(int))
+		for i := range list {
+			list[i].ID = i
+		}
+		return list, nil
+	}
+	nArg := []Arg{{Name: "n", Type: "Int", Default: 100}}
+	itemType := &Object{Name: "Item", Fields: []*Field{
+		{Name: "id", Type: "Int!"},
+		{Name: "children", Type: "[Item!]!", Args: nArg, Resolve: children},
+	}}
+	query := &Object{Name: "Query", Fields: []*Field{
+		{Name: "items", Type: "[Item!]!", Args: nArg, Resolve: children},
+	}}
+	schema, err := NewSchema(query, itemType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 100 + 100*99 = 10000 个对象，恰好在限制之内
+	resp := Execute(schema, Request{Query: `{ items { id children(n: 99) { id } } }`})
+	if len(resp.Errors) > 0 || resp.Data == nil {
+		t.Fatalf("query within the limit failed: %v", resp.Errors)
+	}
+
+	// 第二层已超过限制，第三层不再解析
+	resp = Execute(schema, Request{Query: `{ items { children { children { id } } } }`})
+	if resp.Data != nil || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "more than 10000 objects") {
+		t.Fatalf("got data %v and errors %v, want only the size error", resp.Data != nil, resp.Errors)
+	}
+	if path, _ := json.Marshal(resp.Errors[0].Path); string(path) != `["items",0,"children",0]` {
+		t.Errorf("path = %s", path)
+	}
+}
@@ -0,0 +1,490 @@
+package graphql
+
+import (
+	"strconv"
+)
+
+// document is a parsed query document
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	name       string
+	variables  []variableDefinition
+	selections []selection
+}
+
+type variableDefinition struct {
+	name     string
+	typ      string // 类型引用，例如 "[String!]!"
+	def      *value // 没有默认值时为nil
+	location Location
+}
+
+type fragment struct {
+	name          string
+	typeCondition string
+	directives    []directive
+	selections    []selection
+	location      Location
+}
+
+// selection is a field, a fragment spread or an inline fragment
+type selection struct {
+	// 字段
+	alias      string
+	name       string
+	arguments  []argument
+	selections []selection
+
+	// 片段；spread为命名片段的名称，内联片段的spread为空
+	fragment      bool
+	spread        string
+	typeCondition string
+
+	directives []directive
+	location   Location
+}
+
+// responseKey is the key of a field in the result
+func (s selection) responseKey() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+type argument struct {
+	name  string
+	value value
+}
+
+type directive struct {
+	name      string
+	arguments []argument
+	location  Location
+}
+
+// value is a literal or a variable reference in a query
+type value struct {
+	kind     int // tokenInt等，或以下的值类型
+	raw      string
+	list     []value
+	fields   []argument // 输入对象
+	location Location
+}
+
+// value kinds besides the token kinds
+const (
+	valueVariable = 100 + iota
+	valueBool
+	valueNull
+	valueEnum
+	valueList
+	valueObject
+)
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// parse parses a query document. Only query operations are supported.
+func parse(src string) (*document, error) {
+	p := &parser{lex: &lexer{src: src}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.isPunct("{"):
+			set, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{selections: set})
+		case p.isName("query"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.isName("fragment"):
+			f, err := p.fragmentDefinition()
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := doc.fragments[f.name]; dup {
+				return nil, errorAt(f.location, "There can be only one fragment named %q.", f.name)
+			}
+			doc.fragments[f.name] = f
+		case p.isName("mutation"), p.isName("subscription"):
+			return nil, p.errorf("only query operations are supported")
+		default:
+			return nil, p.errorf("unexpected %s", p.describe())
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, &Error{Message: "Syntax Error: the document contains no operation"}
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) isPunct(s string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == s
+}
+
+func (p *parser) isName(s string) bool {
+	return p.tok.kind == tokenName && p.tok.value == s
+}
+
+func (p *parser) location() Location {
+	return p.lex.location(p.tok.pos)
+}
+
+func (p *parser) describe() string {
+	if p.tok.kind == tokenEOF {
+		return "<EOF>"
+	}
+	return strconv.Quote(p.tok.value)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return p.lex.errorf(p.tok.pos, format, args...)
+}
+
+func (p *parser) expectPunct(s string) error {
+	if !p.isPunct(s) {
+		return p.errorf("expected %q, found %s", s, p.describe())
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("expected a name, found %s", p.describe())
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) operation() (*operation, error) {
+	if err := p.advance(); err != nil { // query
+		return nil, err
+	}
+	op := &operation{}
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isPunct("(") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for !p.isPunct(")") {
+			def, err := p.variableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.variables = append(op.variables, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	set, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = set
+	return op, nil
+}
+
+func (p *parser) variableDefinition() (variableDefinition, error) {
+	def := variableDefinition{location: p.location()}
+	if err := p.expectPunct("$"); err != nil {
+		return def, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return def, err
+	}
+	def.name = name
+	if err := p.expectPunct(":"); err != nil {
+		return def, err
+	}
+	if def.typ, err = p.typeReference(); err != nil {
+		return def, err
+	}
+	if p.isPunct("=") {
+		if err := p.advance(); err != nil {
+			return def, err
+		}
+		v, err := p.value(true)
+		if err != nil {
+			return def, err
+		}
+		def.def = &v
+	}
+	return def, nil
+}
+
+// typeReference parses a type such as [String!]! into its textual form
+func (p *parser) typeReference() (string, error) {
+	var typ string
+	if p.isPunct("[") {
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		inner, err := p.typeReference()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return "", err
+		}
+		typ = name
+	}
+	if p.isPunct("!") {
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		typ += "!"
+	}
+	return typ, nil
+}
+
+func (p *parser) fragmentDefinition() (*fragment, error) {
+	f := &fragment{location: p.location()}
+	if err := p.advance(); err != nil { // fragment
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, p.errorf("unexpected name \"on\"")
+	}
+	f.name = name
+	if !p.isName("on") {
+		return nil, p.errorf("expected \"on\", found %s", p.describe())
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if f.typeCondition, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	if f.directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if f.selections, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (p *parser) selectionSet() ([]selection, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	var set []selection
+	for !p.isPunct("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.errorf("expected \"}\", found <EOF>")
+		}
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, sel)
+	}
+	if len(set) == 0 {
+		return nil, p.errorf("expected a selection, found \"}\"")
+	}
+	return set, p.advance()
+}
+
+func (p *parser) selection() (selection, error) {
+	sel := selection{location: p.location()}
+	var err error
+	if p.isPunct("...") {
+		sel.fragment = true
+		if err := p.advance(); err != nil {
+			return sel, err
+		}
+		switch {
+		case p.isName("on"):
+			if err := p.advance(); err != nil {
+				return sel, err
+			}
+			if sel.typeCondition, err = p.expectName(); err != nil {
+				return sel, err
+			}
+		case p.tok.kind == tokenName:
+			sel.spread = p.tok.value
+			if err := p.advance(); err != nil {
+				return sel, err
+			}
+		}
+		if sel.directives, err = p.directives(); err != nil {
+			return sel, err
+		}
+		if sel.spread == "" {
+			sel.selections, err = p.selectionSet()
+		}
+		return sel, err
+	}
+
+	if sel.name, err = p.expectName(); err != nil {
+		return sel, err
+	}
+	if p.isPunct(":") {
+		if err := p.advance(); err != nil {
+			return sel, err
+		}
+		sel.alias = sel.name
+		if sel.name, err = p.expectName(); err != nil {
+			return sel, err
+		}
+	}
+	if sel.arguments, err = p.arguments(false); err != nil {
+		return sel, err
+	}
+	if sel.directives, err = p.directives(); err != nil {
+		return sel, err
+	}
+	if p.isPunct("{") {
+		sel.selections, err = p.selectionSet()
+	}
+	return sel, err
+}
+
+func (p *parser) arguments(constant bool) ([]argument, error) {
+	if !p.isPunct("(") {
+		return nil, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var args []argument
+	for !p.isPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		v, err := p.value(constant)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, argument{name: name, value: v})
+	}
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]directive, error) {
+	var list []directive
+	for p.isPunct("@") {
+		d := directive{location: p.location()}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		d.name = name
+		if d.arguments, err = p.arguments(false); err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+// value parses a value; constant values may not reference variables
+func (p *parser) value(constant bool) (value, error) {
+	v := value{kind: p.tok.kind, raw: p.tok.value, location: p.location()}
+	switch {
+	case p.isPunct("$") && !constant:
+		if err := p.advance(); err != nil {
+			return v, err
+		}
+		name, err := p.expectName()
+		v.kind, v.raw = valueVariable, name
+		return v, err
+	case p.isPunct("["):
+		v.kind = valueList
+		if err := p.advance(); err != nil {
+			return v, err
+		}
+		for !p.isPunct("]") {
+			item, err := p.value(constant)
+			if err != nil {
+				return v, err
+			}
+			v.list = append(v.list, item)
+		}
+		return v, p.advance()
+	case p.isPunct("{"):
+		v.kind = valueObject
+		if err := p.advance(); err != nil {
+			return v, err
+		}
+		for !p.isPunct("}") {
+			name, err := p.expectName()
+			if err != nil {
+				return v, err
+			}
+			if err := p.expectPunct(":"); err != nil {
+				return v, err
+			}
+			field, err := p.value(constant)
+			if err != nil {
+				return v, err
+			}
+			v.fields = append(v.fields, argument{name: name, value: field})
+		}
+		return v, p.advance()
+	case p.tok.kind == tokenInt, p.tok.kind == tokenFloat, p.tok.kind == tokenString:
+		return v, p.advance()
+	case p.tok.kind == tokenName:
+		switch p.tok.value {
+		case "true", "false":
+			v.kind = valueBool
+		case "null":
+			v.kind = valueNull
+		default:
+			v.kind = valueEnum
+		}
+		return v, p.advance()
+	}
+	return v, p.errorf("unexpected %s", p.describe())
+}
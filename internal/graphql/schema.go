@@ -0,0 +1,175 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Built-in scalar types. Time values are serialized as RFC 3339 strings.
+var scalars = map[string]bool{
+	"ID": true, "String": true, "Int": true, "Float": true, "Boolean": true, "Time": true,
+}
+
+// Params is passed to resolvers
+type Params struct {
+	Source  interface{}            // 父对象，根字段为nil
+	Args    map[string]interface{} // 已按参数类型转换，包含默认值
+	Context interface{}            // 每个请求的上下文，见Request.Context
+}
+
+// ResolveFunc returns the value of a field for one source object
+type ResolveFunc func(p Params) (interface{}, error)
+
+// BatchFunc returns the values of a field for many source objects at once,
+// in the order of sources. It is the data loader of a field: a field of a
+// list of objects is resolved with one call instead of one call per object.
+type BatchFunc func(p Params, sources []interface{}) ([]interface{}, error)
+
+// Arg is an argument of a field
+type Arg struct {
+	Name        string
+	Type        string      // 类型引用，例如 "Int" 或 "ID!"
+	Default     interface{} // 未提供时的值
+	Description string
+}
+
+// Field is a field of an object type. Without Resolve or Batch the value is
+// read from the source: a map key, or an exported struct field whose json
+// tag or name matches the field name.
+type Field struct {
+	Name        string
+	Type        string // 类型引用，例如 "[Repository!]!"
+	Args        []Arg
+	Description string
+	Resolve     ResolveFunc
+	Batch       BatchFunc
+}
+
+func (f *Field) arg(name string) (Arg, bool) {
+	for _, a := range f.Args {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Arg{}, false
+}
+
+// Object is an object type
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+func (o *Object) field(name string) (*Field, bool) {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Schema holds the query type and every object type reachable from it
+type Schema struct {
+	query *Object
+	types map[string]*Object
+}
+
+// NewSchema checks that every type referenced by a field or argument is
+// defined and returns the schema
+func NewSchema(query *Object, types ...*Object) (*Schema, error) {
+	s := &Schema{query: query, types: map[string]*Object{query.Name: query}}
+	for _, t := range types {
+		if _, dup := s.types[t.Name]; dup || scalars[t.Name] {
+			return nil, fmt.Errorf("type %s is defined twice", t.Name)
+		}
+		s.types[t.Name] = t
+	}
+	for _, t := range s.types {
+		for _, f := range t.Fields {
+			if _, ok := s.types[namedType(f.Type)]; !ok && !scalars[namedType(f.Type)] {
+				return nil, fmt.Errorf("field %s.%s has unknown type %s", t.Name, f.Name, f.Type)
+			}
+			for _, a := range f.Args {
+				if !scalars[namedType(a.Type)] {
+					return nil, fmt.Errorf("argument %s of %s.%s must be a scalar", a.Name, t.Name, f.Name)
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// SDL returns the schema in the GraphQL schema definition language
+func (s *Schema) SDL() string {
+	var b strings.Builder
+	names := make([]string, 0, len(s.types))
+	for name := range s.types {
+		if name != s.query.Name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	b.WriteString("scalar Time\n")
+	for _, name := range append([]string{s.query.Name}, names...) {
+		t := s.types[name]
+		b.WriteString("\n")
+		writeDescription(&b, "", t.Description)
+		fmt.Fprintf(&b, "type %s {\n", t.Name)
+		for _, f := range t.Fields {
+			writeDescription(&b, "  ", f.Description)
+			b.WriteString("  " + f.Name)
+			if len(f.Args) > 0 {
+				args := make([]string, len(f.Args))
+				for i, a := range f.Args {
+					args[i] = a.Name + ": " + a.Type
+					if a.Default != nil {
+						args[i] += " = " + literal(a.Default)
+					}
+				}
+				b.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			b.WriteString(": " + f.Type + "\n")
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description != "" {
+		fmt.Fprintf(b, "%s%q\n", indent, description)
+	}
+}
+
+// literal formats a default value as a GraphQL literal
+func literal(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(v)
+}
+
+// namedType strips the list and non-null markers from a type reference
+func namedType(typ string) string {
+	return strings.Trim(typ, "[]!")
+}
+
+// isNonNull reports whether typ is a non-null type and returns the nullable
+// type
+func isNonNull(typ string) (string, bool) {
+	if strings.HasSuffix(typ, "!") {
+		return strings.TrimSuffix(typ, "!"), true
+	}
+	return typ, false
+}
+
+// listItem returns the item type of a nullable list type
+func listItem(typ string) (string, bool) {
+	if strings.HasPrefix(typ, "[") && strings.HasSuffix(typ, "]") {
+		return typ[1 : len(typ)-1], true
+	}
+	return "", false
+}
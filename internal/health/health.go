@@ -0,0 +1,185 @@
+// Package health validates scraped data against the field fill-rates each
+// source normally reaches and keeps a health record per source, so a changed
+// selector shows up as a degraded source instead of silently broken data.
+package health
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// Source names of the records that are not paper sources. Paper sources use
+// the Source field of their papers, such as "Dev.to" or "机器之心".
+const (
+	SourceTrending    = "GitHub Trending"
+	SourceHuggingFace = "Hugging Face"
+)
+
+// Status of a source
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded" // 返回了数据，但字段填充率低于预期
+	StatusFailed   = "failed"   // 请求失败或没有返回数据
+)
+
+// Rule is the minimum share of items that must have a field filled
+type Rule struct {
+	Field   string
+	MinRate float64
+}
+
+// Expected fill-rates per kind of source. Trending pages always show stars,
+// the daily gain and nearly always a description, so a drop means a selector
+// no longer matches.
+var (
+	TrendingRules = []Rule{
+		{Field: "stars", MinRate: 0.9},
+		{Field: "gained_stars", MinRate: 0.8},
+		{Field: "description", MinRate: 0.7},
+	}
+	PaperRules = []Rule{
+		{Field: "title", MinRate: 0.9},
+		{Field: "url", MinRate: 0.9},
+		{Field: "published_date", MinRate: 0.9},
+	}
+	// UndatedPaperRules apply to the sources listed in UndatedSources
+	UndatedPaperRules = []Rule{
+		{Field: "title", MinRate: 0.9},
+		{Field: "url", MinRate: 0.9},
+	}
+	HubRules = []Rule{
+		{Field: "id", MinRate: 0.9},
+		{Field: "created_at", MinRate: 0.9},
+	}
+)
+
+// UndatedSources are the paper sources whose pages show no publication
+// date. Their dates are always estimated, so the published_date rule is not
+// applied to them.
+var UndatedSources = map[string]bool{
+	"CSDN":  true,
+	"InfoQ": true,
+}
+
+// Report is the result of validating one batch of a source
+type Report struct {
+	Source    string             `json:"source"`
+	Items     int                `json:"items"`
+	FillRates map[string]float64 `json:"fill_rates,omitempty"`
+	Problems  []string           `json:"problems,omitempty"` // 低于预期的字段说明
+}
+
+// Degraded reports whether any field is below its expected fill-rate
+func (r Report) Degraded() bool {
+	return len(r.Problems) > 0
+}
+
+// DegradedError is returned by scrapers when the data they parsed fails
+// validation, so callers keep their previous data
+type DegradedError struct {
+	Report Report
+}
+
+func (e *DegradedError) Error() string {
+	return fmt.Sprintf("%s degraded: %s", e.Report.Source, strings.Join(e.Report.Problems, "; "))
+}
+
+// check computes the fill-rate of every rule. filled reports whether item i
+// has the field set. An empty batch is not degraded, it is a failure the
+// caller reports separately.
+func check(source string, n int, rules []Rule, filled func(i int, field string) bool) Report {
+	report := Report{Source: source, Items: n}
+	if n == 0 {
+		return report
+	}
+	report.FillRates = make(map[string]float64, len(rules))
+	for _, rule := range rules {
+		count := 0
+		for i := 0; i < n; i++ {
+			if filled(i, rule.Field) {
+				count++
+			}
+		}
+		rate := float64(count) / float64(n)
+		report.FillRates[rule.Field] = rate
+		if rate < rule.MinRate {
+			report.Problems = append(report.Problems, fmt.Sprintf("%s filled for %.0f%% of %d items, expected at least %.0f%%",
+				rule.Field, rate*100, n, rule.MinRate*100))
+		}
+	}
+	return report
+}
+
+// CheckTrending validates repositories parsed from the trending pages, before
+// the GitHub API fills in missing values
+func CheckTrending(repos []models.Repository) Report {
+	return check(SourceTrending, len(repos), TrendingRules, func(i int, field string) bool {
+		switch field {
+		case "stars":
+			return repos[i].Stars > 0
+		case "gained_stars":
+			return repos[i].GainedStars > 0
+		case "description":
+			return repos[i].Description != ""
+		}
+		return false
+	})
+}
+
+// CheckPapers validates the papers of one source. A publication date is
+// valid when it was parsed and is not in the future; dates the scraper
+// estimated because parsing failed count as unfilled.
+func CheckPapers(source string, papers []models.Paper, now time.Time) Report {
+	rules := PaperRules
+	if UndatedSources[source] {
+		rules = UndatedPaperRules
+	}
+	return check(source, len(papers), rules, func(i int, field string) bool {
+		switch field {
+		case "title":
+			return strings.TrimSpace(papers[i].Title) != ""
+		case "url":
+			return strings.HasPrefix(papers[i].URL, "http")
+		case "published_date":
+			d := papers[i].PublishedDate
+			return !papers[i].DateEstimated && !d.IsZero() && !d.After(now.Add(24*time.Hour))
+		}
+		return false
+	})
+}
+
+// CheckHubItems validates the items of the Hugging Face Hub listings
+func CheckHubItems(items []models.HubItem) Report {
+	return check(SourceHuggingFace, len(items), HubRules, func(i int, field string) bool {
+		switch field {
+		case "id":
+			return strings.Contains(items[i].ID, "/")
+		case "created_at":
+			return !items[i].CreatedAt.IsZero()
+		}
+		return false
+	})
+}
+
+// GroupPapers splits papers by their source, keeping the order within each
+func GroupPapers(papers []models.Paper) map[string][]models.Paper {
+	groups := make(map[string][]models.Paper)
+	for _, p := range papers {
+		groups[p.Source] = append(groups[p.Source], p)
+	}
+	return groups
+}
+
+// sortedKeys returns the sources of groups in a stable order
+func sortedKeys(groups map[string][]models.Paper) []string {
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,180 @@
+package health
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+var now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// trendingRepos returns n repositories with every checked field filled,
+// except stars for the first missingStars of them
+func trendingRepos(n, missingStars int) []models.Repository {
+	repos := make([]models.Repository, n)
+	for i := range repos {
+		repos[i] = models.Repository{Name: "owner/repo", Description: "LLM inference", Stars: 1200, GainedStars: 80}
+		if i < missingStars {
+			repos[i].Stars = 0
+		}
+	}
+	return repos
+}
+
+func TestCheckTrending(t *testing.T) {
+	tests := []struct {
+		name         string
+		repos        []models.Repository
+		wantDegraded bool
+	}{
+		{"all filled", trendingRepos(20, 0), false},
+		{"stars at the threshold", trendingRepos(20, 2), false},
+		{"stars selector broken", trendingRepos(20, 20), true},
+		{"stars below the threshold", trendingRepos(20, 3), true},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckTrending(tt.repos)
+			if report.Degraded() != tt.wantDegraded {
+				t.Errorf("Degraded() = %v, want %v (problems %q)", report.Degraded(), tt.wantDegraded, report.Problems)
+			}
+			if tt.wantDegraded && !strings.HasPrefix(report.Problems[0], "stars filled for") {
+				t.Errorf("unexpected problem %q", report.Problems[0])
+			}
+		})
+	}
+}
+
+func TestCheckPapers(t *testing.T) {
+	good := models.Paper{Title: "Scaling laws", URL: "https://example.com/a", PublishedDate: now.AddDate(0, 0, -2)}
+	tests := []struct {
+		name      string
+		broken    func(p *models.Paper)
+		wantField string
+	}{
+		{"unparsed date", func(p *models.Paper) { p.PublishedDate = time.Time{} }, "published_date"},
+		{"estimated date", func(p *models.Paper) { p.DateEstimated = true }, "published_date"},
+		{"future date", func(p *models.Paper) { p.PublishedDate = now.AddDate(1, 0, 0) }, "published_date"},
+		{"relative link", func(p *models.Paper) { p.URL = "/articles/1" }, "url"},
+		{"empty title", func(p *models.Paper) { p.Title = " " }, "title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			papers := []models.Paper{good, good, good}
+			for i := range papers[1:] {
+				tt.broken(&papers[i+1])
+			}
+			report := CheckPapers("Dev.to", papers, now)
+			if len(report.Problems) != 1 || !strings.HasPrefix(report.Problems[0], tt.wantField+" ") {
+				t.Errorf("problems = %q, want one about %s", report.Problems, tt.wantField)
+			}
+		})
+	}
+
+	if report := CheckPapers("Dev.to", []models.Paper{good}, now); report.Degraded() {
+		t.Errorf("valid paper reported degraded: %q", report.Problems)
+	}
+
+	// 页面本身没有日期的来源不检查发布日期
+	undated := good
+	undated.DateEstimated = true
+	if report := CheckPapers("CSDN", []models.Paper{undated}, now); report.Degraded() {
+		t.Errorf("undated source reported degraded: %q", report.Problems)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	if _, changed := tracker.Observe(CheckTrending(trendingRepos(10, 0)), nil, now); changed {
+		t.Error("a healthy first observation should not count as a change")
+	}
+	if got := tracker.Status(); got != StatusOK {
+		t.Fatalf("Status() = %s, want ok", got)
+	}
+
+	later := now.Add(time.Hour)
+	broken := &DegradedError{Report: CheckTrending(trendingRepos(10, 10))}
+	if _, changed := tracker.Observe(Report{Source: SourceTrending}, broken, later); !changed {
+		t.Error("ok -> degraded should count as a change")
+	}
+	if _, changed := tracker.Observe(Report{Source: SourceTrending}, broken, later); changed {
+		t.Error("degraded -> degraded should not count as a change")
+	}
+	rec := tracker.Records()[0]
+	if rec.Status != StatusDegraded || rec.FillRates["stars"] != 0 || !rec.LastSuccess.Equal(now) || !rec.CheckedAt.Equal(later) {
+		t.Errorf("unexpected degraded record %+v", rec)
+	}
+
+	tracker.Observe(Report{Source: SourceHuggingFace}, errors.New("timeout"), later)
+	if got := tracker.Status(); got != StatusFailed {
+		t.Errorf("Status() = %s, want failed", got)
+	}
+	if recs := tracker.Records(); len(recs) != 2 || recs[1].Error != "timeout" {
+		t.Errorf("unexpected records %+v", recs)
+	}
+}
+
+func TestMergePapers(t *testing.T) {
+	paper := func(source, title string, date time.Time) models.Paper {
+		return models.Paper{Source: source, Title: title, URL: "https://example.com/" + title, PublishedDate: date}
+	}
+	previous := []models.Paper{
+		paper("机器之心", "old-jqzx", now.AddDate(0, 0, -1)),
+		paper("InfoQ", "old-infoq", now.AddDate(0, 0, -1)),
+	}
+	fresh := []models.Paper{
+		paper("InfoQ", "new-infoq", now),
+		paper("机器之心", "broken-1", time.Time{}),
+		paper("机器之心", "broken-2", time.Time{}),
+		paper("Dev.to", "new-devto", now),
+	}
+
+	merged, reports, missing := MergePapers(fresh, previous, now)
+	var titles []string
+	for _, p := range merged {
+		titles = append(titles, p.Title)
+	}
+	if got := strings.Join(titles, ","); got != "new-infoq,new-devto,old-jqzx" {
+		t.Errorf("merged = %s", got)
+	}
+	if len(reports) != 3 || reports[2].Source != "机器之心" || !reports[2].Degraded() || reports[0].Degraded() || reports[1].Degraded() {
+		t.Errorf("unexpected reports %+v", reports)
+	}
+	if len(missing) != 0 {
+		t.Errorf("missing = %q, want none", missing)
+	}
+}
+
+// TestMergePapersMissingSource checks that a source returning nothing keeps
+// its previous papers and is reported
+func TestMergePapersMissingSource(t *testing.T) {
+	previous := []models.Paper{
+		{Source: "InfoQ", Title: "old-infoq", URL: "https://example.com/old-infoq"},
+		{Source: "Dev.to", Title: "old-devto", URL: "https://example.com/old-devto", PublishedDate: now},
+	}
+	fresh := []models.Paper{
+		{Source: "Dev.to", Title: "new-devto", URL: "https://example.com/new-devto", PublishedDate: now},
+	}
+
+	merged, reports, missing := MergePapers(fresh, previous, now)
+	var titles []string
+	for _, p := range merged {
+		titles = append(titles, p.Title)
+	}
+	if got := strings.Join(titles, ","); got != "new-devto,old-infoq" {
+		t.Errorf("merged = %s", got)
+	}
+	if len(reports) != 1 || len(missing) != 1 || missing[0] != "InfoQ" {
+		t.Errorf("reports = %+v, missing = %q", reports, missing)
+	}
+
+	tracker := NewTracker()
+	rec, _ := tracker.Observe(Report{Source: "InfoQ"}, ErrNoItems, now)
+	if rec.Status != StatusFailed || rec.Error != ErrNoItems.Error() {
+		t.Errorf("unexpected record %+v", rec)
+	}
+}
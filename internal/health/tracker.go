@@ -0,0 +1,141 @@
+package health
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// Record is the health of one source as of its latest refresh
+type Record struct {
+	Source      string             `json:"source"`
+	Status      string             `json:"status"`
+	Items       int                `json:"items"`
+	FillRates   map[string]float64 `json:"fill_rates,omitempty"`
+	Problems    []string           `json:"problems,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	CheckedAt   time.Time          `json:"checked_at"`
+	LastSuccess time.Time          `json:"last_success,omitempty"` // 最近一次数据通过校验的时间
+}
+
+// Tracker keeps the health record of every source. It is safe for
+// concurrent use.
+type Tracker struct {
+	mu      sync.RWMutex
+	records map[string]*Record
+}
+
+// NewTracker returns an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{records: make(map[string]*Record)}
+}
+
+// Observe records the outcome of refreshing a source. A *DegradedError marks
+// the source degraded, any other error marks it failed. It returns the new
+// record and whether the status changed; the first record of a source counts
+// as a change unless it is ok.
+func (t *Tracker) Observe(report Report, err error, now time.Time) (Record, bool) {
+	var degraded *DegradedError
+	if errors.As(err, &degraded) {
+		report = degraded.Report
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rec, ok := t.records[report.Source]
+	if !ok {
+		rec = &Record{Source: report.Source, Status: StatusOK}
+		t.records[report.Source] = rec
+	}
+	previous := rec.Status
+	rec.Items = report.Items
+	rec.FillRates = report.FillRates
+	rec.Problems = report.Problems
+	rec.Error = ""
+	rec.CheckedAt = now
+
+	switch {
+	case err != nil && degraded == nil:
+		rec.Status = StatusFailed
+		rec.Error = err.Error()
+	case report.Degraded():
+		rec.Status = StatusDegraded
+	default:
+		rec.Status = StatusOK
+		rec.LastSuccess = now
+	}
+	return *rec, rec.Status != previous
+}
+
+// Records returns a copy of all records sorted by source
+func (t *Tracker) Records() []Record {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	records := make([]Record, 0, len(t.records))
+	for _, rec := range t.records {
+		records = append(records, *rec)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Source < records[j].Source })
+	return records
+}
+
+// Status returns the overall status: failed or degraded when any source is,
+// ok otherwise
+func (t *Tracker) Status() string {
+	status := StatusOK
+	for _, rec := range t.Records() {
+		switch rec.Status {
+		case StatusFailed:
+			return StatusFailed
+		case StatusDegraded:
+			status = StatusDegraded
+		}
+	}
+	return status
+}
+
+// ErrNoItems is the error observed for a source that returned nothing
+var ErrNoItems = errors.New("no items returned")
+
+// MergePapers validates fresh per source and returns the papers to serve
+// together with one report per source. The papers of a degraded source are
+// replaced with its papers in previous, appended after the fresh ones, so
+// broken data never replaces good data. missing lists the sources of
+// previous that returned nothing this time; their previous papers are kept
+// as well.
+func MergePapers(fresh, previous []models.Paper, now time.Time) (merged []models.Paper, reports []Report, missing []string) {
+	groups := GroupPapers(fresh)
+	old := GroupPapers(previous)
+
+	degraded := make(map[string]bool)
+	for _, source := range sortedKeys(groups) {
+		report := CheckPapers(source, groups[source], now)
+		reports = append(reports, report)
+		if report.Degraded() {
+			degraded[source] = true
+		}
+	}
+	// 请求失败或解析不到任何条目的来源不会出现在fresh中
+	for _, source := range sortedKeys(old) {
+		if _, ok := groups[source]; !ok {
+			missing = append(missing, source)
+			degraded[source] = true
+		}
+	}
+
+	merged = make([]models.Paper, 0, len(fresh))
+	for _, p := range fresh {
+		if !degraded[p.Source] {
+			merged = append(merged, p)
+		}
+	}
+	for _, source := range sortedKeys(old) {
+		if degraded[source] {
+			merged = append(merged, old[source]...)
+		}
+	}
+	return merged, reports, missing
+}
@@ -0,0 +1,140 @@
+// Package i18n holds the English and Chinese message catalogs used by the
+// templates and API, picks the language of a request, and translates
+// collected titles and summaries.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang is a supported UI language
+type Lang string
+
+const (
+	English Lang = "en"
+	Chinese Lang = "zh"
+)
+
+// Default is used when a request does not ask for a supported language
+const Default = English
+
+// Supported lists the languages with a message catalog
+var Supported = []Lang{English, Chinese}
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[Lang]map[string]string {
+	result := make(map[Lang]map[string]string)
+	for _, lang := range Supported {
+		data, err := localeFS.ReadFile("locales/" + string(lang) + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("missing message catalog for %s: %v", lang, err))
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("invalid message catalog for %s: %v", lang, err))
+		}
+		result[lang] = messages
+	}
+	return result
+}
+
+// Parse maps a language tag such as "zh-CN" or "en_US" to a supported
+// language
+func Parse(tag string) (Lang, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	for _, lang := range Supported {
+		if tag == string(lang) {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Negotiate picks the language of a request. An explicit choice (the ?lang=
+// parameter or the lang cookie) wins over the Accept-Language header.
+func Negotiate(explicit, acceptLanguage string) Lang {
+	if lang, ok := Parse(explicit); ok {
+		return lang
+	}
+
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang, ok := Parse(fields[0])
+		if !ok {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, found := strings.CutPrefix(strings.TrimSpace(param), "q="); found {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// T returns the message for key in lang, formatted with args. Missing
+// messages fall back to English and then to the key itself.
+func T(lang Lang, key string, args ...interface{}) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// CategoryLabel returns the display name of a model category. Categories
+// are identified by their AIModelKeywords key, some of which are Chinese.
+func CategoryLabel(lang Lang, category string) string {
+	if msg, ok := catalogs[lang]["category."+category]; ok {
+		return msg
+	}
+	return category
+}
+
+// Messages returns all messages of lang whose key starts with prefix, with
+// English messages filling the gaps. It is used to hand catalogs to the
+// browser.
+func Messages(lang Lang, prefix string) map[string]string {
+	result := make(map[string]string)
+	for _, l := range []Lang{Default, lang} {
+		for key, msg := range catalogs[l] {
+			if strings.HasPrefix(key, prefix) {
+				result[key] = msg
+			}
+		}
+	}
+	return result
+}
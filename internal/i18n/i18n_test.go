@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		explicit, accept string
+		want             Lang
+	}{
+		{"", "", English},
+		{"zh", "en-US,en;q=0.9", Chinese},
+		{"fr", "zh-CN,zh;q=0.9", Chinese},
+		{"", "fr-FR,en;q=0.5,zh;q=0.8", Chinese},
+		{"", "zh;q=0,en", English},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.explicit, tt.accept); got != tt.want {
+			t.Errorf("Negotiate(%q, %q) = %q, want %q", tt.explicit, tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for key := range catalogs[English] {
+		if _, ok := catalogs[Chinese][key]; !ok {
+			t.Errorf("zh catalog is missing %q", key)
+		}
+	}
+	for key := range catalogs[Chinese] {
+		if _, ok := catalogs[English][key]; !ok {
+			t.Errorf("en catalog is missing %q", key)
+		}
+	}
+	if got := T(Chinese, "repos.count", 3); got != "3 个仓库" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+// countingTranslator wraps StubTranslator and counts requests
+type countingTranslator struct{ calls int }
+
+func (c *countingTranslator) Name() string { return "counting" }
+
+func (c *countingTranslator) Translate(texts []string, target Lang) ([]string, error) {
+	c.calls++
+	return StubTranslator{}.Translate(texts, target)
+}
+
+func TestTranslatePapersUsesCache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "translations.json")
+	translator := &countingTranslator{}
+	papers := []models.Paper{
+		{Title: "Attention Is All You Need", Summary: "We propose the Transformer."},
+		{Title: "大模型推理优化综述", Summary: "本文总结了大模型推理优化方法。"},
+	}
+	NewLocalizer(translator, cachePath, 0).TranslatePapers(papers)
+
+	if translator.calls != 2 {
+		t.Fatalf("expected one request per target language, got %d", translator.calls)
+	}
+	if got := papers[0].Translations["zh"].Title; got != "[zh] Attention Is All You Need" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if _, ok := papers[0].Translations["en"]; ok {
+		t.Errorf("English paper should not be translated into English")
+	}
+	if got := LocalizePapers(papers, English)[1].Summary; got != "[en] 本文总结了大模型推理优化方法。" {
+		t.Errorf("unexpected localized summary %q", got)
+	}
+	if papers[1].Summary != "本文总结了大模型推理优化方法。" {
+		t.Errorf("LocalizePapers must not modify the originals")
+	}
+
+	// 重新加载缓存后，相同内容不再调用翻译
+	again := &countingTranslator{}
+	cached := []models.Paper{{Title: "Attention Is All You Need", Summary: "We propose the Transformer."}}
+	NewLocalizer(again, cachePath, 0).TranslatePapers(cached)
+	if again.calls != 0 || cached[0].Translations["zh"].Summary == "" {
+		t.Errorf("expected cached translation, got %d calls", again.calls)
+	}
+}
+
+func TestTranslateReposRespectsBudget(t *testing.T) {
+	translator := &countingTranslator{}
+	repos := []models.Repository{{Name: "a/b", Description: "A description longer than the budget"}}
+	NewLocalizer(translator, "", 10).TranslateRepos(repos)
+	if translator.calls != 0 || repos[0].Translations["zh"].Summary != "" {
+		t.Errorf("translation should be skipped when over budget")
+	}
+}
@@ -0,0 +1,390 @@
+package i18n
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gerryyang2025/llm-news/internal/llm"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/store"
+)
+
+// Translator translates a batch of texts into the target language
+type Translator interface {
+	Name() string
+	Translate(texts []string, target Lang) ([]string, error)
+}
+
+// LLMTranslator translates through an OpenAI-compatible chat endpoint
+type LLMTranslator struct {
+	Client *llm.Client
+}
+
+func (t *LLMTranslator) Name() string { return "llm" }
+
+func (t *LLMTranslator) Translate(texts []string, target Lang) ([]string, error) {
+	language := "English"
+	if target == Chinese {
+		language = "Simplified Chinese"
+	}
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []llm.Message{
+		{Role: "system", Content: "Translate each string of the JSON array into " + language + ". " +
+			"Keep product names, model names, repository names and code identifiers unchanged. " +
+			`Reply with a JSON object {"translations": [...]} holding the translated strings in the same order.`},
+		{Role: "user", Content: string(input)},
+	}
+	resp, err := t.Client.Chat(messages, 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := llm.ExtractJSON(resp.Content)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Translations []string `json:"translations"`
+	}
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse translations: %w", err)
+	}
+	if len(result.Translations) != len(texts) {
+		return nil, fmt.Errorf("got %d translations for %d texts", len(result.Translations), len(texts))
+	}
+	return result.Translations, nil
+}
+
+// StubTranslator marks texts with the target language instead of
+// translating them. It needs no network access and is meant for local
+// development and tests.
+type StubTranslator struct{}
+
+func (StubTranslator) Name() string { return "stub" }
+
+func (StubTranslator) Translate(texts []string, target Lang) ([]string, error) {
+	result := make([]string, len(texts))
+	for i, text := range texts {
+		result[i] = "[" + string(target) + "] " + text
+	}
+	return result, nil
+}
+
+// DetectLang guesses whether text is Chinese or English from the share of
+// Chinese characters among its letters
+func DetectLang(text string) Lang {
+	han, letters := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+			letters++
+		case unicode.IsLetter(r):
+			letters++
+		}
+	}
+	// 一个汉字约相当于一个英文单词，按字数的20%作为阈值
+	if letters > 0 && float64(han)/float64(letters) >= 0.2 {
+		return Chinese
+	}
+	return English
+}
+
+// Localizer translates collected titles and summaries into every other
+// supported language and stores them in the Translations field
+type Localizer struct {
+	translator Translator
+	budget     int // 每次刷新最多翻译的字符数，0表示不限制
+
+	mu        sync.Mutex
+	cachePath string
+	cache     map[string]string // sha256(目标语言+原文) -> 译文
+}
+
+// NewLocalizer creates a localizer. An empty cachePath disables the cache file.
+func NewLocalizer(translator Translator, cachePath string, budget int) *Localizer {
+	l := &Localizer{
+		translator: translator,
+		budget:     budget,
+		cachePath:  cachePath,
+		cache:      make(map[string]string),
+	}
+	if cachePath == "" {
+		return l
+	}
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Warning: Failed to read translation cache: %v", err)
+		}
+		return l
+	}
+	if err := json.Unmarshal(data, &l.cache); err != nil {
+		log.Printf("Warning: Failed to parse translation cache: %v", err)
+	}
+	return l
+}
+
+var (
+	localizerOnce    sync.Once
+	defaultLocalizer *Localizer
+)
+
+// DefaultLocalizer returns the localizer configured through the environment,
+// built once per process, or nil when machine translation is disabled
+func DefaultLocalizer() *Localizer {
+	localizerOnce.Do(func() {
+		defaultLocalizer = LocalizerFromEnv()
+		if defaultLocalizer != nil {
+			log.Printf("Machine translation enabled using the %s translator", defaultLocalizer.translator.Name())
+		}
+	})
+	return defaultLocalizer
+}
+
+// LocalizerFromEnv builds a localizer from LLM_NEWS_TRANSLATOR ("llm" uses
+// the chat endpoint of llm.ClientFromEnv, "stub" the StubTranslator) and
+// LLM_NEWS_TRANSLATOR_CHAR_BUDGET (default 200000 characters per refresh)
+func LocalizerFromEnv() *Localizer {
+	var translator Translator
+	switch backend := strings.ToLower(strings.TrimSpace(os.Getenv("LLM_NEWS_TRANSLATOR"))); backend {
+	case "":
+		return nil
+	case "llm":
+		translator = &LLMTranslator{Client: llm.ClientFromEnv()}
+	case "stub":
+		translator = StubTranslator{}
+	default:
+		log.Printf("Warning: Unknown translator %q in LLM_NEWS_TRANSLATOR, machine translation disabled", backend)
+		return nil
+	}
+
+	budget := 200000
+	if v, err := strconv.Atoi(os.Getenv("LLM_NEWS_TRANSLATOR_CHAR_BUDGET")); err == nil {
+		budget = v
+	}
+	return NewLocalizer(translator, filepath.Join(store.DataDir(), "translations.json"), budget)
+}
+
+// translationBatch is the number of texts sent per request
+const translationBatch = 20
+
+// TranslatePapers fills the Translations of papers for every supported
+// language other than the one the paper is written in
+func (l *Localizer) TranslatePapers(papers []models.Paper) {
+	var jobs []job
+	for i := range papers {
+		p := &papers[i]
+		source := DetectLang(p.Title + " " + p.Summary)
+		for _, target := range Supported {
+			if target == source {
+				continue
+			}
+			if p.Translations == nil {
+				p.Translations = make(map[string]models.Translation)
+			}
+			jobs = append(jobs,
+				job{p.Translations, target, fieldTitle, p.Title},
+				job{p.Translations, target, fieldSummary, p.Summary},
+				job{p.Translations, target, fieldTLDR, p.TLDR},
+			)
+		}
+	}
+	l.run(jobs, "papers")
+}
+
+// TranslateRepos fills the Translations of repository descriptions and TL;DRs
+func (l *Localizer) TranslateRepos(repos []models.Repository) {
+	var jobs []job
+	for i := range repos {
+		repo := &repos[i]
+		source := DetectLang(repo.Description)
+		for _, target := range Supported {
+			if target == source {
+				continue
+			}
+			if repo.Translations == nil {
+				repo.Translations = make(map[string]models.Translation)
+			}
+			jobs = append(jobs,
+				job{repo.Translations, target, fieldSummary, repo.Description},
+				job{repo.Translations, target, fieldTLDR, repo.TLDR},
+			)
+		}
+	}
+	l.run(jobs, "repositories")
+}
+
+type field int
+
+const (
+	fieldTitle field = iota
+	fieldSummary
+	fieldTLDR
+)
+
+// job is one text to translate and where its translation is stored
+type job struct {
+	translations map[string]models.Translation
+	target       Lang
+	field        field
+	text         string
+}
+
+func (j job) set(translated string) {
+	t := j.translations[string(j.target)]
+	switch j.field {
+	case fieldTitle:
+		t.Title = translated
+	case fieldSummary:
+		t.Summary = translated
+	case fieldTLDR:
+		t.TLDR = translated
+	}
+	j.translations[string(j.target)] = t
+}
+
+func (l *Localizer) run(jobs []job, kind string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 先使用缓存，剩余的按目标语言分批翻译
+	pending := make(map[Lang][]job)
+	for _, j := range jobs {
+		if strings.TrimSpace(j.text) == "" {
+			continue
+		}
+		if translated, ok := l.cache[cacheKey(j.target, j.text)]; ok {
+			j.set(translated)
+			continue
+		}
+		pending[j.target] = append(pending[j.target], j)
+	}
+
+	spent, translated, skipped := 0, 0, 0
+	for _, target := range Supported {
+		list := pending[target]
+		for start := 0; start < len(list); start += translationBatch {
+			end := start + translationBatch
+			if end > len(list) {
+				end = len(list)
+			}
+			batch := list[start:end]
+
+			texts := make([]string, len(batch))
+			size := 0
+			for i, j := range batch {
+				texts[i] = j.text
+				size += len([]rune(j.text))
+			}
+			if l.budget > 0 && spent+size > l.budget {
+				skipped += len(list) - start
+				break
+			}
+			spent += size
+
+			result, err := l.translator.Translate(texts, target)
+			if err != nil {
+				log.Printf("Warning: Failed to translate %s into %s: %v", kind, target, err)
+				continue
+			}
+			for i, j := range batch {
+				j.set(result[i])
+				l.cache[cacheKey(target, j.text)] = result[i]
+			}
+			translated += len(batch)
+		}
+	}
+
+	if translated > 0 {
+		log.Printf("Translated %d texts of %s (%d characters)", translated, kind, spent)
+		l.saveCache()
+	}
+	if skipped > 0 {
+		log.Printf("Warning: Translation budget exhausted, %d texts of %s were not translated", skipped, kind)
+	}
+}
+
+func (l *Localizer) saveCache() {
+	if l.cachePath == "" {
+		return
+	}
+	data, err := json.Marshal(l.cache)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(l.cachePath), 0755); err != nil {
+		log.Printf("Warning: Failed to save translation cache: %v", err)
+		return
+	}
+	tmp := l.cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("Warning: Failed to save translation cache: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, l.cachePath); err != nil {
+		log.Printf("Warning: Failed to save translation cache: %v", err)
+	}
+}
+
+func cacheKey(target Lang, text string) string {
+	sum := sha256.Sum256([]byte(string(target) + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// LocalizePapers returns copies of papers whose title, summary and TL;DR are
+// replaced by their stored translation into lang, where one exists
+func LocalizePapers(papers []models.Paper, lang Lang) []models.Paper {
+	result := make([]models.Paper, len(papers))
+	copy(result, papers)
+	for i := range result {
+		t, ok := result[i].Translations[string(lang)]
+		if !ok {
+			continue
+		}
+		if t.Title != "" {
+			result[i].Title = t.Title
+		}
+		if t.Summary != "" {
+			result[i].Summary = t.Summary
+		}
+		if t.TLDR != "" {
+			result[i].TLDR = t.TLDR
+		}
+	}
+	return result
+}
+
+// LocalizeRepos returns copies of repositories whose description and TL;DR
+// are replaced by their stored translation into lang, where one exists
+func LocalizeRepos(repos []models.Repository, lang Lang) []models.Repository {
+	result := make([]models.Repository, len(repos))
+	copy(result, repos)
+	for i := range result {
+		t, ok := result[i].Translations[string(lang)]
+		if !ok {
+			continue
+		}
+		if t.Summary != "" {
+			result[i].Description = t.Summary
+		}
+		if t.TLDR != "" {
+			result[i].TLDR = t.TLDR
+		}
+	}
+	return result
+}
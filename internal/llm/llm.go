@@ -0,0 +1,146 @@
+// Package llm is a minimal client for OpenAI-compatible chat completion
+// endpoints. OpenAI, Ollama (http://localhost:11434/v1), llama.cpp server
+// and vLLM all expose this API, as can a test stub.
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Message is one chat message
+type Message struct {
+	Role    string `json:"role"` // system, user 或 assistant
+	Content string `json:"content"`
+}
+
+// Response is the first choice of a chat completion
+type Response struct {
+	Content     string
+	TotalTokens int // 服务端未返回usage时为0
+}
+
+// Client calls the /chat/completions endpoint under BaseURL
+type Client struct {
+	BaseURL string // 默认 https://api.openai.com/v1
+	APIKey  string
+	Model   string // 默认 gpt-4o-mini
+}
+
+// ClientFromEnv builds a client from LLM_NEWS_LLM_URL, LLM_NEWS_LLM_API_KEY
+// (falling back to OPENAI_API_KEY) and LLM_NEWS_LLM_MODEL
+func ClientFromEnv() *Client {
+	c := &Client{
+		BaseURL: os.Getenv("LLM_NEWS_LLM_URL"),
+		APIKey:  os.Getenv("LLM_NEWS_LLM_API_KEY"),
+		Model:   os.Getenv("LLM_NEWS_LLM_MODEL"),
+	}
+	if c.APIKey == "" {
+		c.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	return c
+}
+
+// ModelName returns the configured model or the default one
+func (c *Client) ModelName() string {
+	if c.Model == "" {
+		return "gpt-4o-mini"
+	}
+	return c.Model
+}
+
+// Chat sends the messages and returns the reply. With jsonMode set the
+// server is asked for a JSON object; servers that ignore the hint still
+// work when the prompt asks for JSON.
+func (c *Client) Chat(messages []Message, maxTokens int, jsonMode bool) (Response, error) {
+	baseURL := strings.TrimRight(c.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+
+	payload := map[string]interface{}{
+		"model":       c.ModelName(),
+		"messages":    messages,
+		"temperature": 0.2,
+	}
+	if maxTokens > 0 {
+		payload["max_tokens"] = maxTokens
+	}
+	if jsonMode {
+		payload["response_format"] = map[string]string{"type": "json_object"}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Response{}, err
+	}
+
+	req, err := http.NewRequest("POST", baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	// 本地模型生成较慢，超时时间放宽
+	client := &http.Client{Timeout: 120 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to call chat endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Response{}, fmt.Errorf("chat endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message Message `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			TotalTokens int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Response{}, fmt.Errorf("failed to decode chat response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return Response{}, fmt.Errorf("chat endpoint returned no choices")
+	}
+	return Response{Content: result.Choices[0].Message.Content, TotalTokens: result.Usage.TotalTokens}, nil
+}
+
+// EstimateTokens roughly estimates the token count of text: about four
+// characters per token for English and one per CJK character
+func EstimateTokens(text string) int {
+	tokens := 0
+	ascii := 0
+	for _, r := range text {
+		if r < 0x80 {
+			ascii++
+		} else {
+			tokens++
+		}
+	}
+	return tokens + (ascii+3)/4
+}
+
+// ExtractJSON returns the outermost JSON object in a reply, tolerating
+// Markdown code fences and text around it
+func ExtractJSON(content string) (string, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("no JSON object in reply")
+	}
+	return content[start : end+1], nil
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+var (
+	arxivIDRe    = regexp.MustCompile(`arxiv\.org/(?:abs|pdf)/(\d{4}\.\d{4,5})`)
+	doiRe        = regexp.MustCompile(`(?i)\b(10\.\d{4,9}/[^\s?#"<>]+)`)
+	githubNameRe = regexp.MustCompile(`github\.com/([\w.-]+/[\w.-]+)`)
+)
+
+// PaperID returns the stable identifier of a paper: its arXiv ID when the
+// URL points to arXiv, "doi:" plus the DOI when the URL contains one, and
+// otherwise "h" plus a short hash of the URL (or of the title when the URL
+// is empty). The ID never contains a slash so it fits in one path segment.
+func PaperID(url, title string) string {
+	if m := arxivIDRe.FindStringSubmatch(url); m != nil {
+		return m[1]
+	}
+	if m := doiRe.FindStringSubmatch(url); m != nil {
+		doi := strings.TrimRight(strings.ToLower(m[1]), "./")
+		return "doi:" + strings.ReplaceAll(doi, "/", "_")
+	}
+	source := strings.TrimRight(strings.ToLower(strings.TrimSpace(url)), "/")
+	if source == "" {
+		source = strings.ToLower(strings.TrimSpace(title))
+	}
+	sum := sha1.Sum([]byte(source))
+	return "h" + hex.EncodeToString(sum[:])[:12]
+}
+
+// RepoID returns the stable identifier of a repository, its lowercase
+// owner/name. GitHub URLs are accepted as well as plain names.
+func RepoID(name string) string {
+	name = strings.TrimSpace(name)
+	if m := githubNameRe.FindStringSubmatch(name); m != nil {
+		name = m[1]
+	}
+	return strings.ToLower(strings.TrimSuffix(strings.Trim(name, "/"), ".git"))
+}
+
+// AssignRepoIDs sets the ID of every repository
+func AssignRepoIDs(repos []Repository) {
+	for i := range repos {
+		repos[i].ID = RepoID(repos[i].Name)
+	}
+}
+
+// AssignPaperIDs sets the ID of every paper
+func AssignPaperIDs(papers []Paper) {
+	for i := range papers {
+		papers[i].ID = PaperID(papers[i].URL, papers[i].Title)
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import "testing"
+
+func TestPaperID(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		title string
+		want  string
+	}{
+		{"arxiv abs", "http://arxiv.org/abs/2401.00001v2", "", "2401.00001"},
+		{"arxiv pdf", "https://arxiv.org/pdf/2401.00001v3", "", "2401.00001"},
+		{"doi", "https://doi.org/10.18653/v1/2023.ACL-long.1", "", "doi:10.18653_v1_2023.acl-long.1"},
+		{"acm doi", "https://dl.acm.org/doi/10.1145/3442188.3445922?ref=x", "", "doi:10.1145_3442188.3445922"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PaperID(tt.url, tt.title); got != tt.want {
+				t.Errorf("PaperID(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+
+	// 其他链接使用URL哈希，大小写和末尾斜杠不影响结果
+	a, b := PaperID("https://example.com/post/", "x"), PaperID("https://EXAMPLE.com/post", "y")
+	if a != b || len(a) != 13 {
+		t.Errorf("non-arXiv IDs should be stable hashes of the URL: %q, %q", a, b)
+	}
+	if PaperID("", "A Title") == PaperID("", "Another Title") {
+		t.Error("papers without URL should be identified by title")
+	}
+}
+
+func TestRepoID(t *testing.T) {
+	tests := map[string]string{
+		"Meta-Llama/Llama":                        "meta-llama/llama",
+		"https://github.com/acme/agent.git":       "acme/agent",
+		"https://github.com/acme/agent/tree/main": "acme/agent",
+		" acme/agent/ ":                           "acme/agent",
+	}
+	for in, want := range tests {
+		if got := RepoID(in); got != want {
+			t.Errorf("RepoID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package models
 
 import (
-	"strings"
+	"sort"
+	"sync"
 	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/textmatch"
 )
 
 // Repository represents a GitHub repository
 type Repository struct {
+	ID             string       `json:"id"` // 稳定标识，小写的owner/name
 	Name           string       `json:"name"`
 	URL            string       `json:"url"`
 	Description    string       `json:"description"`
@@ -17,18 +21,40 @@ type Repository struct {
 	GainedForks    int          `json:"gained_forks"`
 	LastUpdated    time.Time    `json:"last_updated"`
 	LastCommit     time.Time    `json:"last_commit"`
+	CreatedAt      time.Time    `json:"created_at"`   // 仓库创建时间，来自GitHub API
 	TechStack      []string     `json:"tech_stack"`
 	TrendMetrics   TrendMetrics `json:"trend_metrics"`
+	Breakout       *Breakout    `json:"breakout,omitempty"` // 增长异常时的突破信号，否则为nil
 	RelevanceScore float64      `json:"relevance_score"`
+	ScoreBreakdown map[string]float64 `json:"score_breakdown,omitempty"` // 相关性分数的各项组成
 	HasDocs        bool         `json:"has_docs"`
 	HasWiki        bool         `json:"has_wiki"`   // 是否有Wiki文档
 	HasReadme      bool         `json:"has_readme"` // 是否有README文档
 	DocsURL        string       `json:"docs_url"`   // 文档URL
+	DocQuality     *DocQuality  `json:"doc_quality,omitempty"` // README分析结果，未分析时为nil
 	ModelCategories []string    `json:"model_categories"` // 模型分类
+	CategoryScores map[string]float64 `json:"category_scores,omitempty"` // 语义分类器给出的各分类概率
 	Source         string       `json:"source"`           // 数据来源，如"GitHub"、"Papers with Code"、"arXiv"
 	PaperURL       string       `json:"paper_url"`        // 论文URL
 	PaperTitle     string       `json:"paper_title"`      // 论文标题
 	Authors        []string     `json:"authors"`          // 作者列表
+	TLDR           string       `json:"tldr,omitempty"`           // LLM生成的一句话简介
+	Highlights     []string     `json:"highlights,omitempty"`     // LLM生成的主要特性
+	KeyTechniques  []string     `json:"key_techniques,omitempty"` // LLM生成的技术标签
+	Translations   map[string]Translation `json:"translations,omitempty"` // 机器翻译，按语言代码索引
+}
+
+// DocQuality is the result of analyzing a repository README
+type DocQuality struct {
+	Score            float64  `json:"score"` // 0-1
+	Installation     bool     `json:"installation"`
+	Usage            bool     `json:"usage"`
+	License          bool     `json:"license"`
+	Benchmarks       bool     `json:"benchmarks"`
+	ModelWeights     bool     `json:"model_weights"`
+	Citation         bool     `json:"citation"`
+	HuggingFaceLinks []string `json:"huggingface_links,omitempty"` // 模型权重、数据集等Hugging Face链接
+	PaperLinks       []string `json:"paper_links,omitempty"`       // arXiv等论文链接
 }
 
 // TrendMetrics captures trending information
@@ -38,14 +64,27 @@ type TrendMetrics struct {
 	Views7d  int `json:"views_7d"`
 }
 
+// Breakout flags a repository whose star velocity jumped well above its own
+// history or the rest of the trending repositories
+type Breakout struct {
+	Velocity float64  `json:"velocity"` // 当前每日新增星标
+	Baseline float64  `json:"baseline"` // 历史每日新增星标的EWMA，无历史时为0
+	SelfZ    float64  `json:"self_z"`   // 相对自身历史的z分数
+	CohortZ  float64  `json:"cohort_z"` // 相对同批仓库的z分数（对数尺度）
+	Reasons  []string `json:"reasons"`  // "self"、"cohort"
+}
+
 // Paper represents a research paper
 type Paper struct {
+	ID                   string    `json:"id"` // 稳定标识：arXiv ID、"doi:"前缀的DOI或URL哈希
 	Title                string    `json:"title"`
 	URL                  string    `json:"url"`
 	Authors              []string  `json:"authors"`
 	PublishedDate        time.Time `json:"published_date"`
+	DateEstimated        bool      `json:"date_estimated,omitempty"` // 无法解析发布日期时为true，PublishedDate只是估计值
 	Source               string    `json:"source"` // ArXiv, ACL, etc.
 	Summary              string    `json:"summary"`
+	TLDR                 string    `json:"tldr,omitempty"` // LLM生成的一句话总结
 	Keywords             []string  `json:"keywords"`
 	CitationCount        int       `json:"citation_count"`
 	CitationVelocity     float64   `json:"citation_velocity"`
@@ -55,6 +94,42 @@ type Paper struct {
 	KeyTechniques        []string  `json:"key_techniques"`
 	CodeSnippet          string    `json:"code_snippet"`
 	ArchitectureDiagram  string    `json:"architecture_diagram"`
+	Translations         map[string]Translation `json:"translations,omitempty"` // 机器翻译，按语言代码索引
+}
+
+// Translation holds the machine-translated text of a repository or paper,
+// stored next to the original fields
+type Translation struct {
+	Title   string `json:"title,omitempty"`
+	Summary string `json:"summary,omitempty"` // 论文摘要或仓库描述
+	TLDR    string `json:"tldr,omitempty"`
+}
+
+// Hugging Face Hub item kinds
+const (
+	HubModel   = "model"
+	HubDataset = "dataset"
+	HubSpace   = "space"
+)
+
+// HubItem represents a trending model, dataset or Space on the Hugging Face Hub
+type HubItem struct {
+	ID              string    `json:"id"`   // 如"meta-llama/Llama-3.1-8B"
+	Kind            string    `json:"kind"` // model、dataset或space
+	URL             string    `json:"url"`
+	Author          string    `json:"author"`
+	Likes           int       `json:"likes"`
+	Downloads       int       `json:"downloads"` // Hub统计的近30天下载量，Space没有该字段
+	TrendingScore   float64   `json:"trending_score"`
+	PipelineTag     string    `json:"pipeline_tag,omitempty"` // 如"text-generation"
+	Library         string    `json:"library,omitempty"`      // 如"transformers"
+	SDK             string    `json:"sdk,omitempty"`          // Space使用的SDK，如"gradio"
+	Tags            []string  `json:"tags"`
+	CreatedAt       time.Time `json:"created_at"`
+	GitHubURL       string    `json:"github_url,omitempty"` // 模型卡片引用的GitHub仓库
+	PaperURLs       []string  `json:"paper_urls,omitempty"` // 模型卡片引用的arXiv论文
+	LikesPerDay     float64   `json:"likes_per_day"`
+	DownloadsPerDay float64   `json:"downloads_per_day"`
 }
 
 // DataSource represents external data source configurations
@@ -146,34 +221,96 @@ type FilterCriteria struct {
 	MinStarsGrowthRate    int     // Minimum stars growth per day
 	MaxDaysSinceCommit    int     // Maximum days since last commit
 	RequiresDocumentation bool    // Whether complete documentation is required
+	MinDocQuality         float64 // Minimum README quality score (0-1) when documentation is required
 	MinRelevanceScore     float64 // Minimum relevance score (0-1)
 }
 
-// GetModelCategories 检测仓库属于哪些模型分类
-func (r *Repository) GetModelCategories() []string {
-	if len(r.ModelCategories) > 0 {
-		return r.ModelCategories
-	}
+// Matches reports whether a single repository satisfies the criteria
+func (c FilterCriteria) Matches(repo Repository) bool {
+	return c.MatchesAt(repo, time.Now())
+}
 
-	categories := make(map[string]bool)
-	repoText := strings.ToLower(r.Name + " " + r.Description)
+// MatchesAt reports whether the repository meets the criteria as of now
+func (c FilterCriteria) MatchesAt(repo Repository, now time.Time) bool {
+	// Check minimum stars growth rate
+	if repo.TrendMetrics.Stars24h < c.MinStarsGrowthRate {
+		return false
+	}
 
-	// 检查仓库文本是否包含各个模型分类的关键词
-	for category, keywords := range AIModelKeywords {
-		for _, keyword := range keywords {
-			if strings.Contains(repoText, strings.ToLower(keyword)) {
-				categories[category] = true
-				break
-			}
+	// Check maximum days since last commit
+	if c.MaxDaysSinceCommit > 0 && !repo.LastCommit.IsZero() {
+		daysSinceLastCommit := now.Sub(repo.LastCommit).Hours() / 24
+		if daysSinceLastCommit > float64(c.MaxDaysSinceCommit) {
+			return false
 		}
 	}
 
-	// 将分类结果转换为切片
+	// Check documentation requirement
+	if c.RequiresDocumentation && !repo.HasAdequateDocs(c.MinDocQuality) {
+		return false
+	}
+
+	// Check minimum relevance score
+	return repo.RelevanceScore >= c.MinRelevanceScore
+}
+
+// DefaultMinDocQuality is the README quality required when documentation is
+// required and no explicit minimum is set
+const DefaultMinDocQuality = 0.4
+
+// HasAdequateDocs reports whether the repository is documented well enough.
+// Repositories with an analyzed README must reach minQuality (or
+// DefaultMinDocQuality when it is 0); the others fall back to HasDocs.
+func (r *Repository) HasAdequateDocs(minQuality float64) bool {
+	if r.DocQuality == nil {
+		return r.HasDocs
+	}
+	if minQuality <= 0 {
+		minQuality = DefaultMinDocQuality
+	}
+	return r.DocQuality.Score >= minQuality
+}
+
+var (
+	categoryMatchersOnce sync.Once
+	categoryMatchers     map[string]*textmatch.Matcher
+)
+
+// modelCategoryMatchers compiles a keyword matcher per AIModelKeywords category
+func modelCategoryMatchers() map[string]*textmatch.Matcher {
+	categoryMatchersOnce.Do(func() {
+		categoryMatchers = make(map[string]*textmatch.Matcher, len(AIModelKeywords))
+		for category, keywords := range AIModelKeywords {
+			categoryMatchers[category] = textmatch.New(keywords...)
+		}
+	})
+	return categoryMatchers
+}
+
+// ModelCategoriesOf returns the model categories whose keywords occur in
+// text, sorted by name
+func ModelCategoriesOf(text string) []string {
+	tokens := textmatch.Tokenize(text)
+
+	// 检查文本是否包含各个模型分类的关键词（按词边界匹配）
 	result := []string{}
-	for category := range categories {
-		result = append(result, category)
+	for category, matcher := range modelCategoryMatchers() {
+		if matcher.MatchAnyTokens(tokens) {
+			result = append(result, category)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GetModelCategories 检测仓库属于哪些模型分类
+func (r *Repository) GetModelCategories() []string {
+	if len(r.ModelCategories) > 0 {
+		return r.ModelCategories
 	}
 
+	result := ModelCategoriesOf(r.Name + " " + r.Description)
+
 	// 如果没有匹配的分类，则标记为"其他"
 	if len(result) == 0 {
 		result = append(result, "其他")
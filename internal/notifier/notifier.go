@@ -0,0 +1,263 @@
+package notifier
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/textmatch"
+)
+
+// EventKind describes why a notification was raised
+type EventKind string
+
+const (
+	EventStarVelocity  EventKind = "star_velocity"
+	EventKeyword       EventKind = "keyword"
+	EventModelCategory EventKind = "model_category"
+	EventWatchlist     EventKind = "watchlist"
+)
+
+// Event is a single notification delivered to every target
+type Event struct {
+	Kind   EventKind          `json:"kind"`
+	Title  string             `json:"title"`
+	Text   string             `json:"text"`
+	URL    string             `json:"url"`
+	Reason string             `json:"reason"`
+	Repo   *models.Repository `json:"repo,omitempty"`
+	Time   time.Time          `json:"time"`
+}
+
+// Target delivers events to one chat platform or webhook endpoint
+type Target interface {
+	Name() string
+	Send(client *http.Client, event Event) error
+}
+
+// Rules decide which repositories trigger a notification
+type Rules struct {
+	MinStarsPerDay  int      // TrendMetrics.Stars24h 达到该阈值即通知，0表示不启用
+	Keywords        []string // 名称或描述中出现这些关键词时通知
+	ModelCategories []string // 属于这些AIModelKeywords分类时通知
+}
+
+// Match returns the events raised by a repository under the rules
+func (r Rules) Match(repo models.Repository) []Event {
+	var events []Event
+
+	if r.MinStarsPerDay > 0 && repo.TrendMetrics.Stars24h >= r.MinStarsPerDay {
+		events = append(events, newRepoEvent(EventStarVelocity, repo,
+			fmt.Sprintf("+%d stars in the last 24h (threshold %d)", repo.TrendMetrics.Stars24h, r.MinStarsPerDay)))
+	}
+
+	tokens := textmatch.Tokenize(repo.Name + " " + repo.Description)
+	for _, keyword := range r.Keywords {
+		if tokens.Contains(keyword) {
+			events = append(events, newRepoEvent(EventKeyword, repo, fmt.Sprintf("matches watched keyword %q", keyword)))
+			break
+		}
+	}
+
+	if len(r.ModelCategories) > 0 {
+		categories := repo.GetModelCategories()
+	categoryLoop:
+		for _, watched := range r.ModelCategories {
+			for _, category := range categories {
+				if strings.EqualFold(watched, category) {
+					events = append(events, newRepoEvent(EventModelCategory, repo, fmt.Sprintf("belongs to model category %s", category)))
+					break categoryLoop
+				}
+			}
+		}
+	}
+
+	return events
+}
+
+func newRepoEvent(kind EventKind, repo models.Repository, reason string) Event {
+	r := repo
+	return Event{
+		Kind:   kind,
+		Title:  repo.Name,
+		Text:   repo.Description,
+		URL:    repo.URL,
+		Reason: reason,
+		Repo:   &r,
+		Time:   time.Now(),
+	}
+}
+
+// Notifier evaluates rules against refreshed data and fans events out to targets
+type Notifier struct {
+	rules   Rules
+	targets []Target
+	client  *http.Client
+
+	mu       sync.Mutex
+	sent     map[string]time.Time // 去重：同一仓库同一类事件在cooldown内只通知一次
+	above    map[string]bool      // 已通知过且24h星数仍在阈值之上的仓库
+	cooldown time.Duration
+}
+
+// New creates a notifier delivering to the given targets
+func New(rules Rules, targets ...Target) *Notifier {
+	return &Notifier{
+		rules:    rules,
+		targets:  targets,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		sent:     make(map[string]time.Time),
+		above:    make(map[string]bool),
+		cooldown: 24 * time.Hour,
+	}
+}
+
+// Targets returns the configured targets
+func (n *Notifier) Targets() []Target {
+	return n.targets
+}
+
+// CheckRepositories matches the rules against repositories and notifies every
+// target about events that were not already sent during the cooldown. A
+// star-velocity event is only raised when a repository crosses the threshold,
+// not on every check while it stays above it. It returns the events that
+// were delivered; the others are retried on the next check.
+func (n *Notifier) CheckRepositories(repos []models.Repository) []Event {
+	var events []Event
+	for _, repo := range repos {
+		events = append(events, n.rules.Match(repo)...)
+	}
+
+	delivered := n.Notify(n.dedupe(events))
+	n.markSent(delivered)
+	return delivered
+}
+
+// Notify sends events to every target, logging delivery failures. It returns
+// the events that reached at least one target.
+func (n *Notifier) Notify(events []Event) []Event {
+	var delivered []Event
+	for _, event := range events {
+		ok := false
+		for _, target := range n.targets {
+			if err := target.Send(n.client, event); err != nil {
+				log.Printf("Warning: Failed to notify %s about %s: %v", target.Name(), event.Title, err)
+				continue
+			}
+			ok = true
+		}
+		if ok {
+			delivered = append(delivered, event)
+		}
+	}
+	return delivered
+}
+
+func eventKey(event Event) string {
+	return string(event.Kind) + "|" + strings.ToLower(event.Title)
+}
+
+// dedupe drops the events sent during the cooldown and the star-velocity
+// events of repositories that were already above the threshold
+func (n *Notifier) dedupe(events []Event) []Event {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	var fresh []Event
+	stillAbove := make(map[string]bool)
+	for _, event := range events {
+		star := event.Kind == EventStarVelocity
+		name := strings.ToLower(event.Title)
+		if star && n.above[name] {
+			stillAbove[name] = true
+			continue
+		}
+		if last, ok := n.sent[eventKey(event)]; ok && now.Sub(last) < n.cooldown {
+			if star {
+				stillAbove[name] = true // 冷却期内被跳过的越线不在之后补发
+			}
+			continue
+		}
+		fresh = append(fresh, event)
+	}
+	// 回落到阈值以下的仓库，下次越过阈值时再通知
+	n.above = stillAbove
+
+	// 清理过期的记录，避免map无限增长
+	for key, last := range n.sent {
+		if now.Sub(last) >= n.cooldown {
+			delete(n.sent, key)
+		}
+	}
+	return fresh
+}
+
+// markSent records the delivered events for dedupe
+func (n *Notifier) markSent(events []Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	for _, event := range events {
+		n.sent[eventKey(event)] = now
+		if event.Kind == EventStarVelocity {
+			n.above[strings.ToLower(event.Title)] = true
+		}
+	}
+}
+
+// FromEnv builds a notifier from LLM_NEWS_NOTIFY_* environment variables.
+// It returns nil when no target is configured.
+func FromEnv() *Notifier {
+	var targets []Target
+	if url := os.Getenv("LLM_NEWS_NOTIFY_WEBHOOK_URL"); url != "" {
+		targets = append(targets, &WebhookTarget{URL: url, Secret: os.Getenv("LLM_NEWS_NOTIFY_WEBHOOK_SECRET")})
+	}
+	if url := os.Getenv("LLM_NEWS_NOTIFY_SLACK_WEBHOOK"); url != "" {
+		targets = append(targets, &SlackTarget{WebhookURL: url})
+	}
+	if url := os.Getenv("LLM_NEWS_NOTIFY_FEISHU_WEBHOOK"); url != "" {
+		targets = append(targets, &FeishuTarget{WebhookURL: url, Secret: os.Getenv("LLM_NEWS_NOTIFY_FEISHU_SECRET")})
+	}
+	if url := os.Getenv("LLM_NEWS_NOTIFY_DINGTALK_WEBHOOK"); url != "" {
+		targets = append(targets, &DingTalkTarget{WebhookURL: url, Secret: os.Getenv("LLM_NEWS_NOTIFY_DINGTALK_SECRET")})
+	}
+	if url := os.Getenv("LLM_NEWS_NOTIFY_WECOM_WEBHOOK"); url != "" {
+		targets = append(targets, &WeComTarget{WebhookURL: url})
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	rules := Rules{MinStarsPerDay: 200}
+	if v, err := strconv.Atoi(os.Getenv("LLM_NEWS_NOTIFY_MIN_STARS")); err == nil {
+		rules.MinStarsPerDay = v
+	}
+	rules.Keywords = splitList(os.Getenv("LLM_NEWS_NOTIFY_KEYWORDS"))
+	for _, category := range splitList(os.Getenv("LLM_NEWS_NOTIFY_MODELS")) {
+		if _, ok := models.AIModelKeywords[category]; !ok {
+			log.Printf("Warning: Unknown model category %q in LLM_NEWS_NOTIFY_MODELS", category)
+			continue
+		}
+		rules.ModelCategories = append(rules.ModelCategories, category)
+	}
+
+	return New(rules, targets...)
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
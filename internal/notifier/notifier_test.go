@@ -0,0 +1,260 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// The expected signatures were computed independently with Python's hmac
+// module, following the Feishu and DingTalk documentation.
+func TestSignatures(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"webhook", SignWebhook("s3cret", "1700000000", []byte(`{"kind":"keyword"}`)), "e57984deac886a8d0436f56bd9006e50436100d57d505279f411778bfbd3663e"},
+		{"feishu", SignFeishu("SECxxx", "1599360473"), "LwreZZxjbsFzPj2ewRYSLSOrW7Tb5faNB4UZoY/PPtg="},
+		{"dingtalk", SignDingTalk("SECxxx", "1599360473000"), "AJviBdi6ACziw+89yqdebcOcdVlZAhlTExxOki+kLJc="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+// request is a webhook call recorded by the test server
+type request struct {
+	header http.Header
+	query  url.Values
+	body   map[string]interface{}
+	raw    []byte
+}
+
+// recordServer returns a server answering every call with reply and the
+// calls it received
+func recordServer(t *testing.T, reply string) (*httptest.Server, *[]request) {
+	var calls []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		req := request{header: r.Header, query: r.URL.Query(), raw: raw}
+		if err := json.Unmarshal(raw, &req.body); err != nil {
+			t.Errorf("body is not JSON: %s", raw)
+		}
+		calls = append(calls, req)
+		io.WriteString(w, reply)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+// path reads a nested value of a decoded JSON body, e.g. "card.header.title.content"
+func path(v interface{}, keys string) interface{} {
+	for _, key := range strings.Split(keys, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			v = node[key]
+		case []interface{}:
+			i := int(key[0] - '0')
+			if i >= len(node) {
+				return nil
+			}
+			v = node[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+var testRepo = models.Repository{
+	Name:         "owner/fast-llm",
+	URL:          "https://github.com/owner/fast-llm",
+	Description:  "Fast LLM inference",
+	TrendMetrics: models.TrendMetrics{Stars24h: 500},
+}
+
+func TestTargets(t *testing.T) {
+	tests := []struct {
+		name   string
+		reply  string
+		target func(url string) Target
+		check  func(t *testing.T, r request)
+	}{
+		{
+			name:   "webhook",
+			target: func(u string) Target { return &WebhookTarget{URL: u, Secret: "s3cret"} },
+			check: func(t *testing.T, r request) {
+				if path(r.body, "kind") != string(EventStarVelocity) || path(r.body, "repo.name") != testRepo.Name {
+					t.Errorf("unexpected event %s", r.raw)
+				}
+				want := "sha256=" + SignWebhook("s3cret", r.header.Get("X-LLMNews-Timestamp"), r.raw)
+				if got := r.header.Get("X-LLMNews-Signature"); got != want {
+					t.Errorf("signature = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name:   "slack",
+			reply:  "ok",
+			target: func(u string) Target { return &SlackTarget{WebhookURL: u} },
+			check: func(t *testing.T, r request) {
+				text, _ := path(r.body, "text").(string)
+				if !strings.HasPrefix(text, "*<https://github.com/owner/fast-llm|owner/fast-llm>*") {
+					t.Errorf("text = %q", text)
+				}
+				if path(r.body, "blocks.0.text.type") != "mrkdwn" || path(r.body, "blocks.0.text.text") != text {
+					t.Errorf("unexpected blocks %s", r.raw)
+				}
+			},
+		},
+		{
+			name:   "feishu",
+			reply:  `{"code":0,"msg":"success"}`,
+			target: func(u string) Target { return &FeishuTarget{WebhookURL: u, Secret: "SECxxx"} },
+			check: func(t *testing.T, r request) {
+				if path(r.body, "msg_type") != "interactive" || path(r.body, "card.header.title.content") != testRepo.Name {
+					t.Errorf("unexpected card %s", r.raw)
+				}
+				if path(r.body, "card.elements.1.actions.0.url") != testRepo.URL {
+					t.Errorf("button does not link the repository: %s", r.raw)
+				}
+				timestamp, _ := path(r.body, "timestamp").(string)
+				if path(r.body, "sign") != SignFeishu("SECxxx", timestamp) {
+					t.Errorf("sign does not match timestamp %q", timestamp)
+				}
+			},
+		},
+		{
+			name:   "dingtalk",
+			reply:  `{"errcode":0,"errmsg":"ok"}`,
+			target: func(u string) Target { return &DingTalkTarget{WebhookURL: u + "?access_token=abc", Secret: "SECxxx"} },
+			check: func(t *testing.T, r request) {
+				if path(r.body, "msgtype") != "markdown" || path(r.body, "markdown.title") != testRepo.Name {
+					t.Errorf("unexpected message %s", r.raw)
+				}
+				if r.query.Get("access_token") != "abc" {
+					t.Errorf("access_token was dropped: %v", r.query)
+				}
+				if r.query.Get("sign") != SignDingTalk("SECxxx", r.query.Get("timestamp")) {
+					t.Errorf("sign does not match timestamp: %v", r.query)
+				}
+			},
+		},
+		{
+			name:   "wecom",
+			reply:  `{"errcode":0,"errmsg":"ok"}`,
+			target: func(u string) Target { return &WeComTarget{WebhookURL: u} },
+			check: func(t *testing.T, r request) {
+				content, _ := path(r.body, "markdown.content").(string)
+				if path(r.body, "msgtype") != "markdown" || !strings.HasPrefix(content, "### [owner/fast-llm](https://github.com/owner/fast-llm)") {
+					t.Errorf("unexpected message %s", r.raw)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := recordServer(t, tt.reply)
+			n := New(Rules{MinStarsPerDay: 100}, tt.target(srv.URL))
+
+			if events := n.CheckRepositories([]models.Repository{testRepo}); len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			// 冷却期内同一仓库同一类事件不再发送
+			if events := n.CheckRepositories([]models.Repository{testRepo}); len(events) != 0 {
+				t.Errorf("got %d events on the second check, want 0", len(events))
+			}
+			if len(*calls) != 1 {
+				t.Fatalf("target was called %d times, want 1", len(*calls))
+			}
+			if ct := (*calls)[0].header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			tt.check(t, (*calls)[0])
+		})
+	}
+}
+
+func TestTargetErrorCode(t *testing.T) {
+	srv, _ := recordServer(t, `{"code":19021,"msg":"sign match fail or timestamp is not within one hour from current time"}`)
+	err := (&FeishuTarget{WebhookURL: srv.URL, Secret: "SECxxx"}).Send(http.DefaultClient, Event{Title: "x"})
+	if err == nil || !strings.Contains(err.Error(), "code=19021") {
+		t.Errorf("got error %v, want the Feishu error code", err)
+	}
+}
+
+// fakeTarget records the events it was sent and fails while err is set
+type fakeTarget struct {
+	err    error
+	events []Event
+}
+
+func (f *fakeTarget) Name() string { return "fake" }
+
+func (f *fakeTarget) Send(client *http.Client, event Event) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.events = append(f.events, event)
+	return nil
+}
+
+// TestStarThresholdCrossing checks that a repository is notified when it
+// crosses the threshold, not on every check while it stays above it
+func TestStarThresholdCrossing(t *testing.T) {
+	target := &fakeTarget{}
+	n := New(Rules{MinStarsPerDay: 100}, target)
+	n.cooldown = 0 // 只检查越线逻辑
+
+	steps := []struct {
+		stars int
+		want  int
+	}{
+		{50, 0},
+		{150, 1}, // 越过阈值
+		{180, 0}, // 仍在阈值之上
+		{50, 0},  // 回落
+		{150, 1}, // 再次越过
+	}
+	for i, step := range steps {
+		repo := testRepo
+		repo.TrendMetrics.Stars24h = step.stars
+		if events := n.CheckRepositories([]models.Repository{repo}); len(events) != step.want {
+			t.Errorf("step %d (%d stars): got %d events, want %d", i, step.stars, len(events), step.want)
+		}
+	}
+	if len(target.events) != 2 {
+		t.Errorf("target received %d events, want 2", len(target.events))
+	}
+}
+
+// TestFailedDeliveryRetried checks that an event no target accepted is sent
+// again on the next check
+func TestFailedDeliveryRetried(t *testing.T) {
+	target := &fakeTarget{err: errors.New("status 502")}
+	n := New(Rules{MinStarsPerDay: 100, Keywords: []string{"llm"}}, target)
+
+	if events := n.CheckRepositories([]models.Repository{testRepo}); len(events) != 0 {
+		t.Fatalf("got %d delivered events while the target fails, want 0", len(events))
+	}
+	target.err = nil
+	if events := n.CheckRepositories([]models.Repository{testRepo}); len(events) != 2 {
+		t.Fatalf("got %d events after the target recovered, want 2", len(events))
+	}
+	if events := n.CheckRepositories([]models.Repository{testRepo}); len(events) != 0 {
+		t.Errorf("got %d events once delivered, want 0", len(events))
+	}
+}
@@ -0,0 +1,267 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// WebhookTarget posts the raw event as JSON. When Secret is set, the body is
+// signed with HMAC-SHA256 in the X-LLMNews-Signature header.
+type WebhookTarget struct {
+	URL    string
+	Secret string
+}
+
+func (t *WebhookTarget) Name() string { return "webhook" }
+
+func (t *WebhookTarget) Send(client *http.Client, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+	if t.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		headers["X-LLMNews-Timestamp"] = timestamp
+		headers["X-LLMNews-Signature"] = "sha256=" + SignWebhook(t.Secret, timestamp, body)
+	}
+	return postJSON(client, t.URL, body, headers, nil)
+}
+
+// SignWebhook computes the signature of a generic webhook body: the hex
+// encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SlackTarget posts to a Slack incoming webhook
+type SlackTarget struct {
+	WebhookURL string
+}
+
+func (t *SlackTarget) Name() string { return "slack" }
+
+func (t *SlackTarget) Send(client *http.Client, event Event) error {
+	text := fmt.Sprintf("*<%s|%s>* %s", event.URL, event.Title, event.Reason)
+	if event.Text != "" {
+		text += "\n" + event.Text
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"text": text,
+		"blocks": []interface{}{
+			map[string]interface{}{
+				"type": "section",
+				"text": map[string]string{"type": "mrkdwn", "text": text},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	// Slack成功时返回纯文本 "ok"
+	return postJSON(client, t.WebhookURL, body, nil, nil)
+}
+
+// FeishuTarget posts an interactive card to a Feishu/Lark custom bot
+type FeishuTarget struct {
+	WebhookURL string
+	Secret     string // 开启"签名校验"时的密钥
+}
+
+func (t *FeishuTarget) Name() string { return "feishu" }
+
+func (t *FeishuTarget) Send(client *http.Client, event Event) error {
+	content := fmt.Sprintf("**%s**", event.Reason)
+	if event.Text != "" {
+		content += "\n" + event.Text
+	}
+
+	payload := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"title":    map[string]string{"tag": "plain_text", "content": event.Title},
+				"template": "blue",
+			},
+			"elements": []interface{}{
+				map[string]interface{}{
+					"tag":  "div",
+					"text": map[string]string{"tag": "lark_md", "content": content},
+				},
+				map[string]interface{}{
+					"tag": "action",
+					"actions": []interface{}{
+						map[string]interface{}{
+							"tag":  "button",
+							"text": map[string]string{"tag": "plain_text", "content": "View"},
+							"url":  event.URL,
+							"type": "primary",
+						},
+					},
+				},
+			},
+		},
+	}
+	if t.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		payload["timestamp"] = timestamp
+		payload["sign"] = SignFeishu(t.Secret, timestamp)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return postJSON(client, t.WebhookURL, body, nil, checkCode("code", "msg"))
+}
+
+// SignFeishu computes the Feishu/Lark bot signature: HMAC-SHA256 keyed with
+// "<timestamp>\n<secret>" over an empty message, base64 encoded
+func SignFeishu(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// DingTalkTarget posts a markdown message to a DingTalk custom robot
+type DingTalkTarget struct {
+	WebhookURL string
+	Secret     string // 安全设置选择"加签"时的密钥
+}
+
+func (t *DingTalkTarget) Name() string { return "dingtalk" }
+
+func (t *DingTalkTarget) Send(client *http.Client, event Event) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": event.Title,
+			"text":  markdownMessage(event),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	target := t.WebhookURL
+	if t.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		target, err = appendQuery(target, map[string]string{
+			"timestamp": timestamp,
+			"sign":      SignDingTalk(t.Secret, timestamp),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return postJSON(client, target, body, nil, checkCode("errcode", "errmsg"))
+}
+
+// SignDingTalk computes the DingTalk robot signature: HMAC-SHA256 of
+// "<timestamp>\n<secret>" keyed with the secret, base64 encoded
+func SignDingTalk(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// WeComTarget posts a markdown message to a WeCom (企业微信) group robot.
+// WeCom robots authenticate with the key in the webhook URL and need no signature.
+type WeComTarget struct {
+	WebhookURL string
+}
+
+func (t *WeComTarget) Name() string { return "wecom" }
+
+func (t *WeComTarget) Send(client *http.Client, event Event) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"content": markdownMessage(event)},
+	})
+	if err != nil {
+		return err
+	}
+	return postJSON(client, t.WebhookURL, body, nil, checkCode("errcode", "errmsg"))
+}
+
+// markdownMessage formats an event for the markdown flavours of DingTalk and WeCom
+func markdownMessage(event Event) string {
+	text := fmt.Sprintf("### [%s](%s)\n\n%s", event.Title, event.URL, event.Reason)
+	if event.Text != "" {
+		text += "\n\n" + event.Text
+	}
+	return text
+}
+
+// checkCode returns a response checker for platforms that report errors
+// as a non-zero code in a 200 response
+func checkCode(codeField, msgField string) func([]byte) error {
+	return func(body []byte) error {
+		var result map[string]interface{}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil // 非JSON响应视为成功
+		}
+		code, ok := result[codeField].(float64)
+		if !ok || code == 0 {
+			return nil
+		}
+		return fmt.Errorf("%s=%v: %v", codeField, code, result[msgField])
+	}
+}
+
+func appendQuery(rawURL string, params map[string]string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	q := u.Query()
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// postJSON posts a JSON body and validates the response
+func postJSON(client *http.Client, target string, body []byte, headers map[string]string, check func([]byte) error) error {
+	req, err := http.NewRequest("POST", target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", "LLM-News-Agent")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return fmt.Errorf("failed to read webhook response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	if check != nil {
+		return check(respBody)
+	}
+	return nil
+}
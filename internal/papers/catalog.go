@@ -0,0 +1,79 @@
+package papers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// builtinSources are the scraped sources that can be fetched one at a time,
+// including the ones FetchTopPapers currently skips
+var builtinSources = []struct {
+	name    string
+	baseURL string
+	fetch   func(client *http.Client, baseURL string, now time.Time) ([]models.Paper, error)
+}{
+	{"paperswithcode", paperswithcodeURL, fetchPapersWithCode},
+	{"hackernews", hackerNewsURL, fetchHackerNewsAIArticles},
+	{"devto", devToURL, fetchDevToAIArticles},
+	{"jiqizhixin", jiqizhixinURL, fetchJiqizhixinArticles},
+	{"csdn", csdnURL, fetchCSDNArticles},
+	{"infoq", infoQURL, fetchInfoQArticles},
+}
+
+// SourceNames returns the names accepted by FetchSource: the scraped sources
+// followed by the configured blog feeds
+func SourceNames() []string {
+	var names []string
+	for _, s := range builtinSources {
+		names = append(names, s.name)
+	}
+	for _, feed := range FeedSourcesFromEnv() {
+		names = append(names, feed.Name)
+	}
+	return names
+}
+
+// FetchSource fetches the articles of a single source by name, ignoring case,
+// so one source can be debugged without the others. The articles are scored
+// like the ones of FetchTopPapers but not summarized or translated.
+func FetchSource(name string) ([]models.Paper, error) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	now := time.Now()
+
+	var results []models.Paper
+	var err error
+	found := false
+	for _, s := range builtinSources {
+		if strings.EqualFold(s.name, name) {
+			results, err = s.fetch(client, s.baseURL, now)
+			found = true
+			break
+		}
+	}
+	if !found {
+		for _, feed := range FeedSourcesFromEnv() {
+			if strings.EqualFold(feed.Name, name) {
+				results, err = fetchFeedSource(client, feed)
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("unknown source %q, expected one of %s", name, strings.Join(SourceNames(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	enrichPapersWithScores(results)
+	models.AssignPaperIDs(results)
+	sortPapersByRelevance(results)
+	return results, nil
+}
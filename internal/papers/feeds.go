@@ -0,0 +1,516 @@
+package papers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// FeedSource describes a blog or news site that publishes an RSS 2.0,
+// Atom 1.0 or JSON Feed document
+type FeedSource struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Limit int    `json:"limit"` // 每个源最多保留的文章数，0表示使用默认值
+}
+
+// DefaultFeedSources lists the blogs fetched when LLM_NEWS_FEED_SOURCES is not set
+var DefaultFeedSources = []FeedSource{
+	{Name: "OpenAI", URL: "https://openai.com/news/rss.xml"},
+	{Name: "Anthropic", URL: "https://www.anthropic.com/rss.xml"},
+	{Name: "Google AI", URL: "https://blog.google/technology/ai/rss/"},
+	{Name: "Hugging Face", URL: "https://huggingface.co/blog/feed.xml"},
+	{Name: "机器之心", URL: "https://www.jiqizhixin.com/rss"},
+}
+
+const defaultFeedLimit = 5
+
+// FeedSourcesFromEnv returns the feed sources configured through the
+// LLM_NEWS_FEED_SOURCES environment variable, formatted as comma separated
+// "name=url" pairs. DefaultFeedSources is returned when the variable is empty.
+func FeedSourcesFromEnv() []FeedSource {
+	raw := strings.TrimSpace(os.Getenv("LLM_NEWS_FEED_SOURCES"))
+	if raw == "" {
+		return DefaultFeedSources
+	}
+
+	var sources []FeedSource
+	for _, pair := range strings.Split(raw, ",") {
+		name, feedURL, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || strings.TrimSpace(feedURL) == "" {
+			log.Printf("Warning: Ignoring malformed feed source %q", pair)
+			continue
+		}
+		sources = append(sources, FeedSource{
+			Name: strings.TrimSpace(name),
+			URL:  strings.TrimSpace(feedURL),
+		})
+	}
+	return sources
+}
+
+// feedEntry is a format-neutral entry parsed from any supported feed format
+type feedEntry struct {
+	Title     string
+	Link      string
+	Summary   string
+	Authors   []string
+	Tags      []string
+	Published time.Time
+}
+
+// fetchFeedArticles fetches every configured feed and returns the AI related entries
+func fetchFeedArticles(client *http.Client, sources []FeedSource) ([]models.Paper, error) {
+	var results []models.Paper
+	var errors []string
+
+	for _, source := range sources {
+		posts, err := fetchFeedSource(client, source)
+		if err != nil {
+			log.Printf("Warning: Error fetching feed %s: %v", source.Name, err)
+			errors = append(errors, fmt.Sprintf("%s: %v", source.Name, err))
+			continue
+		}
+		results = append(results, posts...)
+	}
+
+	if len(results) == 0 && len(errors) > 0 {
+		return nil, fmt.Errorf("failed to fetch any feed: %s", strings.Join(errors, "; "))
+	}
+
+	return results, nil
+}
+
+// fetchFeedSource downloads and parses one feed
+func fetchFeedSource(client *http.Client, source FeedSource) ([]models.Paper, error) {
+	req, err := http.NewRequest("GET", source.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "LLM-News-Agent")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, */*;q=0.8")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %w", err)
+	}
+
+	entries, err := parseFeed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return feedEntriesToPapers(source, entries), nil
+}
+
+// feedEntriesToPapers keeps the AI related entries and converts them to papers
+func feedEntriesToPapers(source FeedSource, entries []feedEntry) []models.Paper {
+	limit := source.Limit
+	if limit <= 0 {
+		limit = defaultFeedLimit
+	}
+
+	// 先收集所有候选条目，一次性判断AI相关性
+	var candidates []feedEntry
+	var texts []string
+	for _, entry := range entries {
+		if entry.Title == "" || entry.Link == "" {
+			continue
+		}
+		candidates = append(candidates, entry)
+		texts = append(texts, entry.Title+" "+entry.Summary+" "+strings.Join(entry.Tags, " "))
+	}
+	related := aiRelated(texts)
+
+	var results []models.Paper
+	for i, entry := range candidates {
+		if len(results) >= limit {
+			break
+		}
+		if !related[i] {
+			continue
+		}
+		text := texts[i]
+
+		authors := entry.Authors
+		if len(authors) == 0 {
+			authors = []string{source.Name}
+		}
+
+		// 条目没有日期时使用当前时间，并标记为估计值
+		publishedDate := entry.Published
+		if publishedDate.IsZero() {
+			publishedDate = time.Now()
+		}
+
+		results = append(results, models.Paper{
+			Title:         entry.Title,
+			URL:           entry.Link,
+			Authors:       authors,
+			PublishedDate: publishedDate,
+			DateEstimated: entry.Published.IsZero(),
+			Source:        source.Name,
+			Summary:       entry.Summary,
+			Keywords:      extractKeywords(text),
+			NoveltyScore:  calculateNoveltyScore(entry.Title, entry.Summary),
+		})
+	}
+
+	return results
+}
+
+// parseFeed detects the feed format and parses the entries
+func parseFeed(data []byte) ([]feedEntry, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("empty feed document")
+	}
+
+	if trimmed[0] == '{' {
+		return parseJSONFeed(trimmed)
+	}
+
+	// 读取根元素名称来区分RSS和Atom
+	decoder := xml.NewDecoder(bytes.NewReader(trimmed))
+	decoder.CharsetReader = passthroughCharsetReader
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect feed format: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(start.Name.Local) {
+		case "rss", "rdf":
+			return parseRSSFeed(trimmed)
+		case "feed":
+			return parseAtomFeed(trimmed)
+		default:
+			return nil, fmt.Errorf("unsupported feed root element <%s>", start.Name.Local)
+		}
+	}
+}
+
+// RSS 2.0 (以及RSS 1.0/RDF) 的结构
+type rssDocument struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"` // RSS 1.0 把item放在根元素下
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Author      string   `xml:"author"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	GUID        string   `xml:"guid"`
+}
+
+func parseRSSFeed(data []byte) ([]feedEntry, error) {
+	var doc rssDocument
+	if err := decodeXML(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+	}
+
+	items := append(doc.Channel.Items, doc.Items...)
+	entries := make([]feedEntry, 0, len(items))
+	for _, item := range items {
+		summary := item.Description
+		if strings.TrimSpace(summary) == "" {
+			summary = item.Content
+		}
+
+		link := strings.TrimSpace(item.Link)
+		if link == "" && strings.HasPrefix(item.GUID, "http") {
+			link = strings.TrimSpace(item.GUID)
+		}
+
+		var authors []string
+		for _, author := range []string{item.Creator, item.Author} {
+			if author = strings.TrimSpace(author); author != "" {
+				authors = append(authors, author)
+				break
+			}
+		}
+
+		published := parseFeedDate(item.PubDate)
+		if published.IsZero() {
+			published = parseFeedDate(item.Date)
+		}
+
+		entries = append(entries, feedEntry{
+			Title:     cleanFeedText(item.Title),
+			Link:      link,
+			Summary:   summarizeFeedText(summary),
+			Authors:   authors,
+			Tags:      trimAll(item.Categories),
+			Published: published,
+		})
+	}
+
+	return entries, nil
+}
+
+// Atom 1.0 的结构
+type atomDocument struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Authors   []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+}
+
+func parseAtomFeed(data []byte) ([]feedEntry, error) {
+	var doc atomDocument
+	if err := decodeXML(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+	}
+
+	entries := make([]feedEntry, 0, len(doc.Entries))
+	for _, entry := range doc.Entries {
+		// 优先使用rel="alternate"（或未指定rel）的链接
+		link := ""
+		for _, l := range entry.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = strings.TrimSpace(l.Href)
+				break
+			}
+		}
+		if link == "" && len(entry.Links) > 0 {
+			link = strings.TrimSpace(entry.Links[0].Href)
+		}
+
+		summary := entry.Summary
+		if strings.TrimSpace(summary) == "" {
+			summary = entry.Content
+		}
+
+		var authors []string
+		for _, author := range entry.Authors {
+			if name := strings.TrimSpace(author.Name); name != "" {
+				authors = append(authors, name)
+			}
+		}
+
+		var tags []string
+		for _, category := range entry.Categories {
+			if category.Label != "" {
+				tags = append(tags, strings.TrimSpace(category.Label))
+			} else if category.Term != "" {
+				tags = append(tags, strings.TrimSpace(category.Term))
+			}
+		}
+
+		published := parseFeedDate(entry.Published)
+		if published.IsZero() {
+			published = parseFeedDate(entry.Updated)
+		}
+
+		entries = append(entries, feedEntry{
+			Title:     cleanFeedText(entry.Title),
+			Link:      link,
+			Summary:   summarizeFeedText(summary),
+			Authors:   authors,
+			Tags:      tags,
+			Published: published,
+		})
+	}
+
+	return entries, nil
+}
+
+// JSON Feed 1.x 的结构 (https://jsonfeed.org/version/1.1)
+type jsonFeedDocument struct {
+	Version string `json:"version"`
+	Items   []struct {
+		ID            string   `json:"id"`
+		URL           string   `json:"url"`
+		ExternalURL   string   `json:"external_url"`
+		Title         string   `json:"title"`
+		Summary       string   `json:"summary"`
+		ContentText   string   `json:"content_text"`
+		ContentHTML   string   `json:"content_html"`
+		DatePublished string   `json:"date_published"`
+		DateModified  string   `json:"date_modified"`
+		Tags          []string `json:"tags"`
+		Author        *struct {
+			Name string `json:"name"`
+		} `json:"author"`
+		Authors []struct {
+			Name string `json:"name"`
+		} `json:"authors"`
+	} `json:"items"`
+}
+
+func parseJSONFeed(data []byte) ([]feedEntry, error) {
+	var doc jsonFeedDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON feed: %w", err)
+	}
+	if !strings.HasPrefix(doc.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("unsupported JSON feed version %q", doc.Version)
+	}
+
+	entries := make([]feedEntry, 0, len(doc.Items))
+	for _, item := range doc.Items {
+		link := item.URL
+		if link == "" {
+			link = item.ExternalURL
+		}
+
+		summary := item.Summary
+		if summary == "" {
+			summary = item.ContentText
+		}
+		if summary == "" {
+			summary = item.ContentHTML
+		}
+
+		var authors []string
+		for _, author := range item.Authors {
+			if name := strings.TrimSpace(author.Name); name != "" {
+				authors = append(authors, name)
+			}
+		}
+		// version 1.0 只有单个author字段
+		if len(authors) == 0 && item.Author != nil && strings.TrimSpace(item.Author.Name) != "" {
+			authors = append(authors, strings.TrimSpace(item.Author.Name))
+		}
+
+		published := parseFeedDate(item.DatePublished)
+		if published.IsZero() {
+			published = parseFeedDate(item.DateModified)
+		}
+
+		entries = append(entries, feedEntry{
+			Title:     cleanFeedText(item.Title),
+			Link:      strings.TrimSpace(link),
+			Summary:   summarizeFeedText(summary),
+			Authors:   authors,
+			Tags:      trimAll(item.Tags),
+			Published: published,
+		})
+	}
+
+	return entries, nil
+}
+
+// decodeXML decodes a feed document leniently, accepting HTML entities and
+// non UTF-8 charset declarations
+func decodeXML(data []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = passthroughCharsetReader
+	return decoder.Decode(v)
+}
+
+// passthroughCharsetReader treats every declared charset as UTF-8. Feeds that
+// declare GB2312/GBK but actually serve UTF-8 are common among Chinese blogs.
+func passthroughCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	return input, nil
+}
+
+// parseFeedDate parses the date layouts commonly found in feeds
+func parseFeedDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+
+	layouts := []string{
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC3339,
+		time.RFC3339Nano,
+		"Mon, 2 Jan 2006 15:04:05 -0700",
+		"Mon, 2 Jan 2006 15:04:05 MST",
+		"2 Jan 2006 15:04:05 -0700",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+var (
+	htmlTagRegex    = regexp.MustCompile(`(?s)<[^>]*>`)
+	whitespaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// cleanFeedText strips HTML markup and collapses whitespace
+func cleanFeedText(s string) string {
+	s = htmlTagRegex.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	return strings.TrimSpace(whitespaceRegex.ReplaceAllString(s, " "))
+}
+
+// summarizeFeedText cleans a feed summary and limits it to 300 characters
+func summarizeFeedText(s string) string {
+	s = cleanFeedText(s)
+	runes := []rune(s)
+	if len(runes) > 300 {
+		return string(runes[:300]) + "..."
+	}
+	return s
+}
+
+func trimAll(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
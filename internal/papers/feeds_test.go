@@ -0,0 +1,223 @@
+package papers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "feeds", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		fixture   string
+		wantCount int
+		first     feedEntry
+	}{
+		{
+			fixture:   "rss2.xml",
+			wantCount: 3,
+			first: feedEntry{
+				Title:     "Fine-tuning Llama 3 with QLoRA",
+				Link:      "https://huggingface.co/blog/llama3-qlora",
+				Summary:   "A step-by-step guide to fine-tuning an LLM on a single GPU.",
+				Authors:   []string{"Jane Doe"},
+				Tags:      []string{"llm", "fine-tuning"},
+				Published: time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			fixture:   "atom.xml",
+			wantCount: 2,
+			first: feedEntry{
+				Title:     "Gemini 1.5: our next-generation model",
+				Link:      "https://blog.google/technology/ai/gemini-1-5/",
+				Summary:   "A breakthrough in long-context understanding.",
+				Authors:   []string{"Sundar Pichai", "Demis Hassabis"},
+				Tags:      []string{"Gemini"},
+				Published: time.Date(2024, 4, 3, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			fixture:   "jsonfeed.json",
+			wantCount: 2,
+			first: feedEntry{
+				Title:     "开源大模型 Qwen 新版本发布，支持 RAG 与 Agent",
+				Link:      "https://www.jiqizhixin.com/articles/2024-04-05-1",
+				Summary:   "通义千问团队发布了新的 LLM 。",
+				Authors:   []string{"机器之心编辑部"},
+				Tags:      []string{"大模型", "Qwen"},
+				Published: time.Date(2024, 4, 5, 1, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			entries, err := parseFeed(readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("parseFeed returned error: %v", err)
+			}
+			if len(entries) != tt.wantCount {
+				t.Fatalf("got %d entries, want %d", len(entries), tt.wantCount)
+			}
+
+			got := entries[0]
+			if got.Title != tt.first.Title {
+				t.Errorf("Title = %q, want %q", got.Title, tt.first.Title)
+			}
+			if got.Link != tt.first.Link {
+				t.Errorf("Link = %q, want %q", got.Link, tt.first.Link)
+			}
+			if got.Summary != tt.first.Summary {
+				t.Errorf("Summary = %q, want %q", got.Summary, tt.first.Summary)
+			}
+			if strings.Join(got.Authors, "|") != strings.Join(tt.first.Authors, "|") {
+				t.Errorf("Authors = %v, want %v", got.Authors, tt.first.Authors)
+			}
+			if strings.Join(got.Tags, "|") != strings.Join(tt.first.Tags, "|") {
+				t.Errorf("Tags = %v, want %v", got.Tags, tt.first.Tags)
+			}
+			if !got.Published.Equal(tt.first.Published) {
+				t.Errorf("Published = %v, want %v", got.Published, tt.first.Published)
+			}
+		})
+	}
+}
+
+func TestParseFeedFallbacks(t *testing.T) {
+	entries, err := parseFeed(readFixture(t, "rss2.xml"))
+	if err != nil {
+		t.Fatalf("parseFeed returned error: %v", err)
+	}
+	// 没有link时使用guid，没有description时使用content:encoded
+	rag := entries[2]
+	if rag.Link != "https://huggingface.co/blog/rag-at-scale" {
+		t.Errorf("Link = %q, want guid fallback", rag.Link)
+	}
+	if rag.Summary != "Serving RAG pipelines with TGI." {
+		t.Errorf("Summary = %q, want content:encoded fallback", rag.Summary)
+	}
+
+	entries, err = parseFeed(readFixture(t, "jsonfeed.json"))
+	if err != nil {
+		t.Fatalf("parseFeed returned error: %v", err)
+	}
+	claude := entries[1]
+	if claude.Link != "https://www.jiqizhixin.com/articles/2024-04-04-2" {
+		t.Errorf("Link = %q, want external_url fallback", claude.Link)
+	}
+	if len(claude.Authors) != 1 || claude.Authors[0] != "Synced" {
+		t.Errorf("Authors = %v, want JSON Feed 1.0 author fallback", claude.Authors)
+	}
+	if claude.Published.IsZero() {
+		t.Error("Published is zero, want date_modified fallback")
+	}
+}
+
+func TestParseFeedErrors(t *testing.T) {
+	inputs := map[string]string{
+		"empty":        "   ",
+		"html":         "<html><body>Not a feed</body></html>",
+		"json version": `{"version": "1", "items": []}`,
+		"broken json":  `{"version": `,
+	}
+	for name, input := range inputs {
+		if _, err := parseFeed([]byte(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFetchFeedSource(t *testing.T) {
+	fixtures := map[string]string{
+		"/rss":  "rss2.xml",
+		"/atom": "atom.xml",
+		"/json": "jsonfeed.json",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(readFixture(t, name))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path       string
+		wantTitles []string
+	}{
+		// 非AI相关的条目应被IsAIRelated过滤掉
+		{"/rss", []string{"Fine-tuning Llama 3 with QLoRA", "Retrieval augmented generation at scale"}},
+		{"/atom", []string{"Gemini 1.5: our next-generation model"}},
+		{"/json", []string{"开源大模型 Qwen 新版本发布，支持 RAG 与 Agent", "Anthropic 发布 Claude 3 系列模型"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			source := FeedSource{Name: "Fixture", URL: server.URL + tt.path}
+			got, err := fetchFeedSource(server.Client(), source)
+			if err != nil {
+				t.Fatalf("fetchFeedSource returned error: %v", err)
+			}
+			if len(got) != len(tt.wantTitles) {
+				t.Fatalf("got %d papers, want %d", len(got), len(tt.wantTitles))
+			}
+			for i, paper := range got {
+				if paper.Title != tt.wantTitles[i] {
+					t.Errorf("paper %d title = %q, want %q", i, paper.Title, tt.wantTitles[i])
+				}
+				if paper.Source != "Fixture" {
+					t.Errorf("paper %d source = %q, want Fixture", i, paper.Source)
+				}
+				if len(paper.Keywords) == 0 {
+					t.Errorf("paper %d has no keywords", i)
+				}
+			}
+		})
+	}
+
+	if _, err := fetchFeedSource(server.Client(), FeedSource{Name: "Missing", URL: server.URL + "/missing"}); err == nil {
+		t.Error("expected an error for a 404 feed")
+	}
+}
+
+func TestFeedSourceLimit(t *testing.T) {
+	entries, err := parseFeed(readFixture(t, "rss2.xml"))
+	if err != nil {
+		t.Fatalf("parseFeed returned error: %v", err)
+	}
+	got := feedEntriesToPapers(FeedSource{Name: "Fixture", Limit: 1}, entries)
+	if len(got) != 1 {
+		t.Fatalf("got %d papers, want 1", len(got))
+	}
+}
+
+func TestFeedSourcesFromEnv(t *testing.T) {
+	t.Setenv("LLM_NEWS_FEED_SOURCES", "")
+	if got := FeedSourcesFromEnv(); len(got) != len(DefaultFeedSources) {
+		t.Errorf("got %d sources, want defaults", len(got))
+	}
+
+	t.Setenv("LLM_NEWS_FEED_SOURCES", "OpenAI=https://openai.com/news/rss.xml, broken ,HF = https://huggingface.co/blog/feed.xml")
+	got := FeedSourcesFromEnv()
+	if len(got) != 2 {
+		t.Fatalf("got %d sources, want 2", len(got))
+	}
+	if got[1].Name != "HF" || got[1].URL != "https://huggingface.co/blog/feed.xml" {
+		t.Errorf("unexpected source %+v", got[1])
+	}
+}
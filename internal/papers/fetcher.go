@@ -12,12 +12,20 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gerryyang2025/llm-news/internal/i18n"
 	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/summarizer"
 )
 
-// Constants for the APIs
+// Constants for the APIs, replaced by httptest servers in tests
 const (
-	paperswithcodeURL = "https://paperswithcode.com/api/v1/papers/?topics=language-modelling,transformer,nlp,llm,gpt,diffusion-models&page=1"
+	paperswithcodeURL  = "https://paperswithcode.com"
+	paperswithcodePath = "/api/v1/papers/?topics=language-modelling,transformer,nlp,llm,gpt,diffusion-models&page=1"
+	hackerNewsURL      = "https://hacker-news.firebaseio.com"
+	devToURL           = "https://dev.to"
+	jiqizhixinURL      = "https://www.jiqizhixin.com"
+	csdnURL            = "https://blog.csdn.net"
+	infoQURL           = "https://www.infoq.cn"
 )
 
 // FetchTopPapers fetches top AI/ML papers from multiple sources
@@ -26,7 +34,10 @@ func FetchTopPapers() ([]models.Paper, error) {
 	var errors []string
 
 	// Fetch from Papers with Code
-	pwcPapers, err := fetchPapersWithCode()
+	client := &http.Client{
+		Timeout: 30 * time.Second, // 增加超时时间到30秒
+	}
+	pwcPapers, err := fetchPapersWithCode(client, paperswithcodeURL, time.Now())
 	if err != nil {
 		log.Printf("Warning: Error fetching from Papers with Code: %v", err)
 		errors = append(errors, fmt.Sprintf("Papers with Code: %v", err))
@@ -53,6 +64,17 @@ func FetchTopPapers() ([]models.Paper, error) {
 
 	// Calculate citation velocity and novelty scores
 	enrichPapersWithScores(allPapers)
+	models.AssignPaperIDs(allPapers)
+
+	// 配置了LLM时生成TL;DR、贡献点和技术标签
+	if s := summarizer.Default(); s != nil {
+		s.SummarizePapers(allPapers)
+	}
+
+	// 配置了翻译时生成中英文对照的标题和摘要
+	if l := i18n.DefaultLocalizer(); l != nil {
+		l.TranslatePapers(allPapers)
+	}
 
 	// Sort papers by relevance
 	sortPapersByRelevance(allPapers)
@@ -60,14 +82,9 @@ func FetchTopPapers() ([]models.Paper, error) {
 	return allPapers, nil
 }
 
-// fetchPapersWithCode fetches papers from Papers with Code
-func fetchPapersWithCode() ([]models.Paper, error) {
-	// Make HTTP request
-	client := &http.Client{
-		Timeout: 30 * time.Second, // 增加超时时间到30秒
-	}
-
-	resp, err := client.Get(paperswithcodeURL)
+// fetchPapersWithCode fetches papers from the Papers with Code API at baseURL
+func fetchPapersWithCode(client *http.Client, baseURL string, now time.Time) ([]models.Paper, error) {
+	resp, err := client.Get(baseURL + paperswithcodePath)
 	if err != nil {
 		// 出错时不再返回示例数据
 		return nil, fmt.Errorf("failed to fetch papers from Papers with Code: %w", err)
@@ -135,9 +152,10 @@ func fetchPapersWithCode() ([]models.Paper, error) {
 			}
 		}
 
-		// 如果无法解析日期，则使用当前时间
-		if publishedDate.IsZero() {
-			publishedDate = time.Now().AddDate(0, 0, -rand.Intn(30)) // 随机设定为过去30天内
+		// 如果无法解析日期，则使用估计的日期并标记，健康检查将其视为未填充
+		dateEstimated := publishedDate.IsZero()
+		if dateEstimated {
+			publishedDate = now.AddDate(0, 0, -rand.Intn(30)) // 随机设定为过去30天内
 			log.Printf("Warning: Could not parse date for paper %s, using estimated date", result.Title)
 		}
 
@@ -192,6 +210,7 @@ func fetchPapersWithCode() ([]models.Paper, error) {
 			URL:           result.URL,
 			Authors:       authors,
 			PublishedDate: publishedDate,
+			DateEstimated: dateEstimated,
 			Source:        "Papers with Code",
 			Summary:       result.Abstract,
 			Keywords:      keywords,
@@ -367,9 +386,10 @@ func enhancePaperWithDetails(paper *models.Paper) {
 		}
 	}
 
-	// Generate example code snippet if relevant
-	if containsAny(paper.Title, "code", "implementation", "github") ||
-		containsAny(paper.Summary, "code", "implementation", "github") {
+	// Generate example code snippet if relevant. The snippet is a generic
+	// template, so it is skipped when LLM summaries are enabled.
+	if summarizer.Default() == nil && (containsAny(paper.Title, "code", "implementation", "github") ||
+		containsAny(paper.Summary, "code", "implementation", "github")) {
 		paper.CodeSnippet = generateCodeSnippet(paper)
 	}
 }
@@ -464,35 +484,33 @@ func enrichPapersWithScores(papers []models.Paper) {
 	}
 }
 
+// ScoreBreakdown returns the weighted components of the ranking score of a
+// paper: citation velocity (30%), novelty (30%), citation count (25%) and
+// freshness (15%). The ranking score is their sum.
+func ScoreBreakdown(p models.Paper, now time.Time) map[string]float64 {
+	// 计算日期新鲜度分数（越近越高，最高5分）
+	daysOld := now.Sub(p.PublishedDate).Hours() / 24
+	freshness := 5.0 - math.Min(daysOld/60, 5.0) // 60天内线性递减，最低0分
+
+	return map[string]float64{
+		"citation_velocity": p.CitationVelocity * 0.3,
+		"novelty":           p.NoveltyScore * 0.3,
+		"citations":         float64(p.CitationCount) / 100.0 * 0.25,
+		"freshness":         freshness * 0.15,
+	}
+}
+
+// RankingScore returns the sum of the score components of a paper
+func RankingScore(p models.Paper, now time.Time) float64 {
+	b := ScoreBreakdown(p, now)
+	return b["citation_velocity"] + b["novelty"] + b["citations"] + b["freshness"]
+}
+
 // sortPapersByRelevance sorts papers by a combination of factors for maximum relevance
 func sortPapersByRelevance(papers []models.Paper) {
-	// 使用sort包进行高效排序
+	now := time.Now()
+	// 使用sort包进行高效排序，降序排列（高分在前）
 	sort.Slice(papers, func(i, j int) bool {
-		// 计算综合评分（考虑多个因素的加权平均）
-		// 1. 引用速度 (30%)
-		// 2. 新颖性分数 (30%)
-		// 3. 引用总数 (25%)
-		// 4. 发布日期新鲜度 (15%)
-
-		// 计算日期新鲜度分数（越近越高，最高5分）
-		daysOldI := time.Since(papers[i].PublishedDate).Hours() / 24
-		daysOldJ := time.Since(papers[j].PublishedDate).Hours() / 24
-
-		freshnessI := 5.0 - math.Min(daysOldI/60, 5.0) // 60天内线性递减，最低0分
-		freshnessJ := 5.0 - math.Min(daysOldJ/60, 5.0)
-
-		// 综合评分计算
-		scoreI := (papers[i].CitationVelocity * 0.3) +
-			(papers[i].NoveltyScore * 0.3) +
-			(float64(papers[i].CitationCount) / 100.0 * 0.25) +
-			(freshnessI * 0.15)
-
-		scoreJ := (papers[j].CitationVelocity * 0.3) +
-			(papers[j].NoveltyScore * 0.3) +
-			(float64(papers[j].CitationCount) / 100.0 * 0.25) +
-			(freshnessJ * 0.15)
-
-		// 降序排列（高分在前）
-		return scoreI > scoreJ
+		return RankingScore(papers[i], now) > RankingScore(papers[j], now)
 	})
 }
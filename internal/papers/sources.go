@@ -10,16 +10,23 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gerryyang2025/llm-news/internal/classifier"
 	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/textmatch"
 )
 
 // FetchOtherBlogPosts 抓取技术博客文章
 func FetchOtherBlogPosts() ([]models.Paper, error) {
+	client := &http.Client{
+		Timeout: 20 * time.Second,
+	}
+	now := time.Now()
+
 	var results []models.Paper
 	var errors []string
 
 	// 获取HackerNews热门AI文章
-	hackerNewsPosts, err := fetchHackerNewsAIArticles()
+	hackerNewsPosts, err := fetchHackerNewsAIArticles(client, hackerNewsURL, now)
 	if err != nil {
 		log.Printf("Warning: Error fetching from HackerNews: %v", err)
 		errors = append(errors, fmt.Sprintf("HackerNews: %v", err))
@@ -28,7 +35,7 @@ func FetchOtherBlogPosts() ([]models.Paper, error) {
 	}
 
 	// 获取Dev.to热门AI文章
-	devToPosts, err := fetchDevToAIArticles()
+	devToPosts, err := fetchDevToAIArticles(client, devToURL, now)
 	if err != nil {
 		log.Printf("Warning: Error fetching from Dev.to: %v", err)
 		errors = append(errors, fmt.Sprintf("Dev.to: %v", err))
@@ -36,10 +43,10 @@ func FetchOtherBlogPosts() ([]models.Paper, error) {
 		results = append(results, devToPosts...)
 	}
 
-	// 注释掉机器之心数据源，因为404错误
+	// 注释掉机器之心数据源，因为404错误，现改为通过RSS订阅获取（见DefaultFeedSources）
 	/*
 		// 获取机器之心热门AI文章
-		jiqizhixinPosts, err := fetchJiqizhixinArticles()
+		jiqizhixinPosts, err := fetchJiqizhixinArticles(client, jiqizhixinURL, now)
 		if err != nil {
 			log.Printf("Warning: Error fetching from 机器之心: %v", err)
 			errors = append(errors, fmt.Sprintf("机器之心: %v", err))
@@ -49,7 +56,7 @@ func FetchOtherBlogPosts() ([]models.Paper, error) {
 	*/
 
 	// 获取CSDN热门AI文章
-	csdnPosts, err := fetchCSDNArticles()
+	csdnPosts, err := fetchCSDNArticles(client, csdnURL, now)
 	if err != nil {
 		log.Printf("Warning: Error fetching from CSDN: %v", err)
 		errors = append(errors, fmt.Sprintf("CSDN: %v", err))
@@ -57,10 +64,19 @@ func FetchOtherBlogPosts() ([]models.Paper, error) {
 		results = append(results, csdnPosts...)
 	}
 
+	// 获取RSS/Atom/JSON Feed博客文章
+	feedPosts, err := fetchFeedArticles(client, FeedSourcesFromEnv())
+	if err != nil {
+		log.Printf("Warning: Error fetching blog feeds: %v", err)
+		errors = append(errors, fmt.Sprintf("Feeds: %v", err))
+	} else {
+		results = append(results, feedPosts...)
+	}
+
 	// 注释掉InfoQ中文站，因为451错误
 	/*
 		// 获取InfoQ中文站热门AI文章
-		infoqPosts, err := fetchInfoQArticles()
+		infoqPosts, err := fetchInfoQArticles(client, infoQURL, now)
 		if err != nil {
 			log.Printf("Warning: Error fetching from InfoQ: %v", err)
 			errors = append(errors, fmt.Sprintf("InfoQ: %v", err))
@@ -78,14 +94,9 @@ func FetchOtherBlogPosts() ([]models.Paper, error) {
 }
 
 // 获取HackerNews上热门的AI相关文章
-func fetchHackerNewsAIArticles() ([]models.Paper, error) {
-	// 获取HackerNews最新故事
-	client := &http.Client{
-		Timeout: 20 * time.Second,
-	}
-
+func fetchHackerNewsAIArticles(client *http.Client, baseURL string, now time.Time) ([]models.Paper, error) {
 	// 获取最新的top stories
-	resp, err := client.Get("https://hacker-news.firebaseio.com/v0/topstories.json")
+	resp, err := client.Get(baseURL + "/v0/topstories.json")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch HackerNews top stories: %v", err)
 	}
@@ -119,7 +130,7 @@ func fetchHackerNewsAIArticles() ([]models.Paper, error) {
 
 	// 获取每个故事的详情，找出AI相关的
 	for _, id := range storyIDs {
-		storyURL := fmt.Sprintf("https://hacker-news.firebaseio.com/v0/item/%d.json", id)
+		storyURL := fmt.Sprintf("%s/v0/item/%d.json", baseURL, id)
 		storyResp, err := client.Get(storyURL)
 		if err != nil {
 			log.Printf("Warning: Failed to fetch HackerNews story %d: %v", id, err)
@@ -162,7 +173,7 @@ func fetchHackerNewsAIArticles() ([]models.Paper, error) {
 				Summary:          story.Text,
 				Keywords:         extractKeywords(story.Title + " " + story.Text),
 				CitationCount:    story.Score, // 使用得分作为引用计数
-				CitationVelocity: float64(story.Score) / float64(maxInt(1, int(time.Since(time.Unix(story.Time, 0)).Hours()/24))),
+				CitationVelocity: float64(story.Score) / float64(maxInt(1, int(now.Sub(time.Unix(story.Time, 0)).Hours()/24))),
 				NoveltyScore:     calculateNoveltyScore(story.Title, story.Text),
 			}
 			results = append(results, paper)
@@ -178,13 +189,9 @@ func fetchHackerNewsAIArticles() ([]models.Paper, error) {
 }
 
 // 从Dev.to获取热门AI文章
-func fetchDevToAIArticles() ([]models.Paper, error) {
-	client := &http.Client{
-		Timeout: 20 * time.Second,
-	}
-
+func fetchDevToAIArticles(client *http.Client, baseURL string, now time.Time) ([]models.Paper, error) {
 	// 获取Dev.to上带有AI标签的热门文章
-	resp, err := client.Get("https://dev.to/api/articles?tag=ai&top=5")
+	resp, err := client.Get(baseURL + "/api/articles?tag=ai&top=5")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch Dev.to articles: %v", err)
 	}
@@ -241,7 +248,7 @@ func fetchDevToAIArticles() ([]models.Paper, error) {
 			Summary:          description,
 			Keywords:         tags,
 			CitationCount:    int(reactionsCount),
-			CitationVelocity: float64(int(reactionsCount)) / float64(maxInt(1, int(time.Since(publishedDate).Hours()/24))),
+			CitationVelocity: float64(int(reactionsCount)) / float64(maxInt(1, int(now.Sub(publishedDate).Hours()/24))),
 			NoveltyScore:     3.5 + float64(minInt(int(readingTime), 30))/10.0, // 基于阅读时间的新颖性评分
 		}
 
@@ -252,14 +259,10 @@ func fetchDevToAIArticles() ([]models.Paper, error) {
 }
 
 // 从机器之心获取热门AI文章
-func fetchJiqizhixinArticles() ([]models.Paper, error) {
-	client := &http.Client{
-		Timeout: 20 * time.Second,
-	}
-
+func fetchJiqizhixinArticles(client *http.Client, baseURL string, now time.Time) ([]models.Paper, error) {
 	// 机器之心没有公开API，我们需要抓取网页内容
 	// 这里使用RSS feed替代，或者直接解析HTML页面
-	resp, err := client.Get("https://www.jiqizhixin.com/categories/technical")
+	resp, err := client.Get(baseURL + "/categories/technical")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch 机器之心 articles: %v", err)
 	}
@@ -297,18 +300,21 @@ func fetchJiqizhixinArticles() ([]models.Paper, error) {
 		links = links[:maxArticles]
 	}
 
+	// 一次性判断所有标题是否与AI相关
+	related := aiRelated(trimmedTitles(titles))
+
 	for i := 0; i < len(titles) && i < len(links) && len(results) < maxArticles; i++ {
 		if len(titles[i]) > 1 && len(links[i]) > 1 {
 			title := strings.TrimSpace(titles[i][1])
-			link := "https://www.jiqizhixin.com" + links[i][1]
+			link := jiqizhixinURL + links[i][1]
 
 			// 只获取AI相关文章
-			if isAIRelated(title) {
-				publishedDate := time.Now() // 如果无法解析日期，使用当前时间
+			if related[i] {
+				publishedDate, dateEstimated := now, true // 如果无法解析日期，使用当前时间并标记为估计值
 				if i < len(dates) && len(dates[i]) > 1 {
 					// 尝试解析日期，格式可能是"2023-01-01"或类似格式
 					if parsedDate, err := time.Parse("2006-01-02", strings.TrimSpace(dates[i][1])); err == nil {
-						publishedDate = parsedDate
+						publishedDate, dateEstimated = parsedDate, false
 					}
 				}
 
@@ -317,6 +323,7 @@ func fetchJiqizhixinArticles() ([]models.Paper, error) {
 					URL:              link,
 					Authors:          []string{"机器之心"},
 					PublishedDate:    publishedDate,
+					DateEstimated:    dateEstimated,
 					Source:           "机器之心",
 					Summary:          fmt.Sprintf("来自机器之心的AI技术文章：%s", title),
 					Keywords:         extractKeywords(title),
@@ -333,13 +340,9 @@ func fetchJiqizhixinArticles() ([]models.Paper, error) {
 }
 
 // 从CSDN获取热门AI文章
-func fetchCSDNArticles() ([]models.Paper, error) {
-	client := &http.Client{
-		Timeout: 20 * time.Second,
-	}
-
+func fetchCSDNArticles(client *http.Client, baseURL string, now time.Time) ([]models.Paper, error) {
 	// CSDN AI专区
-	resp, err := client.Get("https://blog.csdn.net/nav/ai")
+	resp, err := client.Get(baseURL + "/nav/ai")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch CSDN articles: %v", err)
 	}
@@ -375,18 +378,22 @@ func fetchCSDNArticles() ([]models.Paper, error) {
 		links = links[:maxArticles]
 	}
 
+	// 一次性判断所有标题是否与AI相关
+	related := aiRelated(trimmedTitles(titles))
+
 	for i := 0; i < len(titles) && i < len(links) && len(results) < maxArticles; i++ {
 		if len(titles[i]) > 1 && len(links[i]) > 1 {
 			title := strings.TrimSpace(titles[i][1])
 			link := links[i][1]
 
 			// 只获取AI相关文章
-			if isAIRelated(title) {
+			if related[i] {
 				paper := models.Paper{
 					Title:            title,
 					URL:              link,
 					Authors:          []string{"CSDN博客"},
-					PublishedDate:    time.Now(), // 假设为当前时间
+					PublishedDate:    now, // 页面没有发布日期，使用当前时间
+					DateEstimated:    true,
 					Source:           "CSDN",
 					Summary:          fmt.Sprintf("来自CSDN的AI技术文章：%s", title),
 					Keywords:         extractKeywords(title),
@@ -403,13 +410,9 @@ func fetchCSDNArticles() ([]models.Paper, error) {
 }
 
 // 从InfoQ中文站获取热门AI文章
-func fetchInfoQArticles() ([]models.Paper, error) {
-	client := &http.Client{
-		Timeout: 20 * time.Second,
-	}
-
+func fetchInfoQArticles(client *http.Client, baseURL string, now time.Time) ([]models.Paper, error) {
 	// InfoQ AI专区
-	resp, err := client.Get("https://www.infoq.cn/topic/AI")
+	resp, err := client.Get(baseURL + "/topic/AI")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch InfoQ articles: %v", err)
 	}
@@ -450,7 +453,7 @@ func fetchInfoQArticles() ([]models.Paper, error) {
 	for i := 0; i < len(titles) && i < len(links) && len(results) < maxArticles; i++ {
 		if len(titles[i]) > 1 && len(links[i]) > 1 {
 			title := strings.TrimSpace(titles[i][1])
-			link := "https://www.infoq.cn" + links[i][1]
+			link := infoQURL + links[i][1]
 
 			var author string
 			if i < len(authors) && len(authors[i]) > 1 {
@@ -463,7 +466,8 @@ func fetchInfoQArticles() ([]models.Paper, error) {
 				Title:            title,
 				URL:              link,
 				Authors:          []string{author},
-				PublishedDate:    time.Now(), // 假设为当前时间
+				PublishedDate:    now, // 页面没有发布日期，使用当前时间
+				DateEstimated:    true,
 				Source:           "InfoQ",
 				Summary:          fmt.Sprintf("来自InfoQ的AI技术文章：%s", title),
 				Keywords:         extractKeywords(title),
@@ -478,32 +482,29 @@ func fetchInfoQArticles() ([]models.Paper, error) {
 	return results, nil
 }
 
-// 从标题和文本中提取关键词
-func extractKeywords(text string) []string {
-	// 简单的关键词提取实现
-	keywords := make(map[string]bool)
-
-	// AI相关关键词
-	aiTerms := []string{
-		"ai", "artificial intelligence", "machine learning", "ml", "llm", "large language model",
-		"chatgpt", "gpt", "claude", "gemini", "openai", "anthropic", "llama", "mistral",
-		"huggingface", "neural", "deep learning", "diffusion", "transformer", "nlp",
-		"rag", "agents", "multimodal", "vision", "speech", "bert", "rlhf", "fine-tuning",
-	}
-
-	text = strings.ToLower(text)
-	for _, term := range aiTerms {
-		if strings.Contains(text, term) {
-			keywords[term] = true
+// trimmedTitles 返回正则匹配结果中的标题文本，与matches一一对应
+func trimmedTitles(matches [][]string) []string {
+	titles := make([]string, len(matches))
+	for i, m := range matches {
+		if len(m) > 1 {
+			titles[i] = strings.TrimSpace(m[1])
 		}
 	}
+	return titles
+}
 
-	var result []string
-	for keyword := range keywords {
-		result = append(result, keyword)
-	}
+// keywordTermMatcher 匹配需要提取的AI相关关键词
+var keywordTermMatcher = textmatch.New(
+	"ai", "artificial intelligence", "machine learning", "ml", "llm", "large language model",
+	"chatgpt", "gpt", "claude", "gemini", "openai", "anthropic", "llama", "mistral",
+	"huggingface", "neural", "deep learning", "diffusion", "transformer", "nlp",
+	"rag", "agents", "multimodal", "vision", "speech", "bert", "rlhf", "fine-tuning",
+)
 
-	return result
+// 从标题和文本中提取关键词
+func extractKeywords(text string) []string {
+	// 简单的关键词提取实现，按词边界匹配AI相关关键词
+	return keywordTermMatcher.Match(text)
 }
 
 // 计算基于内容的新颖性分数
@@ -580,22 +581,38 @@ func maxFloat64(a, b float64) float64 {
 	return b
 }
 
-// 检查内容是否与AI相关
-func isAIRelated(text string) bool {
-	text = strings.ToLower(text)
+// aiTermMatcher 匹配判断AI相关性的关键词
+var aiTermMatcher = textmatch.New(
+	"ai", "machine learning", "ml", "llm", "language model",
+	"chatgpt", "gpt", "claude", "gemini", "openai", "anthropic",
+	"huggingface", "neural network", "deep learning", "diffusion",
+	"transformer", "nlp", "bert", "rlhf", "fine-tuning", "rag", "agent",
+)
 
-	aiTerms := []string{
-		"ai", "machine learning", "ml", "llm", "language model",
-		"chatgpt", "gpt", "claude", "gemini", "openai", "anthropic",
-		"huggingface", "neural network", "deep learning", "diffusion",
-		"transformer", "nlp", "bert", "rlhf", "fine-tuning", "rag", "agent",
-	}
+// IsAIRelated 检查内容是否与AI相关，配置了语义分类器时优先使用分类器
+func IsAIRelated(text string) bool {
+	return aiRelated([]string{text})[0]
+}
 
-	for _, term := range aiTerms {
-		if strings.Contains(text, term) {
-			return true
+// aiClassifier 返回配置的语义分类器，测试中可替换
+var aiClassifier = classifier.Default
+
+// aiRelated 批量检查多条内容是否与AI相关，配置了分类器时只调用一次分类器，
+// 分类失败时退回关键词匹配
+func aiRelated(texts []string) []bool {
+	related := make([]bool, len(texts))
+	if c := aiClassifier(); c != nil && len(texts) > 0 {
+		results, err := c.Classify(texts)
+		if err == nil && len(results) == len(texts) {
+			for i, result := range results {
+				related[i] = result.AI > classifier.AIThreshold
+			}
+			return related
 		}
 	}
 
-	return false
+	for i, text := range texts {
+		related[i] = aiTermMatcher.MatchAny(text)
+	}
+	return related
 }
@@ -0,0 +1,201 @@
+// Package ranking provides the strategies used to order repositories. The
+// strategy is picked per request with ?rank=, and LLM_NEWS_RANKERS lists the
+// strategies visitors are split between by default, e.g. "weighted,gravity"
+// for an A/B test.
+package ranking
+
+import (
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/textmatch"
+)
+
+// Ranker scores repositories; higher scores rank first
+type Ranker interface {
+	Name() string
+	Score(repo models.Repository, now time.Time) float64
+}
+
+// Strategy names
+const (
+	Weighted   = "weighted"
+	Velocity   = "velocity"
+	Gravity    = "gravity"
+	HiddenGems = "gems"
+)
+
+var rankers = map[string]Ranker{
+	Weighted:   weightedRanker{},
+	Velocity:   velocityRanker{},
+	Gravity:    gravityRanker{gravity: 1.8},
+	HiddenGems: gemsRanker{maxStars: 5000},
+}
+
+// Get returns the ranker with the given name
+func Get(name string) (Ranker, bool) {
+	r, ok := rankers[strings.ToLower(strings.TrimSpace(name))]
+	return r, ok
+}
+
+// Names returns the names of all strategies
+func Names() []string {
+	names := make([]string, 0, len(rankers))
+	for name := range rankers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Arms returns the strategies listed in LLM_NEWS_RANKERS, defaulting to the
+// weighted score alone. Unknown names are skipped.
+func Arms() []Ranker {
+	var arms []Ranker
+	for _, name := range strings.Split(os.Getenv("LLM_NEWS_RANKERS"), ",") {
+		if r, ok := Get(name); ok {
+			arms = append(arms, r)
+		}
+	}
+	if len(arms) == 0 {
+		arms = append(arms, rankers[Weighted])
+	}
+	return arms
+}
+
+// Rank returns a copy of the repositories ordered by the ranker. Ties keep
+// the more starred repository first, then the name order.
+func Rank(r Ranker, repos []models.Repository, now time.Time) []models.Repository {
+	type scored struct {
+		repo  models.Repository
+		score float64
+	}
+	list := make([]scored, len(repos))
+	for i, repo := range repos {
+		list[i] = scored{repo: repo, score: r.Score(repo, now)}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].score != list[j].score {
+			return list[i].score > list[j].score
+		}
+		if list[i].repo.Stars != list[j].repo.Stars {
+			return list[i].repo.Stars > list[j].repo.Stars
+		}
+		return list[i].repo.Name < list[j].repo.Name
+	})
+
+	ranked := make([]models.Repository, len(list))
+	for i, s := range list {
+		ranked[i] = s.repo
+	}
+	return ranked
+}
+
+// StarVelocity returns the stars a repository gains per day as reported by
+// the trending page. Every repository is measured the same way: the
+// snapshot-based velocity of breakout detection exists only for flagged
+// repositories, so mixing it in would favor them over the others.
+func StarVelocity(repo models.Repository) float64 {
+	return float64(repo.TrendMetrics.Stars24h)
+}
+
+// relevantKeywordMatcher 匹配用于计算相关性分数的关键词
+var relevantKeywordMatcher = textmatch.New(
+	"llm", "agent", "multimodal", "rlhf", "diffusion", "agi", "ai", "ml",
+	"gpt", "bert", "transformer", "nlp", "language-model", "claude", "gemini",
+	"fine-tuning", "prompt", "rag", "anthropic", "openai", "text-to-image",
+)
+
+// WeightedBreakdown returns the components of the weighted relevance score:
+// stars (up to 0.25), 24h growth (up to 0.35), commit recency (up to 0.15)
+// and AI keywords (up to 0.35). The relevance score is their sum, capped
+// at 1.
+func WeightedBreakdown(repo models.Repository, now time.Time) map[string]float64 {
+	// Calculate base score based on stars and engagement
+	starsScore := math.Min(float64(repo.Stars)/5000.0, 1.0) * 0.25                // 降低星星权重
+	growthScore := math.Min(float64(repo.TrendMetrics.Stars24h)/50.0, 1.0) * 0.35 // 降低增长率权重
+
+	// Calculate recency score
+	recencyScore := 0.0
+	if !repo.LastCommit.IsZero() {
+		daysSinceLastCommit := now.Sub(repo.LastCommit).Hours() / 24
+		recencyScore = (1.0 - math.Min(daysSinceLastCommit/30.0, 1.0)) * 0.15 // 使用30天作为时间窗口
+	}
+
+	// Calculate keyword relevance score
+	keywordScore := 0.25 // 提高关键词基础分
+
+	// 在名称和描述中查找关键词
+	keywordScore += float64(len(relevantKeywordMatcher.Match(repo.Name))) * 0.03        // 名称匹配给更高权重
+	keywordScore += float64(len(relevantKeywordMatcher.Match(repo.Description))) * 0.01 // 描述匹配给较低权重
+
+	// 检查技术栈中的关键词
+	for _, tech := range repo.TechStack {
+		if relevantKeywordMatcher.MatchAny(tech) {
+			keywordScore += 0.02 // 技术栈匹配
+		}
+	}
+
+	return map[string]float64{
+		"stars":    starsScore,
+		"growth":   growthScore,
+		"recency":  recencyScore,
+		"keywords": math.Min(keywordScore, 0.35), // 限制关键词分数上限
+	}
+}
+
+// weightedRanker orders by the relevance score computed when scraping
+type weightedRanker struct{}
+
+func (weightedRanker) Name() string { return Weighted }
+
+func (weightedRanker) Score(repo models.Repository, now time.Time) float64 {
+	return repo.RelevanceScore
+}
+
+// velocityRanker orders by stars gained per day
+type velocityRanker struct{}
+
+func (velocityRanker) Name() string { return Velocity }
+
+func (velocityRanker) Score(repo models.Repository, now time.Time) float64 {
+	return StarVelocity(repo)
+}
+
+// gravityRanker is the Hacker News formula: the stars gained per day
+// divided by (age in hours + 2) ^ gravity, so new repositories need fewer
+// stars to rank high and old ones decay
+type gravityRanker struct {
+	gravity float64
+}
+
+func (gravityRanker) Name() string { return Gravity }
+
+func (g gravityRanker) Score(repo models.Repository, now time.Time) float64 {
+	created := repo.CreatedAt
+	if created.IsZero() {
+		// 没有创建时间时按一年计算，避免未知年龄的仓库排在前面
+		created = now.AddDate(-1, 0, 0)
+	}
+	age := math.Max(now.Sub(created).Hours(), 0)
+	return StarVelocity(repo) / math.Pow(age+2, g.gravity)
+}
+
+// gemsRanker favors fast-growing repositories that are not yet popular.
+// Repositories above maxStars score zero.
+type gemsRanker struct {
+	maxStars int
+}
+
+func (gemsRanker) Name() string { return HiddenGems }
+
+func (g gemsRanker) Score(repo models.Repository, now time.Time) float64 {
+	if repo.Stars > g.maxStars {
+		return 0
+	}
+	return StarVelocity(repo) / math.Log10(float64(repo.Stars)+10)
+}
@@ -0,0 +1,110 @@
+package ranking
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func testRepos() []models.Repository {
+	return []models.Repository{
+		{
+			Name: "big/established", Stars: 80000, RelevanceScore: 0.9,
+			TrendMetrics: models.TrendMetrics{Stars24h: 300},
+			CreatedAt:    now.AddDate(-3, 0, 0),
+		},
+		{
+			Name: "new/rocket", Stars: 4000, RelevanceScore: 0.6,
+			TrendMetrics: models.TrendMetrics{Stars24h: 200},
+			Breakout:     &models.Breakout{Velocity: 1500},
+			CreatedAt:    now.AddDate(0, 0, -5),
+		},
+		{
+			Name: "small/gem", Stars: 300, RelevanceScore: 0.5,
+			TrendMetrics: models.TrendMetrics{Stars24h: 250},
+			CreatedAt:    now.AddDate(0, -2, 0),
+		},
+		{
+			Name: "quiet/tool", Stars: 1200, RelevanceScore: 0.7,
+			TrendMetrics: models.TrendMetrics{Stars24h: 5},
+		},
+	}
+}
+
+func names(repos []models.Repository) []string {
+	var result []string
+	for _, r := range repos {
+		result = append(result, r.Name)
+	}
+	return result
+}
+
+func TestRankers(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     []string
+	}{
+		{Weighted, []string{"big/established", "quiet/tool", "new/rocket", "small/gem"}},
+		// 所有仓库都按趋势榜的日增星标排序，突破信号中的增速不参与
+		{Velocity, []string{"big/established", "small/gem", "new/rocket", "quiet/tool"}},
+		// 老仓库随年龄衰减，没有创建时间的按一年计算
+		{Gravity, []string{"new/rocket", "small/gem", "big/established", "quiet/tool"}},
+		// 超过5000星的仓库排在最后
+		{HiddenGems, []string{"small/gem", "new/rocket", "quiet/tool", "big/established"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			r, ok := Get(tt.strategy)
+			if !ok || r.Name() != tt.strategy {
+				t.Fatalf("Get(%q) = %v, %v", tt.strategy, r, ok)
+			}
+			if got := names(Rank(r, testRepos(), now)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rank = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankDoesNotModifyInput(t *testing.T) {
+	repos := testRepos()
+	r, _ := Get(Velocity)
+	Rank(r, repos, now)
+	if repos[0].Name != "big/established" {
+		t.Error("Rank should sort a copy")
+	}
+}
+
+func TestArms(t *testing.T) {
+	t.Setenv("LLM_NEWS_RANKERS", "")
+	if arms := Arms(); len(arms) != 1 || arms[0].Name() != Weighted {
+		t.Errorf("default arms = %v", arms)
+	}
+	t.Setenv("LLM_NEWS_RANKERS", " Gravity, bogus ,gems")
+	arms := Arms()
+	if len(arms) != 2 || arms[0].Name() != Gravity || arms[1].Name() != HiddenGems {
+		t.Errorf("arms = %v", arms)
+	}
+	if _, ok := Get("bogus"); ok {
+		t.Error("unknown strategies should not resolve")
+	}
+}
+
+func TestWeightedBreakdown(t *testing.T) {
+	repo := models.Repository{
+		Name:         "acme/llm-agent",
+		Stars:        10000,
+		TrendMetrics: models.TrendMetrics{Stars24h: 100},
+		LastCommit:   now,
+	}
+	b := WeightedBreakdown(repo, now)
+	want := map[string]float64{"stars": 0.25, "growth": 0.35, "recency": 0.15, "keywords": 0.31}
+	for k, v := range want {
+		if diff := b[k] - v; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s = %v, want %v", k, b[k], v)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Package readme analyzes repository READMEs for the documentation signals
+// readers care about (installation, usage examples, license, benchmarks,
+// released weights and citations) and extracts model and paper links.
+package readme
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/textmatch"
+)
+
+// 各项文档信号在质量分中的权重，合计为1
+const (
+	weightInstallation = 0.2
+	weightUsage        = 0.2
+	weightLicense      = 0.1
+	weightBenchmarks   = 0.15
+	weightModelWeights = 0.1
+	weightCitation     = 0.1
+	weightStructure    = 0.15
+)
+
+// 结构完整的README至少有这么多小节和字符
+const (
+	minSections = 3
+	minLength   = 1500
+)
+
+var (
+	installHeading   = textmatch.New("install", "installation", "setup", "getting started", "requirements", "安装", "环境配置", "快速开始")
+	usageHeading     = textmatch.New("usage", "example", "quick start", "quickstart", "tutorial", "demo", "inference", "how to use", "使用", "示例", "快速开始", "推理")
+	licenseHeading   = textmatch.New("license", "licence", "许可", "开源协议")
+	benchmarkTerms   = textmatch.New("benchmark", "evaluation", "results", "performance", "leaderboard", "mmlu", "humaneval", "gsm8k", "accuracy", "评测", "性能", "实验结果")
+	citationHeading  = textmatch.New("citation", "cite", "bibtex", "引用")
+	installCommandRe = regexp.MustCompile(`(?m)^\s*[$>]?\s*(pip3? install|conda install|npm (install|i)|yarn add|pnpm add|go (get|install)|cargo install|brew install|docker (pull|run)|git clone|uv (pip install|add)|make install)\b`)
+	tableDividerRe   = regexp.MustCompile(`(?m)^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)+\|?\s*$`)
+	bibtexRe         = regexp.MustCompile(`(?i)@(article|inproceedings|misc|techreport|book|software)\s*\{`)
+	licenseTextRe    = regexp.MustCompile(`(?i)(licensed under|\]\(\.?/?LICENSE|apache license|mit license|gnu general public license|creative commons)`)
+
+	huggingFaceRe = regexp.MustCompile(`https?://huggingface\.co/((?:datasets/|spaces/)?[A-Za-z0-9][\w.-]*/[\w.-]+|papers/\d{4}\.\d{4,5})`)
+	arxivRe       = regexp.MustCompile(`https?://(?:www\.)?arxiv\.org/(?:abs|pdf)/(\d{4}\.\d{4,5})`)
+	githubRe      = regexp.MustCompile(`https?://(?:www\.)?github\.com/([A-Za-z0-9][\w.-]*)/([\w.-]+)`)
+)
+
+// huggingFaceReserved are first path segments of huggingface.co that are
+// site pages rather than organizations
+var huggingFaceReserved = map[string]bool{
+	"docs": true, "blog": true, "learn": true, "models": true, "tasks": true,
+	"join": true, "login": true, "pricing": true, "settings": true, "collections": true,
+}
+
+// Analyze scores a README written in Markdown. The score is between 0 and 1.
+func Analyze(content string) models.DocQuality {
+	var q models.DocQuality
+	if strings.TrimSpace(content) == "" {
+		return q
+	}
+
+	headings, codeBlocks, prose := split(content)
+
+	headingMatches := func(m *textmatch.Matcher) bool {
+		for _, h := range headings {
+			if m.MatchAny(h) {
+				return true
+			}
+		}
+		return false
+	}
+
+	q.HuggingFaceLinks, q.PaperLinks = extractLinks(content)
+
+	q.Installation = headingMatches(installHeading) || installCommandRe.MatchString(strings.Join(codeBlocks, "\n"))
+	// 有“使用”类小节，或除安装命令外至少还有一段代码
+	q.Usage = headingMatches(usageHeading) || countNonInstallBlocks(codeBlocks) >= 1
+	q.License = headingMatches(licenseHeading) || licenseTextRe.MatchString(prose)
+	q.Benchmarks = tableDividerRe.MatchString(prose) &&
+		(headingMatches(benchmarkTerms) || benchmarkTerms.MatchAny(prose))
+	q.Citation = headingMatches(citationHeading) || bibtexRe.MatchString(content)
+	for _, link := range q.HuggingFaceLinks {
+		if isModelLink(link) {
+			q.ModelWeights = true
+			break
+		}
+	}
+
+	score := 0.0
+	for _, signal := range []struct {
+		present bool
+		weight  float64
+	}{
+		{q.Installation, weightInstallation},
+		{q.Usage, weightUsage},
+		{q.License, weightLicense},
+		{q.Benchmarks, weightBenchmarks},
+		{q.ModelWeights, weightModelWeights},
+		{q.Citation, weightCitation},
+	} {
+		if signal.present {
+			score += signal.weight
+		}
+	}
+	// 结构分按小节数和篇幅各占一半
+	score += weightStructure / 2 * minFloat(float64(len(headings))/minSections, 1)
+	score += weightStructure / 2 * minFloat(float64(len([]rune(content)))/minLength, 1)
+
+	q.Score = float64(int(score*100+0.5)) / 100
+	return q
+}
+
+// split separates the headings, fenced code blocks and remaining text of a
+// Markdown document
+func split(content string) (headings, codeBlocks []string, prose string) {
+	var text, block strings.Builder
+	inCode := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if inCode {
+				codeBlocks = append(codeBlocks, block.String())
+				block.Reset()
+			} else if lang := strings.ToLower(strings.Trim(trimmed, "`~ ")); lang == "bibtex" || lang == "bib" {
+				// BibTeX代码块也计入正文，便于识别引用
+				text.WriteString(line + "\n")
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			block.WriteString(line + "\n")
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			headings = append(headings, strings.TrimSpace(strings.TrimLeft(trimmed, "#")))
+		} else if h := htmlHeading(trimmed); h != "" {
+			headings = append(headings, h)
+		}
+		text.WriteString(line + "\n")
+	}
+	if inCode {
+		codeBlocks = append(codeBlocks, block.String())
+	}
+	return headings, codeBlocks, text.String()
+}
+
+var htmlHeadingRe = regexp.MustCompile(`(?i)^<h[1-6][^>]*>(.*?)</h[1-6]>`)
+
+func htmlHeading(line string) string {
+	if m := htmlHeadingRe.FindStringSubmatch(line); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func countNonInstallBlocks(blocks []string) int {
+	count := 0
+	for _, block := range blocks {
+		if strings.TrimSpace(block) != "" && !installCommandRe.MatchString(block) {
+			count++
+		}
+	}
+	return count
+}
+
+// extractLinks returns the Hugging Face and paper links of a README, sorted
+// and without duplicates. arXiv PDF links are normalized to abstract pages.
+func extractLinks(content string) (huggingFace, papers []string) {
+	hf := make(map[string]bool)
+	paperSet := make(map[string]bool)
+
+	for _, m := range huggingFaceRe.FindAllStringSubmatch(content, -1) {
+		path := strings.TrimRight(m[1], ".")
+		owner := strings.SplitN(path, "/", 2)[0]
+		if huggingFaceReserved[owner] {
+			continue
+		}
+		if strings.HasPrefix(path, "papers/") {
+			paperSet["https://arxiv.org/abs/"+strings.TrimPrefix(path, "papers/")] = true
+			continue
+		}
+		hf["https://huggingface.co/"+path] = true
+	}
+	for _, m := range arxivRe.FindAllStringSubmatch(content, -1) {
+		paperSet["https://arxiv.org/abs/"+m[1]] = true
+	}
+	return sortedKeys(hf), sortedKeys(paperSet)
+}
+
+// PaperLinks returns the arXiv papers referenced by a README or model card as
+// abstract page URLs
+func PaperLinks(content string) []string {
+	_, papers := extractLinks(content)
+	return papers
+}
+
+// githubReserved are first path segments of github.com that are site pages
+// rather than users or organizations
+var githubReserved = map[string]bool{
+	"orgs": true, "sponsors": true, "features": true, "topics": true, "marketplace": true,
+	"settings": true, "apps": true, "about": true, "collections": true, "login": true,
+}
+
+// GitHubRepos returns the GitHub repositories referenced by a README or
+// model card, in order of first appearance and without duplicates
+func GitHubRepos(content string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, m := range githubRe.FindAllStringSubmatch(content, -1) {
+		owner, name := m[1], strings.TrimSuffix(strings.TrimRight(m[2], "."), ".git")
+		if githubReserved[strings.ToLower(owner)] || name == "" {
+			continue
+		}
+		link := "https://github.com/" + owner + "/" + name
+		if key := strings.ToLower(link); !seen[key] {
+			seen[key] = true
+			result = append(result, link)
+		}
+	}
+	return result
+}
+
+// isModelLink reports whether a Hugging Face link points to a model rather
+// than a dataset or Space
+func isModelLink(link string) bool {
+	path := strings.TrimPrefix(link, "https://huggingface.co/")
+	return !strings.HasPrefix(path, "datasets/") && !strings.HasPrefix(path, "spaces/")
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,95 @@
+package readme
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const richReadme = `# Awesome-LLM
+
+An open 7B language model. Weights: https://huggingface.co/acme/awesome-7b (see also
+[the docs](https://huggingface.co/docs/transformers) and https://huggingface.co/datasets/acme/pretrain-mix).
+
+Paper: https://arxiv.org/pdf/2401.01234v2 and https://huggingface.co/papers/2401.01234
+
+## Installation
+
+` + "```bash\npip install awesome-llm\n```" + `
+
+## Quick Start
+
+` + "```python\nfrom awesome import Model\nmodel = Model.from_pretrained(\"acme/awesome-7b\")\n```" + `
+
+## Benchmarks
+
+| Model | MMLU | GSM8K |
+|-------|------|-------|
+| awesome-7b | 64.1 | 52.3 |
+
+## Citation
+
+` + "```bibtex\n@article{acme2024awesome,\n  title={Awesome}\n}\n```" + `
+
+## License
+
+This project is licensed under the Apache License 2.0.
+`
+
+func TestAnalyzeRichReadme(t *testing.T) {
+	q := Analyze(richReadme)
+
+	if !q.Installation || !q.Usage || !q.License || !q.Benchmarks || !q.ModelWeights || !q.Citation {
+		t.Errorf("expected all signals, got %+v", q)
+	}
+	if q.Score < 0.9 || q.Score > 1 {
+		t.Errorf("unexpected score %.2f", q.Score)
+	}
+
+	wantHF := []string{"https://huggingface.co/acme/awesome-7b", "https://huggingface.co/datasets/acme/pretrain-mix"}
+	if !reflect.DeepEqual(q.HuggingFaceLinks, wantHF) {
+		t.Errorf("HuggingFaceLinks = %q, want %q", q.HuggingFaceLinks, wantHF)
+	}
+	wantPapers := []string{"https://arxiv.org/abs/2401.01234"}
+	if !reflect.DeepEqual(q.PaperLinks, wantPapers) {
+		t.Errorf("PaperLinks = %q, want %q", q.PaperLinks, wantPapers)
+	}
+}
+
+func TestAnalyzeSparseReadme(t *testing.T) {
+	q := Analyze("# tool\n\nA small tool.\n")
+	if q.Installation || q.Usage || q.License || q.Benchmarks || q.ModelWeights || q.Citation {
+		t.Errorf("expected no signals, got %+v", q)
+	}
+	if q.Score > 0.1 {
+		t.Errorf("unexpected score %.2f", q.Score)
+	}
+	if Analyze("").Score != 0 {
+		t.Errorf("empty README should score 0")
+	}
+}
+
+func TestAnalyzeChineseReadme(t *testing.T) {
+	content := strings.Join([]string{
+		"# 模型介绍", "## 安装", "```\ngit clone https://github.com/acme/model\n```",
+		"## 使用方法", "```python\nimport model\n```", "## 评测结果",
+		"| 模型 | 得分 |", "| --- | --- |", "| A | 1 |", "## 开源协议", "MIT",
+	}, "\n")
+	q := Analyze(content)
+	if !q.Installation || !q.Usage || !q.Benchmarks || !q.License {
+		t.Errorf("expected installation, usage, benchmarks and license, got %+v", q)
+	}
+	if q.ModelWeights || q.Citation {
+		t.Errorf("unexpected weights or citation signal: %+v", q)
+	}
+}
+
+func TestDataOnlyLinksAreNotWeights(t *testing.T) {
+	q := Analyze("Data at https://huggingface.co/datasets/acme/corpus and demo at https://huggingface.co/spaces/acme/demo.")
+	if q.ModelWeights {
+		t.Errorf("datasets and Spaces should not count as model weights")
+	}
+	if len(q.HuggingFaceLinks) != 2 {
+		t.Errorf("unexpected links %q", q.HuggingFaceLinks)
+	}
+}
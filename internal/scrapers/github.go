@@ -14,59 +14,91 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/gerryyang2025/llm-news/internal/classifier"
+	"github.com/gerryyang2025/llm-news/internal/health"
+	"github.com/gerryyang2025/llm-news/internal/i18n"
 	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/ranking"
+	"github.com/gerryyang2025/llm-news/internal/summarizer"
+	"github.com/gerryyang2025/llm-news/internal/textmatch"
 )
 
+// GitHub endpoints, replaced by httptest servers in tests
+const (
+	githubURL    = "https://github.com"
+	githubAPIURL = "https://api.github.com"
+)
+
+// trendingPaths are the trending pages scraped: daily, weekly and monthly
+// trending plus the languages most AI projects are written in
+var trendingPaths = []string{
+	"/trending",                  // Daily trending
+	"/trending?since=weekly",     // Weekly trending
+	"/trending?since=monthly",    // Monthly trending
+	"/trending/python",           // Python trending
+	"/trending/javascript",       // JavaScript trending
+	"/trending/typescript",       // TypeScript trending
+	"/trending/jupyter-notebook", // Jupyter Notebook trending
+	"/trending/cpp",              // C++ trending
+	"/trending/go",               // GoLang trending
+}
+
 // ScrapeGithubTrending scrapes the GitHub trending page and returns repositories
 // filtered by AI-related keywords
 func ScrapeGithubTrending() ([]models.Repository, error) {
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+	}
+	return scrapeGithubTrending(client, githubURL, githubAPIURL, time.Now())
+}
+
+// scrapeGithubTrending scrapes the trending pages under webURL and enriches
+// the repositories through the REST API at apiURL
+func scrapeGithubTrending(client *http.Client, webURL, apiURL string, now time.Time) ([]models.Repository, error) {
 	// Get repositories from GitHub trending
-	repos, err := scrapeBasicTrendingInfo()
+	repos, err := scrapeBasicTrendingInfo(client, webURL, apiURL, now)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter repositories by AI-related keywords
-	aiRepos := filterReposByKeywords(repos, models.AIKeywords)
+	// Filter repositories by AI-related keywords, or semantically when a classifier is configured
+	aiRepos := FilterAIRepos(repos)
 
 	// Enrich repositories with additional information
 	for i := range aiRepos {
-		enrichRepositoryDetails(&aiRepos[i])
+		enrichRepositoryDetails(client, apiURL, &aiRepos[i])
 	}
 
 	// Apply filter criteria
-	filteredRepos := applyFilterCriteria(aiRepos, models.DefaultFilterCriteria())
+	filteredRepos := applyFilterCriteria(aiRepos, models.DefaultFilterCriteria(), now)
 
 	// Calculate relevance scores
-	calculateRelevanceScores(filteredRepos)
+	CalculateRelevanceScores(filteredRepos, now)
 
-	return filteredRepos, nil
-}
+	models.AssignRepoIDs(filteredRepos)
 
-// scrapeBasicTrendingInfo scrapes basic information from GitHub trending page
-func scrapeBasicTrendingInfo() ([]models.Repository, error) {
-	// GitHub trending URLs - we'll get daily, weekly and monthly trending
-	urls := []string{
-		"https://github.com/trending",                  // Daily trending
-		"https://github.com/trending?since=weekly",     // Weekly trending
-		"https://github.com/trending?since=monthly",    // Monthly trending
-		"https://github.com/trending/python",           // Python trending
-		"https://github.com/trending/javascript",       // JavaScript trending
-		"https://github.com/trending/typescript",       // TypeScript trending
-		"https://github.com/trending/jupyter-notebook", // Jupyter Notebook trending
-		"https://github.com/trending/cpp",              // C++ trending
-		"https://github.com/trending/go",               // GoLang trending
+	// 配置了LLM时生成仓库简介
+	if s := summarizer.Default(); s != nil {
+		s.SummarizeRepos(filteredRepos)
 	}
 
-	allRepos := []models.Repository{}
-
-	// Make HTTP requests to both URLs
-	client := &http.Client{
-		Timeout: 15 * time.Second,
+	// 配置了翻译时生成中英文对照的简介
+	if l := i18n.DefaultLocalizer(); l != nil {
+		l.TranslateRepos(filteredRepos)
 	}
 
+	return filteredRepos, nil
+}
+
+// scrapeBasicTrendingInfo scrapes basic information from GitHub trending page.
+// It returns a *health.DegradedError when the parsed fields fall below their
+// expected fill-rates.
+func scrapeBasicTrendingInfo(client *http.Client, webURL, apiURL string, now time.Time) ([]models.Repository, error) {
+	allRepos := []models.Repository{}
+
 	// Process each URL
-	for _, url := range urls {
+	for _, path := range trendingPaths {
+		url := webURL + path
 		resp, err := client.Get(url)
 		if err != nil {
 			log.Printf("Warning: Failed to fetch %s: %v", url, err)
@@ -152,7 +184,7 @@ func scrapeBasicTrendingInfo() ([]models.Repository, error) {
 				}
 			}
 
-			repo.LastUpdated = time.Now()
+			repo.LastUpdated = now
 			repo.RelevanceScore = 0.5 // Default mid-level score
 
 			// Skip duplicate repositories
@@ -166,9 +198,14 @@ func scrapeBasicTrendingInfo() ([]models.Repository, error) {
 		})
 	}
 
+	// 在用API补充数据之前校验趋势页解析结果，选择器失效时不返回残缺数据
+	if report := health.CheckTrending(allRepos); report.Degraded() {
+		return nil, &health.DegradedError{Report: report}
+	}
+
 	// 尝试补充额外的仓库，如果当前数量不足50个
 	if len(allRepos) < 50 {
-		additionalRepos, err := fetchAdditionalRepos(50 - len(allRepos))
+		additionalRepos, err := fetchAdditionalRepos(client, apiURL, 50-len(allRepos), now)
 		if err == nil && len(additionalRepos) > 0 {
 			for _, repo := range additionalRepos {
 				// 检查是否存在重复
@@ -194,7 +231,7 @@ func scrapeBasicTrendingInfo() ([]models.Repository, error) {
 }
 
 // fetchAdditionalRepos fetches additional repositories using GitHub API search
-func fetchAdditionalRepos(count int) ([]models.Repository, error) {
+func fetchAdditionalRepos(client *http.Client, apiURL string, count int, now time.Time) ([]models.Repository, error) {
 	if count <= 0 {
 		return []models.Repository{}, nil
 	}
@@ -221,10 +258,6 @@ func fetchAdditionalRepos(count int) ([]models.Repository, error) {
 		"language:go topic:rag sort:stars",
 	}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
 	additionalRepos := []models.Repository{}
 
 	// 每个查询获取一定数量，直到达到目标数量
@@ -236,8 +269,8 @@ func fetchAdditionalRepos(count int) ([]models.Repository, error) {
 		}
 
 		// 构建GitHub搜索API URL
-		url := fmt.Sprintf("https://api.github.com/search/repositories?q=%s&per_page=%d",
-			strings.ReplaceAll(query, " ", "+"), perQueryCount)
+		url := fmt.Sprintf("%s/search/repositories?q=%s&per_page=%d",
+			apiURL, strings.ReplaceAll(query, " ", "+"), perQueryCount)
 
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
@@ -294,7 +327,7 @@ func fetchAdditionalRepos(count int) ([]models.Repository, error) {
 				Language:    item.Language,
 				Stars:       item.StargazersCount,
 				Forks:       item.ForksCount,
-				LastUpdated: time.Now(),
+				LastUpdated: now,
 				TechStack:   item.Topics,
 				TrendMetrics: models.TrendMetrics{
 					// 估算星星增长数
@@ -323,7 +356,7 @@ func fetchAdditionalRepos(count int) ([]models.Repository, error) {
 }
 
 // enrichRepositoryDetails adds additional information to a repository using GitHub API
-func enrichRepositoryDetails(repo *models.Repository) {
+func enrichRepositoryDetails(client *http.Client, apiURL string, repo *models.Repository) {
 	// Extract owner and repo name
 	parts := strings.Split(repo.Name, "/")
 	if len(parts) != 2 {
@@ -334,12 +367,7 @@ func enrichRepositoryDetails(repo *models.Repository) {
 	repoName := parts[1]
 
 	// GitHub API URL
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repoName)
-
-	// Make HTTP request
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	url := fmt.Sprintf("%s/repos/%s/%s", apiURL, owner, repoName)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -371,6 +399,7 @@ func enrichRepositoryDetails(repo *models.Repository) {
 		Language        string   `json:"language"`
 		StargazersCount int      `json:"stargazers_count"`
 		ForksCount      int      `json:"forks_count"`
+		CreatedAt       string   `json:"created_at"`
 		UpdatedAt       string   `json:"updated_at"`
 		PushedAt        string   `json:"pushed_at"`
 		Topics          []string `json:"topics"`
@@ -405,6 +434,9 @@ func enrichRepositoryDetails(repo *models.Repository) {
 			repo.LastCommit = t
 		}
 	}
+	if t, err := time.Parse(time.RFC3339, githubRepo.CreatedAt); err == nil {
+		repo.CreatedAt = t
+	}
 
 	// Set tech stack from topics
 	if len(githubRepo.Topics) > 0 {
@@ -425,21 +457,8 @@ func enrichRepositoryDetails(repo *models.Repository) {
 		repo.DocsURL = fmt.Sprintf("https://github.com/%s/wiki", repo.Name)
 	}
 
-	// Check if README exists
-	readmeURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/readme", owner, repoName)
-	readmeReq, err := http.NewRequest("GET", readmeURL, nil)
-	if err == nil {
-		readmeReq.Header.Add("User-Agent", "LLM-News-Agent")
-		readmeResp, err := client.Do(readmeReq)
-		if err == nil && readmeResp.StatusCode == http.StatusOK {
-			repo.HasDocs = true
-			repo.HasReadme = true
-			if repo.DocsURL == "" {
-				repo.DocsURL = fmt.Sprintf("https://github.com/%s#readme", repo.Name)
-			}
-			readmeResp.Body.Close()
-		}
-	}
+	// Fetch and analyze the README
+	applyReadme(client, apiURL, repo, owner, repoName)
 
 	// Calculate forks gained
 	// For simplicity, we'll estimate this based on the stars gained
@@ -454,42 +473,32 @@ func enrichRepositoryDetails(repo *models.Repository) {
 	repo.GetModelCategories()
 }
 
-// filterReposByKeywords filters repositories by checking if their name or description
+// coreKeywordMatcher 匹配强相关的核心关键词
+var coreKeywordMatcher = textmatch.New("llm", "ai", "ml", "gpt", "bert", "nlp", "language-model", "machine-learning", "deep-learning")
+
+// FilterReposByKeywords filters repositories by checking if their name or description
 // contains any of the given keywords
-func filterReposByKeywords(repos []models.Repository, keywords []string) []models.Repository {
+func FilterReposByKeywords(repos []models.Repository, keywords []string) []models.Repository {
 	filtered := []models.Repository{}
 
 	// 添加更多可能相关的仓库
 	potentialRepos := []models.Repository{}
 
+	allKeywords := textmatch.New(keywords...)
+
 	for _, repo := range repos {
-		lowerName := strings.ToLower(repo.Name)
-		lowerDesc := strings.ToLower(repo.Description)
+		tokens := textmatch.Tokenize(repo.Name + " " + repo.Description)
 
 		// 强匹配: 名称或描述中直接包含核心关键词
-		coreKeywords := []string{"llm", "ai", "ml", "gpt", "bert", "nlp", "language-model", "machine-learning", "deep-learning"}
-
-		// 检查核心关键词
-		foundCore := false
-		for _, keyword := range coreKeywords {
-			if strings.Contains(lowerName, keyword) || strings.Contains(lowerDesc, keyword) {
-				filtered = append(filtered, repo)
-				log.Printf("Found AI repository: %s", repo.Name)
-				foundCore = true
-				break
-			}
-		}
-
-		if foundCore {
+		if coreKeywordMatcher.MatchAnyTokens(tokens) {
+			filtered = append(filtered, repo)
+			log.Printf("Found AI repository: %s", repo.Name)
 			continue // 已经添加过，跳过后续检查
 		}
 
 		// 弱匹配: 检查所有关键词
-		for _, keyword := range keywords {
-			if strings.Contains(lowerName, keyword) || strings.Contains(lowerDesc, keyword) {
-				potentialRepos = append(potentialRepos, repo)
-				break
-			}
+		if allKeywords.MatchAnyTokens(tokens) {
+			potentialRepos = append(potentialRepos, repo)
 		}
 	}
 
@@ -502,8 +511,44 @@ func filterReposByKeywords(repos []models.Repository, keywords []string) []model
 	return filtered
 }
 
+// FilterAIRepos keeps the AI related repositories, using the classifier
+// when one is configured and keyword matching otherwise
+func FilterAIRepos(repos []models.Repository) []models.Repository {
+	if c := classifier.Default(); c != nil {
+		return classifyRepos(c, repos)
+	}
+	return FilterReposByKeywords(repos, models.AIKeywords)
+}
+
+// classifyRepos keeps the repositories the classifier considers AI related
+// and assigns their model categories from the category probabilities
+func classifyRepos(c classifier.Classifier, repos []models.Repository) []models.Repository {
+	texts := make([]string, len(repos))
+	for i, repo := range repos {
+		texts[i] = repo.Name + " " + repo.Description + " " + strings.Join(repo.TechStack, " ")
+	}
+
+	results, err := c.Classify(texts)
+	if err != nil {
+		log.Printf("Warning: Failed to classify repositories, using keyword filter: %v", err)
+		return FilterReposByKeywords(repos, models.AIKeywords)
+	}
+
+	filtered := []models.Repository{}
+	for i, repo := range repos {
+		if results[i].AI <= classifier.AIThreshold {
+			continue
+		}
+		repo.CategoryScores = results[i].Categories
+		repo.ModelCategories = results[i].Labels(classifier.CategoryThreshold)
+		filtered = append(filtered, repo)
+		log.Printf("Found AI repository: %s (p=%.2f)", repo.Name, results[i].AI)
+	}
+	return filtered
+}
+
 // applyFilterCriteria filters repositories based on the specified criteria
-func applyFilterCriteria(repos []models.Repository, criteria models.FilterCriteria) []models.Repository {
+func applyFilterCriteria(repos []models.Repository, criteria models.FilterCriteria, now time.Time) []models.Repository {
 	// 如果仓库数量少于50个，则跳过过滤直接返回
 	if len(repos) < 50 {
 		return repos
@@ -512,30 +557,9 @@ func applyFilterCriteria(repos []models.Repository, criteria models.FilterCriter
 	filtered := []models.Repository{}
 
 	for _, repo := range repos {
-		// Check minimum stars growth rate
-		if repo.TrendMetrics.Stars24h < criteria.MinStarsGrowthRate {
-			continue
+		if criteria.MatchesAt(repo, now) {
+			filtered = append(filtered, repo)
 		}
-
-		// Check maximum days since last commit
-		if !repo.LastCommit.IsZero() {
-			daysSinceLastCommit := time.Since(repo.LastCommit).Hours() / 24
-			if daysSinceLastCommit > float64(criteria.MaxDaysSinceCommit) {
-				continue
-			}
-		}
-
-		// Check documentation requirement
-		if criteria.RequiresDocumentation && !repo.HasDocs {
-			continue
-		}
-
-		// Check minimum relevance score
-		if repo.RelevanceScore < criteria.MinRelevanceScore {
-			continue
-		}
-
-		filtered = append(filtered, repo)
 	}
 
 	// 如果过滤后数量不足，则适当降低标准，保留最相关的仓库
@@ -557,58 +581,16 @@ func applyFilterCriteria(repos []models.Repository, criteria models.FilterCriter
 	return filtered
 }
 
-// calculateRelevanceScores calculates relevance scores for repositories
-func calculateRelevanceScores(repos []models.Repository) {
+// CalculateRelevanceScores calculates relevance scores for repositories as of now
+// with the weighted formula of the ranking package
+func CalculateRelevanceScores(repos []models.Repository, now time.Time) {
 	for i := range repos {
-		// Calculate base score based on stars and engagement
-		starsScore := minFloat(float64(repos[i].Stars)/5000.0, 1.0) * 0.25                // 降低星星权重
-		growthScore := minFloat(float64(repos[i].TrendMetrics.Stars24h)/50.0, 1.0) * 0.35 // 降低增长率权重
-		// Calculate recency score
-		recencyScore := 0.0
-		if !repos[i].LastCommit.IsZero() {
-			daysSinceLastCommit := time.Since(repos[i].LastCommit).Hours() / 24
-			recencyScore = (1.0 - minFloat(daysSinceLastCommit/30.0, 1.0)) * 0.15 // 使用30天作为时间窗口
-		}
-
-		// Calculate keyword relevance score
-		keywordScore := 0.25 // 提高关键词基础分
-		relevantKeywords := []string{
-			"llm", "agent", "multimodal", "rlhf", "diffusion", "agi", "ai", "ml",
-			"gpt", "bert", "transformer", "nlp", "language-model", "claude", "gemini",
-			"fine-tuning", "prompt", "rag", "anthropic", "openai", "text-to-image",
-		}
-
-		// 在名称和描述中查找关键词
-		lowerName := strings.ToLower(repos[i].Name)
-		lowerDesc := strings.ToLower(repos[i].Description)
-
-		for _, keyword := range relevantKeywords {
-			if strings.Contains(lowerName, keyword) {
-				keywordScore += 0.03 // 名称匹配给更高权重
-			}
-			if strings.Contains(lowerDesc, keyword) {
-				keywordScore += 0.01 // 描述匹配给较低权重
-			}
-		}
-
-		// 检查技术栈中的关键词
-		for _, tech := range repos[i].TechStack {
-			techLower := strings.ToLower(tech)
-			for _, keyword := range relevantKeywords {
-				if strings.Contains(techLower, keyword) {
-					keywordScore += 0.02 // 技术栈匹配
-					break
-				}
-			}
-		}
-
-		keywordScore = minFloat(keywordScore, 0.35) // 限制关键词分数上限
-
-		// Sum up for final score
-		repos[i].RelevanceScore = starsScore + growthScore + recencyScore + keywordScore
+		breakdown := ranking.WeightedBreakdown(repos[i], now)
 
-		// Ensure the score is between 0 and 1
-		repos[i].RelevanceScore = minFloat(repos[i].RelevanceScore, 1.0)
+		// Sum up for final score, ensuring it is between 0 and 1
+		score := breakdown["stars"] + breakdown["growth"] + breakdown["recency"] + breakdown["keywords"]
+		repos[i].RelevanceScore = minFloat(score, 1.0)
+		repos[i].ScoreBreakdown = breakdown
 	}
 }
 
@@ -0,0 +1,208 @@
+package scrapers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/health"
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+var fixtureNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// checkGolden compares v, encoded as indented JSON, with testdata/golden/name.
+// Run the tests with -update to rewrite the file after an intended change.
+func checkGolden(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode %s: %v", name, err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match the golden file, run with -update if the change is intended:\n%s", name, got)
+	}
+}
+
+// newFixtureServer serves testdata files by request path. Paths not listed
+// return 404, like pages GitHub removed or repositories without a README.
+func newFixtureServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 趋势页按since参数区分
+		key := r.URL.Path
+		if since := r.URL.Query().Get("since"); since != "" {
+			key += "?since=" + since
+		}
+		name, ok := routes[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if ua := r.Header.Get("User-Agent"); strings.HasPrefix(r.URL.Path, "/repos/") && ua != "LLM-News-Agent" {
+			t.Errorf("GitHub API request %s sent User-Agent %q", r.URL.Path, ua)
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Errorf("failed to read fixture %s: %v", name, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// githubRoutes maps the trending pages, the search API and the repository
+// API to the recorded responses
+func githubRoutes() map[string]string {
+	routes := map[string]string{
+		"/trending":                   "github/trending.html",
+		"/trending?since=weekly":      "github/trending_weekly.html",
+		"/search/repositories":        "github/search.json",
+		"/repos/ollama/ollama/readme": "github/readmes/ollama__ollama.md",
+	}
+	for _, name := range []string{"ollama/ollama", "deepseek-ai/DeepSeek-V3", "browser-use/browser-use", "unslothai/unsloth", "microsoft/LoRA", "huggingface/diffusers"} {
+		routes["/repos/"+name] = "github/repos/" + strings.Replace(name, "/", "__", 1) + ".json"
+	}
+	return routes
+}
+
+func TestScrapeGithubTrending(t *testing.T) {
+	t.Setenv("LLM_NEWS_CLASSIFIER", "")
+	server := newFixtureServer(t, githubRoutes())
+
+	repos, err := scrapeGithubTrending(server.Client(), server.URL, server.URL, fixtureNow)
+	if err != nil {
+		t.Fatalf("scrapeGithubTrending returned error: %v", err)
+	}
+
+	byName := make(map[string]models.Repository)
+	for _, repo := range repos {
+		byName[repo.Name] = repo
+	}
+	// 非AI仓库被过滤，搜索结果补充的仓库不重复
+	for _, name := range []string{"excalidraw/excalidraw", "microsoft/markitdown"} {
+		if _, ok := byName[name]; ok {
+			t.Errorf("%s should be filtered out", name)
+		}
+	}
+	ollama, ok := byName["ollama/ollama"]
+	if !ok {
+		t.Fatalf("ollama/ollama missing from %d repositories", len(repos))
+	}
+	if ollama.GainedStars != 1021 || ollama.TrendMetrics.Stars24h != 1021 || ollama.Stars != 128502 {
+		t.Errorf("unexpected ollama star counts: gained %d, 24h %d, total %d", ollama.GainedStars, ollama.TrendMetrics.Stars24h, ollama.Stars)
+	}
+	if ollama.DocQuality == nil || !ollama.HasReadme {
+		t.Error("ollama README should be analyzed")
+	}
+	if unsloth := byName["unslothai/unsloth"]; unsloth.TrendMetrics.Stars24h != 410 {
+		t.Errorf("weekly gain should be averaged per day, got %d", unsloth.TrendMetrics.Stars24h)
+	}
+	if _, ok := byName["huggingface/transformers"]; !ok {
+		t.Error("search results should fill up the trending list")
+	}
+
+	checkGolden(t, "github_trending.json", repos)
+}
+
+// TestScrapeGithubTrendingMarkupChange makes sure a trending page the
+// selectors no longer match fails loudly instead of returning broken data
+func TestScrapeGithubTrendingMarkupChange(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join("testdata", "github", "trending.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		old, new  string
+		wantField string // 期望被标记为degraded的字段，为空表示直接失败
+	}{
+		{"article class", `<article class="Box-row">`, `<article class="Box-row-v2">`, ""},
+		{"stars link", `/stargazers"`, `/stars"`, "stars"},
+		{"description tag", `<p class=`, `<div class=`, "description"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := strings.ReplaceAll(string(page), tt.old, tt.new)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/trending" || r.URL.Query().Get("since") != "" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(changed))
+			}))
+			defer server.Close()
+
+			repos, err := scrapeBasicTrendingInfo(server.Client(), server.URL, server.URL, fixtureNow)
+			if err == nil {
+				t.Fatalf("expected an error, got %d repositories", len(repos))
+			}
+			var degraded *health.DegradedError
+			if !errors.As(err, &degraded) {
+				if tt.wantField != "" {
+					t.Fatalf("expected a degraded error, got %v", err)
+				}
+				return
+			}
+			if tt.wantField == "" {
+				t.Fatalf("expected a plain error, got %v", err)
+			}
+			if rate := degraded.Report.FillRates[tt.wantField]; rate >= 0.5 {
+				t.Errorf("%s fill-rate = %v, want it to drop", tt.wantField, rate)
+			}
+		})
+	}
+}
+
+func TestScrapePapersWithCode(t *testing.T) {
+	routes := githubRoutes()
+	routes["/api/v1/papers/"] = "paperswithcode/papers.json"
+	server := newFixtureServer(t, routes)
+
+	repos, err := scrapePapersWithCode(server.Client(), server.URL, server.URL, fixtureNow)
+	if err != nil {
+		t.Fatalf("scrapePapersWithCode returned error: %v", err)
+	}
+
+	// 跳过fork和没有代码的论文；已知仓库只保留能从API获取星标数的
+	var names []string
+	for _, repo := range repos {
+		names = append(names, repo.Source+":"+repo.Name)
+	}
+	want := "Papers with Code:microsoft/LoRA,Papers with Code:CompVis/latent-diffusion,GitHub AI Papers:huggingface/diffusers,GitHub AI Papers:microsoft/LoRA"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("repositories = %s, want %s", got, want)
+	}
+
+	checkGolden(t, "paperswithcode.json", repos)
+}
@@ -0,0 +1,390 @@
+package scrapers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/health"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/readme"
+)
+
+// huggingFaceURL is the base URL of the Hugging Face Hub
+const huggingFaceURL = "https://huggingface.co"
+
+// defaultHubLimit is the number of trending items fetched per kind
+const defaultHubLimit = 20
+
+// hubCardMaxAge is how long the links extracted from a model card are reused
+const hubCardMaxAge = 7 * 24 * time.Hour
+
+// hubHistoryWindow is how long like and download history is kept
+const hubHistoryWindow = 30 * 24 * time.Hour
+
+// hubKinds maps each item kind to its API listing and page path prefix
+var hubKinds = []struct {
+	kind, api, prefix string
+}{
+	{models.HubModel, "/api/models", ""},
+	{models.HubDataset, "/api/datasets", "datasets/"},
+	{models.HubSpace, "/api/spaces", "spaces/"},
+}
+
+// hubPrefix returns the page path prefix of an item kind
+func hubPrefix(kind string) string {
+	for _, k := range hubKinds {
+		if k.kind == kind {
+			return k.prefix
+		}
+	}
+	return ""
+}
+
+// hubListItem is one entry of the Hub listing APIs
+type hubListItem struct {
+	ID            string   `json:"id"`
+	Author        string   `json:"author"`
+	Likes         int      `json:"likes"`
+	Downloads     int      `json:"downloads"`
+	TrendingScore float64  `json:"trendingScore"`
+	PipelineTag   string   `json:"pipeline_tag"`
+	Library       string   `json:"library_name"`
+	SDK           string   `json:"sdk"`
+	Tags          []string `json:"tags"`
+	CreatedAt     string   `json:"createdAt"`
+	Private       bool     `json:"private"`
+}
+
+// FetchHuggingFaceTrending fetches the trending models, datasets and Spaces
+// of the Hugging Face Hub. The number of items per kind is configured
+// through LLM_NEWS_HF_LIMIT. history may be nil. A *health.DegradedError is
+// returned when the listings lack fields they normally have.
+func FetchHuggingFaceTrending(history *HubHistory) ([]models.HubItem, error) {
+	limit := defaultHubLimit
+	if v, err := strconv.Atoi(os.Getenv("LLM_NEWS_HF_LIMIT")); err == nil && v > 0 {
+		limit = v
+	}
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+	}
+	return fetchHubTrending(client, huggingFaceURL, limit, history, time.Now())
+}
+
+func fetchHubTrending(client *http.Client, baseURL string, limit int, history *HubHistory, now time.Time) ([]models.HubItem, error) {
+	var items []models.HubItem
+	var errs []string
+	for _, k := range hubKinds {
+		kindItems, err := fetchHubItems(client, baseURL, k.kind, k.api, limit)
+		if err != nil {
+			log.Printf("Warning: Failed to fetch trending Hugging Face %ss: %v", k.kind, err)
+			errs = append(errs, fmt.Sprintf("%s: %v", k.kind, err))
+			continue
+		}
+		items = append(items, kindItems...)
+	}
+	if len(items) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("failed to fetch Hugging Face Hub: %s", strings.Join(errs, "; "))
+	}
+	if report := health.CheckHubItems(items); report.Degraded() {
+		return nil, &health.DegradedError{Report: report}
+	}
+
+	// 从模型卡片中提取GitHub仓库和论文链接，已缓存的卡片不重复下载
+	for i := range items {
+		item := &items[i]
+		if history != nil {
+			if card, ok := history.card(item.Kind, item.ID, now); ok {
+				applyHubCard(item, card)
+				continue
+			}
+		}
+		card, ok := fetchHubCard(client, baseURL, item, now)
+		applyHubCard(item, card)
+		if history != nil && ok {
+			history.setCard(item.Kind, item.ID, card)
+		}
+	}
+
+	if history != nil {
+		history.Record(items, now)
+	}
+	return items, nil
+}
+
+// fetchHubItems fetches one listing sorted by trending score
+func fetchHubItems(client *http.Client, baseURL, kind, api string, limit int) ([]models.HubItem, error) {
+	listURL := fmt.Sprintf("%s%s?sort=trendingScore&direction=-1&limit=%d", baseURL, api, limit)
+	body, err := getHub(client, listURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []hubListItem
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse listing: %w", err)
+	}
+	return hubListToItems(kind, list), nil
+}
+
+// hubListToItems converts listing entries to hub items, reading arXiv
+// references from the "arxiv:" tags the Hub derives from the model card
+func hubListToItems(kind string, list []hubListItem) []models.HubItem {
+	var items []models.HubItem
+	for _, entry := range list {
+		if entry.ID == "" || entry.Private {
+			continue
+		}
+		item := models.HubItem{
+			ID:            entry.ID,
+			Kind:          kind,
+			URL:           huggingFaceURL + "/" + hubPrefix(kind) + entry.ID,
+			Author:        entry.Author,
+			Likes:         entry.Likes,
+			Downloads:     entry.Downloads,
+			TrendingScore: entry.TrendingScore,
+			PipelineTag:   entry.PipelineTag,
+			Library:       entry.Library,
+			SDK:           entry.SDK,
+		}
+		if item.Author == "" {
+			item.Author, _, _ = strings.Cut(entry.ID, "/")
+		}
+		if t, err := time.Parse(time.RFC3339, entry.CreatedAt); err == nil {
+			item.CreatedAt = t
+		}
+		for _, tag := range entry.Tags {
+			if id, ok := strings.CutPrefix(tag, "arxiv:"); ok {
+				item.PaperURLs = append(item.PaperURLs, "https://arxiv.org/abs/"+id)
+				continue
+			}
+			item.Tags = append(item.Tags, tag)
+		}
+		// Hub的下载量是近30天的累计值
+		item.DownloadsPerDay = float64(item.Downloads) / 30
+		items = append(items, item)
+	}
+	return items
+}
+
+// hubCard holds the links extracted from a model card
+type hubCard struct {
+	GitHubURL string    `json:"github_url,omitempty"`
+	PaperURLs []string  `json:"paper_urls,omitempty"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// fetchHubCard downloads the README (model card) of an item. An item without
+// a README gets an empty card. ok is false when the download failed for
+// another reason, such as rate limiting: the empty card must not be cached,
+// so that it is retried on the next refresh.
+func fetchHubCard(client *http.Client, baseURL string, item *models.HubItem, now time.Time) (card hubCard, ok bool) {
+	card = hubCard{FetchedAt: now}
+	body, err := getHub(client, fmt.Sprintf("%s/%s%s/raw/main/README.md", baseURL, hubPrefix(item.Kind), item.ID))
+	if errors.Is(err, errHubNotFound) {
+		return card, true
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to fetch the model card of %s: %v", item.ID, err)
+		return card, false
+	}
+	content := string(body)
+	if repos := readme.GitHubRepos(content); len(repos) > 0 {
+		card.GitHubURL = repos[0]
+	}
+	card.PaperURLs = readme.PaperLinks(content)
+	return card, true
+}
+
+func applyHubCard(item *models.HubItem, card hubCard) {
+	if card.GitHubURL != "" {
+		item.GitHubURL = card.GitHubURL
+	}
+	item.PaperURLs = mergeLinks(item.PaperURLs, card.PaperURLs)
+}
+
+func mergeLinks(a, b []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, link := range append(append([]string{}, a...), b...) {
+		if !seen[link] {
+			seen[link] = true
+			result = append(result, link)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// errHubNotFound is returned by getHub for a 404, e.g. a repo without README
+var errHubNotFound = errors.New("not found")
+
+func getHub(client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "LLM-News-Agent")
+	if token := os.Getenv("HF_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, errHubNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxReadmeSize))
+}
+
+// HubPoint is one observation of the like and download counts of an item
+type HubPoint struct {
+	At        time.Time `json:"at"`
+	Likes     int       `json:"likes"`
+	Downloads int       `json:"downloads"`
+}
+
+// hubEntry is the history of one item
+type hubEntry struct {
+	Points []HubPoint `json:"points"`
+	Card   *hubCard   `json:"card,omitempty"`
+}
+
+// HubHistory keeps like and download observations of Hub items to compute
+// their velocity, and caches the links extracted from model cards. It is
+// persisted as a JSON file.
+type HubHistory struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]*hubEntry // kind/id -> 历史
+}
+
+// OpenHubHistory loads the history stored at path. A missing file yields an
+// empty history.
+func OpenHubHistory(path string) (*HubHistory, error) {
+	h := &HubHistory{path: path, entries: make(map[string]*hubEntry)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return h, nil
+		}
+		return nil, fmt.Errorf("failed to read hub history: %w", err)
+	}
+	if err := json.Unmarshal(data, &h.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse hub history: %w", err)
+	}
+	return h, nil
+}
+
+func hubKey(kind, id string) string {
+	return kind + "/" + id
+}
+
+func (h *HubHistory) card(kind, id string, now time.Time) (hubCard, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entry, ok := h.entries[hubKey(kind, id)]
+	if !ok || entry.Card == nil || now.Sub(entry.Card.FetchedAt) > hubCardMaxAge {
+		return hubCard{}, false
+	}
+	return *entry.Card, true
+}
+
+func (h *HubHistory) setCard(kind, id string, card hubCard) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entry := h.entry(hubKey(kind, id))
+	entry.Card = &card
+}
+
+func (h *HubHistory) entry(key string) *hubEntry {
+	entry, ok := h.entries[key]
+	if !ok {
+		entry = &hubEntry{}
+		h.entries[key] = entry
+	}
+	return entry
+}
+
+// Points returns the observations of an item in chronological order
+func (h *HubHistory) Points(kind, id string) []HubPoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entry, ok := h.entries[hubKey(kind, id)]
+	if !ok {
+		return nil
+	}
+	return append([]HubPoint(nil), entry.Points...)
+}
+
+// Record adds an observation for every item, fills LikesPerDay from the
+// oldest observation in the window, drops stale history and saves the file
+func (h *HubHistory) Record(items []models.HubItem, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := range items {
+		item := &items[i]
+		entry := h.entry(hubKey(item.Kind, item.ID))
+		entry.Points = append(entry.Points, HubPoint{At: now, Likes: item.Likes, Downloads: item.Downloads})
+
+		// 丢弃窗口之外的观测
+		start := 0
+		for start < len(entry.Points)-1 && now.Sub(entry.Points[start].At) > hubHistoryWindow {
+			start++
+		}
+		entry.Points = entry.Points[start:]
+
+		// 至少间隔一小时才计算点赞速度，避免放大短时间波动
+		oldest := entry.Points[0]
+		if days := now.Sub(oldest.At).Hours() / 24; days >= 1.0/24 {
+			item.LikesPerDay = float64(item.Likes-oldest.Likes) / days
+		}
+	}
+
+	// 长时间未出现在榜单上的条目不再保留
+	for key, entry := range h.entries {
+		if len(entry.Points) == 0 || now.Sub(entry.Points[len(entry.Points)-1].At) > hubHistoryWindow {
+			delete(h.entries, key)
+		}
+	}
+
+	if err := h.save(); err != nil {
+		log.Printf("Warning: Failed to save hub history: %v", err)
+	}
+}
+
+func (h *HubHistory) save() error {
+	if h.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(h.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return err
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.path)
+}
@@ -0,0 +1,225 @@
+package scrapers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+func readHubFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "hf", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// newHubServer serves the recorded Hub listings and model cards, counting
+// model card requests
+func newHubServer(t *testing.T, cardRequests *int32) *httptest.Server {
+	fixtures := map[string]string{
+		"/api/models":   "models.json",
+		"/api/datasets": "datasets.json",
+		"/api/spaces":   "spaces.json",
+		"/deepseek-ai/DeepSeek-R1/raw/main/README.md":           "cards/deepseek-ai__DeepSeek-R1.md",
+		"/datasets/open-r1/OpenR1-Math-220k/raw/main/README.md": "cards/open-r1__OpenR1-Math-220k.md",
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/README.md") {
+			atomic.AddInt32(cardRequests, 1)
+		}
+		name, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("sort") == "" && strings.HasPrefix(r.URL.Path, "/api/") {
+			t.Errorf("listing %s requested without sort", r.URL.Path)
+		}
+		w.Write(readHubFixture(t, name))
+	}))
+}
+
+func TestFetchHubTrending(t *testing.T) {
+	var cardRequests int32
+	server := newHubServer(t, &cardRequests)
+	defer server.Close()
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	items, err := fetchHubTrending(server.Client(), server.URL, 10, nil, now)
+	if err != nil {
+		t.Fatalf("fetchHubTrending returned error: %v", err)
+	}
+
+	// 私有模型应被跳过
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.Kind+":"+item.ID)
+	}
+	wantIDs := []string{
+		"model:deepseek-ai/DeepSeek-R1",
+		"model:Qwen/Qwen2.5-VL-7B-Instruct",
+		"dataset:open-r1/OpenR1-Math-220k",
+		"space:black-forest-labs/FLUX.1-dev",
+	}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Fatalf("items = %q, want %q", ids, wantIDs)
+	}
+
+	r1 := items[0]
+	if r1.Author != "deepseek-ai" || r1.URL != "https://huggingface.co/deepseek-ai/DeepSeek-R1" {
+		t.Errorf("unexpected author or URL: %q, %q", r1.Author, r1.URL)
+	}
+	if r1.PipelineTag != "text-generation" || r1.Library != "transformers" || r1.Likes != 11873 {
+		t.Errorf("unexpected metadata: %+v", r1)
+	}
+	if r1.GitHubURL != "https://github.com/deepseek-ai/DeepSeek-R1" {
+		t.Errorf("GitHubURL = %q", r1.GitHubURL)
+	}
+	// 标签和模型卡片中的同一篇论文只保留一次
+	if want := []string{"https://arxiv.org/abs/2501.12948"}; !reflect.DeepEqual(r1.PaperURLs, want) {
+		t.Errorf("PaperURLs = %q, want %q", r1.PaperURLs, want)
+	}
+	for _, tag := range r1.Tags {
+		if strings.HasPrefix(tag, "arxiv:") {
+			t.Errorf("arxiv tag %q should be moved to PaperURLs", tag)
+		}
+	}
+	if r1.CreatedAt.IsZero() || r1.DownloadsPerDay <= 0 {
+		t.Errorf("missing creation date or download rate: %+v", r1)
+	}
+
+	qwen := items[1]
+	if len(qwen.PaperURLs) != 2 || qwen.GitHubURL != "" {
+		t.Errorf("unexpected Qwen links: %q, %q", qwen.PaperURLs, qwen.GitHubURL)
+	}
+
+	dataset := items[2]
+	if dataset.URL != "https://huggingface.co/datasets/open-r1/OpenR1-Math-220k" {
+		t.Errorf("dataset URL = %q", dataset.URL)
+	}
+	if dataset.GitHubURL != "https://github.com/huggingface/open-r1" {
+		t.Errorf("dataset GitHubURL = %q", dataset.GitHubURL)
+	}
+	if want := []string{"https://arxiv.org/abs/2501.12948"}; !reflect.DeepEqual(dataset.PaperURLs, want) {
+		t.Errorf("dataset PaperURLs = %q, want %q", dataset.PaperURLs, want)
+	}
+
+	space := items[3]
+	if space.SDK != "gradio" || space.URL != "https://huggingface.co/spaces/black-forest-labs/FLUX.1-dev" {
+		t.Errorf("unexpected Space: %+v", space)
+	}
+
+	if n := atomic.LoadInt32(&cardRequests); n != 4 {
+		t.Errorf("expected one card request per item, got %d", n)
+	}
+}
+
+func TestHubHistoryVelocityAndCardCache(t *testing.T) {
+	var cardRequests int32
+	server := newHubServer(t, &cardRequests)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "hf_history.json")
+	history, err := OpenHubHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := fetchHubTrending(server.Client(), server.URL, 10, history, start); err != nil {
+		t.Fatal(err)
+	}
+
+	// 两天后点赞数增加100，重新加载历史后计算速度
+	reloaded, err := OpenHubHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []models.HubItem{{ID: "deepseek-ai/DeepSeek-R1", Kind: models.HubModel, Likes: 11973}}
+	reloaded.Record(items, start.Add(48*time.Hour))
+	if items[0].LikesPerDay != 50 {
+		t.Errorf("LikesPerDay = %v, want 50", items[0].LikesPerDay)
+	}
+	if points := reloaded.Points(models.HubModel, "deepseek-ai/DeepSeek-R1"); len(points) != 2 {
+		t.Errorf("expected 2 observations, got %d", len(points))
+	}
+
+	// 缓存的模型卡片在有效期内不再下载
+	before := atomic.LoadInt32(&cardRequests)
+	if _, err := fetchHubTrending(server.Client(), server.URL, 10, reloaded, start.Add(72*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&cardRequests); n != before {
+		t.Errorf("expected cached cards, got %d new requests", n-before)
+	}
+
+	// 超过30天未出现的条目会被清理
+	reloaded.Record(nil, start.Add(40*24*time.Hour))
+	if points := reloaded.Points(models.HubSpace, "black-forest-labs/FLUX.1-dev"); points != nil {
+		t.Errorf("stale history should be dropped, got %d points", len(points))
+	}
+}
+
+// TestHubCardRetry checks that a model card whose download failed is fetched
+// again on the next refresh, while a missing README stays cached
+func TestHubCardRetry(t *testing.T) {
+	var cardRequests, failures int32
+	hub := newHubServer(t, &cardRequests)
+	defer hub.Close()
+	// 第一次请求DeepSeek-R1的模型卡片时被限流
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/deepseek-ai/DeepSeek-R1/raw/main/README.md" && atomic.AddInt32(&failures, 1) == 1 {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+			return
+		}
+		hub.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	history, err := OpenHubHistory(filepath.Join(t.TempDir(), "hf_history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	items, err := fetchHubTrending(server.Client(), server.URL, 10, history, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items[0].GitHubURL != "" {
+		t.Fatalf("GitHubURL = %q despite the failed download", items[0].GitHubURL)
+	}
+
+	// 只重新请求失败的卡片，404的卡片已缓存
+	before := atomic.LoadInt32(&cardRequests)
+	items, err = fetchHubTrending(server.Client(), server.URL, 10, history, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&cardRequests) - before; n != 1 {
+		t.Errorf("got %d card requests on the second refresh, want 1", n)
+	}
+	if items[0].GitHubURL != "https://github.com/deepseek-ai/DeepSeek-R1" {
+		t.Errorf("GitHubURL = %q after the retry", items[0].GitHubURL)
+	}
+}
+
+func TestFetchHubTrendingErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	if _, err := fetchHubTrending(server.Client(), server.URL, 10, nil, time.Now()); err == nil {
+		t.Error("expected an error when every listing fails")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"regexp"
 	"strings"
@@ -24,40 +25,50 @@ type PapersWithCodeRepository struct {
 	PaperTitle  string   `json:"paper_title"`
 }
 
+// Papers with Code API endpoint, replaced by an httptest server in tests
+const papersWithCodeURL = "https://paperswithcode.com"
+
+// papersWithCodePath lists the papers of broad topics
+const papersWithCodePath = "/api/v1/papers/?topics=language-modelling,transformer,nlp,llm,gpt,diffusion-models,computer-vision,retrieval,optimization&limit=50&page=1"
+
 // ScrapePapersWithCode scrapes the Papers with Code trending repositories
 func ScrapePapersWithCode() ([]models.Repository, error) {
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+	}
+	return scrapePapersWithCode(client, papersWithCodeURL, githubAPIURL, time.Now())
+}
+
+// scrapePapersWithCode lists the papers at baseURL and the known paper
+// implementations, enriching both through the GitHub API at apiURL
+func scrapePapersWithCode(client *http.Client, baseURL, apiURL string, now time.Time) ([]models.Repository, error) {
 	// 存储所有获取的论文仓库
 	allRepos := []models.Repository{}
 
 	// 尝试从Papers with Code获取数据
-	papersWithCodeRepos, err := scrapePapersWithCodeAPI()
+	papersWithCodeRepos, err := scrapePapersWithCodeAPI(client, baseURL, apiURL, now)
 	if err != nil {
-		fmt.Printf("Warning: Failed to fetch from Papers with Code API: %v\n", err)
+		log.Printf("Warning: Failed to fetch from Papers with Code API: %v", err)
 	} else {
 		allRepos = append(allRepos, papersWithCodeRepos...)
 	}
 
 	// 尝试从GitHub专题列表获取AI论文实现
-	githubAIPapersRepos, err := scrapeGitHubAIPapers()
+	githubAIPapersRepos, err := scrapeGitHubAIPapers(client, apiURL, now)
 	if err != nil {
-		fmt.Printf("Warning: Failed to fetch from GitHub AI Papers: %v\n", err)
+		log.Printf("Warning: Failed to fetch from GitHub AI Papers: %v", err)
 	} else {
 		allRepos = append(allRepos, githubAIPapersRepos...)
 	}
 
+	models.AssignRepoIDs(allRepos)
 	return allRepos, nil
 }
 
 // scrapePapersWithCodeAPI 从Papers with Code API获取数据
-func scrapePapersWithCodeAPI() ([]models.Repository, error) {
-	// Papers with Code API endpoint
+func scrapePapersWithCodeAPI(client *http.Client, baseURL, apiURL string, now time.Time) ([]models.Repository, error) {
 	// 使用较广泛的主题并增加结果数
-	url := "https://paperswithcode.com/api/v1/papers/?topics=language-modelling,transformer,nlp,llm,gpt,diffusion-models,computer-vision,retrieval,optimization&limit=50&page=1"
-
-	// Make HTTP request
-	client := &http.Client{
-		Timeout: 15 * time.Second,
-	}
+	url := baseURL + papersWithCodePath
 
 	// 添加用户代理以避免被阻止
 	req, err := http.NewRequest("GET", url, nil)
@@ -171,7 +182,7 @@ func scrapePapersWithCodeAPI() ([]models.Repository, error) {
 				Language:    repo.Framework,
 				Stars:       repo.Stars,
 				GainedStars: 0, // We don't know the daily gain from the API
-				LastUpdated: time.Now(),
+				LastUpdated: now,
 				LastCommit:  paper.PublishedAt, // Using paper publish date as a proxy
 				TechStack:   []string{repo.Framework},
 				TrendMetrics: models.TrendMetrics{
@@ -186,7 +197,7 @@ func scrapePapersWithCodeAPI() ([]models.Repository, error) {
 			}
 
 			// Try to fetch additional repository details from GitHub
-			enrichRepositoryWithGitHubDetails(&repository)
+			enrichRepositoryWithGitHubDetails(client, apiURL, &repository)
 
 			repos = append(repos, repository)
 		}
@@ -196,7 +207,7 @@ func scrapePapersWithCodeAPI() ([]models.Repository, error) {
 }
 
 // scrapeGitHubAIPapers 从GitHub获取AI论文实现
-func scrapeGitHubAIPapers() ([]models.Repository, error) {
+func scrapeGitHubAIPapers(client *http.Client, apiURL string, now time.Time) ([]models.Repository, error) {
 	// 定义一些知名的AI论文实现仓库
 	knownRepos := []struct {
 		Owner       string
@@ -290,7 +301,7 @@ func scrapeGitHubAIPapers() ([]models.Repository, error) {
 			Description:    knownRepo.Description,
 			Language:       "unknown", // 将通过enrichRepositoryWithGitHubDetails更新
 			Stars:          0,         // 将通过enrichRepositoryWithGitHubDetails更新
-			LastUpdated:    time.Now(),
+			LastUpdated:    now,
 			TechStack:      []string{"research", "ai", "paper"},
 			RelevanceScore: 0.9,
 			HasDocs:        true,
@@ -300,7 +311,7 @@ func scrapeGitHubAIPapers() ([]models.Repository, error) {
 		}
 
 		// 获取GitHub仓库详细信息
-		enrichRepositoryWithGitHubDetails(&repository)
+		enrichRepositoryWithGitHubDetails(client, apiURL, &repository)
 
 		if repository.Stars > 0 {
 			repos = append(repos, repository)
@@ -311,7 +322,7 @@ func scrapeGitHubAIPapers() ([]models.Repository, error) {
 }
 
 // enrichRepositoryWithGitHubDetails fetches additional details from GitHub
-func enrichRepositoryWithGitHubDetails(repo *models.Repository) {
+func enrichRepositoryWithGitHubDetails(client *http.Client, apiURL string, repo *models.Repository) {
 	// Extract owner and repo name
 	parts := strings.Split(repo.Name, "/")
 	if len(parts) != 2 {
@@ -322,12 +333,7 @@ func enrichRepositoryWithGitHubDetails(repo *models.Repository) {
 	repoName := parts[1]
 
 	// GitHub API URL
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repoName)
-
-	// Make HTTP request
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	url := fmt.Sprintf("%s/repos/%s/%s", apiURL, owner, repoName)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -404,21 +410,8 @@ func enrichRepositoryWithGitHubDetails(repo *models.Repository) {
 		repo.DocsURL = fmt.Sprintf("https://github.com/%s/wiki", repo.Name)
 	}
 
-	// Check if README exists
-	readmeURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/readme", owner, repoName)
-	readmeReq, err := http.NewRequest("GET", readmeURL, nil)
-	if err == nil {
-		readmeReq.Header.Add("User-Agent", "LLM-News-Agent")
-		readmeResp, err := client.Do(readmeReq)
-		if err == nil && readmeResp.StatusCode == http.StatusOK {
-			repo.HasDocs = true
-			repo.HasReadme = true
-			if repo.DocsURL == "" {
-				repo.DocsURL = fmt.Sprintf("https://github.com/%s#readme", repo.Name)
-			}
-			readmeResp.Body.Close()
-		}
-	}
+	// Fetch and analyze the README
+	applyReadme(client, apiURL, repo, owner, repoName)
 
 	// 计算并获取模型分类
 	repo.GetModelCategories()
@@ -0,0 +1,63 @@
+package scrapers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/readme"
+)
+
+// maxReadmeSize bounds the README bytes read for analysis
+const maxReadmeSize = 512 * 1024
+
+// fetchReadme downloads the raw README of a repository. ok is false when the
+// repository has no README or the request fails.
+func fetchReadme(client *http.Client, apiURL, owner, repoName string) (content string, ok bool) {
+	readmeURL := fmt.Sprintf("%s/repos/%s/%s/readme", apiURL, owner, repoName)
+	req, err := http.NewRequest("GET", readmeURL, nil)
+	if err != nil {
+		return "", false
+	}
+	req.Header.Add("User-Agent", "LLM-News-Agent")
+	// 直接获取README原文，而不是base64编码的JSON
+	req.Header.Add("Accept", "application/vnd.github.raw")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxReadmeSize))
+	if err != nil {
+		// 读取失败时仍可确认README存在
+		return "", true
+	}
+	return string(body), true
+}
+
+// applyReadme fetches and analyzes the README of repo, recording its
+// documentation quality and extracted links
+func applyReadme(client *http.Client, apiURL string, repo *models.Repository, owner, repoName string) {
+	content, ok := fetchReadme(client, apiURL, owner, repoName)
+	if !ok {
+		return
+	}
+
+	repo.HasDocs = true
+	repo.HasReadme = true
+	if repo.DocsURL == "" {
+		repo.DocsURL = fmt.Sprintf("https://github.com/%s#readme", repo.Name)
+	}
+
+	if content != "" {
+		quality := readme.Analyze(content)
+		repo.DocQuality = &quality
+	}
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Retention controls how long snapshots are kept. Snapshots of the last
+// HourlyDays days are all kept; older days are collapsed to their last
+// snapshot. With MaxDays > 0 snapshots older than that are removed.
+type Retention struct {
+	HourlyDays int
+	MaxDays    int // 0表示永久保留每日快照
+}
+
+// DefaultRetention keeps a week of hourly snapshots and daily ones forever
+var DefaultRetention = Retention{HourlyDays: 7}
+
+// RetentionFromEnv reads LLM_NEWS_SNAPSHOT_HOURLY_DAYS and
+// LLM_NEWS_SNAPSHOT_MAX_DAYS, falling back to DefaultRetention
+func RetentionFromEnv() Retention {
+	r := DefaultRetention
+	if v, err := strconv.Atoi(os.Getenv("LLM_NEWS_SNAPSHOT_HOURLY_DAYS")); err == nil && v > 0 {
+		r.HourlyDays = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("LLM_NEWS_SNAPSHOT_MAX_DAYS")); err == nil && v >= 0 {
+		r.MaxDays = v
+	}
+	return r
+}
+
+// day returns the UTC day t falls on
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// Compact applies the retention rules as of now and deletes the snapshot
+// files that are no longer needed. It returns the number of removed
+// snapshots. Days are UTC days; a day is only collapsed once it lies
+// completely before the hourly window.
+func (s *Store) Compact(r Retention, now time.Time) (int, error) {
+	hourlyFrom := day(now).AddDate(0, 0, -r.HourlyDays)
+	var oldest time.Time
+	if r.MaxDays > 0 {
+		oldest = day(now).AddDate(0, 0, -r.MaxDays)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var kept []Snapshot
+	var firstErr error
+	removed := 0
+	for i, snap := range s.snapshots {
+		drop := snap.TakenAt.Before(oldest)
+		if !drop && snap.TakenAt.Before(hourlyFrom) {
+			// 同一天中只保留最后一个快照
+			drop = i+1 < len(s.snapshots) && day(s.snapshots[i+1].TakenAt).Equal(day(snap.TakenAt))
+		}
+		if !drop {
+			kept = append(kept, snap)
+			continue
+		}
+		if err := os.Remove(s.snapshotPath(snap.TakenAt)); err != nil && !os.IsNotExist(err) {
+			// 删除失败的快照保留在内存中，下次压缩时重试
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to remove snapshot: %w", err)
+			}
+			kept = append(kept, snap)
+			continue
+		}
+		removed++
+	}
+	s.snapshots = kept
+	return removed, firstErr
+}
+
+// OnDay returns the last snapshot taken on the UTC day of date
+func (s *Store) OnDay(date time.Time) (Snapshot, bool) {
+	start := day(date)
+	snap, ok := s.At(start.Add(24*time.Hour - time.Nanosecond))
+	if !ok || snap.TakenAt.Before(start) {
+		return Snapshot{}, false
+	}
+	return snap, true
+}
+
+// Days returns the UTC days with at least one snapshot, oldest first
+func (s *Store) Days() []time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var days []time.Time
+	for _, snap := range s.snapshots {
+		d := day(snap.TakenAt)
+		if len(days) == 0 || !days[len(days)-1].Equal(d) {
+			days = append(days, d)
+		}
+	}
+	return days
+}
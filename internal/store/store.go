@@ -0,0 +1,172 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// snapshotLayout is the timestamp layout used in snapshot file names
+const snapshotLayout = "20060102T150405Z"
+
+// Snapshot is the full state of the collected data at one point in time
+type Snapshot struct {
+	TakenAt time.Time           `json:"taken_at"`
+	Repos   []models.Repository `json:"repos"`
+	Papers  []models.Paper      `json:"papers"`
+}
+
+// Store keeps snapshots in memory and persists each of them as a JSON file
+// under <dir>/snapshots
+type Store struct {
+	dir       string
+	mu        sync.RWMutex
+	snapshots []Snapshot // 按时间升序排列
+}
+
+// DataDir returns the directory used for persisted data, configured through
+// LLM_NEWS_DATA_DIR and defaulting to ./data
+func DataDir() string {
+	if dir := strings.TrimSpace(os.Getenv("LLM_NEWS_DATA_DIR")); dir != "" {
+		return dir
+	}
+	return "data"
+}
+
+// Open creates the snapshot directory if needed and loads existing snapshots
+func Open(dir string) (*Store, error) {
+	s := &Store{dir: dir}
+	if err := os.MkdirAll(s.snapshotDir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(s.snapshotDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.snapshotDir(), entry.Name()))
+		if err != nil {
+			log.Printf("Warning: Failed to read snapshot %s: %v", entry.Name(), err)
+			continue
+		}
+		var snap Snapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			log.Printf("Warning: Failed to parse snapshot %s: %v", entry.Name(), err)
+			continue
+		}
+		s.snapshots = append(s.snapshots, snap)
+	}
+
+	sort.Slice(s.snapshots, func(i, j int) bool {
+		return s.snapshots[i].TakenAt.Before(s.snapshots[j].TakenAt)
+	})
+
+	return s, nil
+}
+
+// Dir returns the root data directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+func (s *Store) snapshotDir() string {
+	return filepath.Join(s.dir, "snapshots")
+}
+
+func (s *Store) snapshotPath(t time.Time) string {
+	return filepath.Join(s.snapshotDir(), t.UTC().Format(snapshotLayout)+".json")
+}
+
+// Save persists a snapshot and adds it to the in-memory history
+func (s *Store) Save(snap Snapshot) error {
+	if snap.TakenAt.IsZero() {
+		snap.TakenAt = time.Now()
+	}
+	snap.TakenAt = snap.TakenAt.UTC()
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	// 先写临时文件再重命名，避免进程中断时留下损坏的快照
+	path := s.snapshotPath(snap.TakenAt)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := sort.Search(len(s.snapshots), func(i int) bool {
+		return !s.snapshots[i].TakenAt.Before(snap.TakenAt)
+	})
+	if i < len(s.snapshots) && s.snapshots[i].TakenAt.Equal(snap.TakenAt) {
+		s.snapshots[i] = snap
+		return nil
+	}
+	s.snapshots = append(s.snapshots, Snapshot{})
+	copy(s.snapshots[i+1:], s.snapshots[i:])
+	s.snapshots[i] = snap
+	return nil
+}
+
+// Latest returns the most recent snapshot
+func (s *Store) Latest() (Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.snapshots) == 0 {
+		return Snapshot{}, false
+	}
+	return s.snapshots[len(s.snapshots)-1], true
+}
+
+// At returns the most recent snapshot taken at or before t
+func (s *Store) At(t time.Time) (Snapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := sort.Search(len(s.snapshots), func(i int) bool {
+		return s.snapshots[i].TakenAt.After(t)
+	})
+	if i == 0 {
+		return Snapshot{}, false
+	}
+	return s.snapshots[i-1], true
+}
+
+// Between returns the snapshots taken in [from, to], oldest first
+func (s *Store) Between(from, to time.Time) []Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []Snapshot
+	for _, snap := range s.snapshots {
+		if snap.TakenAt.Before(from) || snap.TakenAt.After(to) {
+			continue
+		}
+		result = append(result, snap)
+	}
+	return result
+}
+
+// Len returns the number of stored snapshots
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.snapshots)
+}
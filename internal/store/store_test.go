@@ -0,0 +1,96 @@
+package store
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func openWith(t *testing.T, times ...time.Time) *Store {
+	t.Helper()
+	st, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, at := range times {
+		if err := st.Save(Snapshot{TakenAt: at}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return st
+}
+
+func TestCompact(t *testing.T) {
+	now := time.Date(2026, 9, 20, 12, 0, 0, 0, time.UTC)
+	hour := func(days, h int) time.Time {
+		return time.Date(2026, 9, 20-days, h, 0, 0, 0, time.UTC)
+	}
+	st := openWith(t,
+		hour(40, 9),                             // 超过最大保留期
+		hour(10, 1), hour(10, 13), hour(10, 23), // 压缩为23点的快照
+		hour(8, 5),                          // 当天唯一的快照
+		hour(3, 1), hour(3, 2), hour(0, 11), // 仍在按小时保留的窗口内
+	)
+
+	removed, err := st.Compact(Retention{HourlyDays: 7, MaxDays: 30}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 3 {
+		t.Errorf("removed %d snapshots, want 3", removed)
+	}
+
+	want := []time.Time{hour(10, 23), hour(8, 5), hour(3, 1), hour(3, 2), hour(0, 11)}
+	got := st.Between(time.Time{}, now)
+	if len(got) != len(want) {
+		t.Fatalf("kept %d snapshots, want %d", len(got), len(want))
+	}
+	for i, snap := range got {
+		if !snap.TakenAt.Equal(want[i]) {
+			t.Errorf("snapshot %d taken at %v, want %v", i, snap.TakenAt, want[i])
+		}
+	}
+
+	// 被删除的快照文件在重新打开后不再出现
+	reopened, err := Open(st.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Len() != len(want) {
+		t.Errorf("reopened store has %d snapshots, want %d", reopened.Len(), len(want))
+	}
+	if _, err := os.Stat(st.snapshotPath(hour(10, 1))); !os.IsNotExist(err) {
+		t.Errorf("collapsed snapshot file still exists: %v", err)
+	}
+}
+
+func TestOnDay(t *testing.T) {
+	st := openWith(t,
+		time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC),
+		time.Date(2026, 9, 1, 20, 0, 0, 0, time.UTC),
+		time.Date(2026, 9, 3, 0, 0, 0, 0, time.UTC),
+	)
+
+	tests := []struct {
+		date string
+		want time.Time
+		ok   bool
+	}{
+		{"2026-09-01", time.Date(2026, 9, 1, 20, 0, 0, 0, time.UTC), true},
+		{"2026-09-02", time.Time{}, false},
+		{"2026-09-03", time.Date(2026, 9, 3, 0, 0, 0, 0, time.UTC), true},
+		{"2026-08-31", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		date, _ := time.Parse("2006-01-02", tt.date)
+		snap, ok := st.OnDay(date)
+		if ok != tt.ok || !snap.TakenAt.Equal(tt.want) {
+			t.Errorf("OnDay(%s) = %v, %v; want %v, %v", tt.date, snap.TakenAt, ok, tt.want, tt.ok)
+		}
+	}
+
+	days := st.Days()
+	if len(days) != 2 || days[0].Format("2006-01-02") != "2026-09-01" || days[1].Format("2006-01-02") != "2026-09-03" {
+		t.Errorf("Days() = %v", days)
+	}
+}
@@ -0,0 +1,82 @@
+package summarizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// maxCacheEntries bounds the size of the cache file; the cache is cleared
+// when it grows beyond this
+const maxCacheEntries = 20000
+
+// Cache maps content hashes to summaries and persists them as a JSON file
+type Cache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]Summary
+}
+
+// OpenCache loads the cache file at path, creating an empty cache if the
+// file does not exist yet
+func OpenCache(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: make(map[string]Summary)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summary cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse summary cache: %w", err)
+	}
+	return c, nil
+}
+
+// Get returns the summary stored under key
+func (c *Cache) Get(key string) (Summary, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	summary, ok := c.entries[key]
+	return summary, ok
+}
+
+// Put stores a summary under key
+func (c *Cache) Put(key string, summary Summary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCacheEntries {
+		c.entries = make(map[string]Summary)
+	}
+	c.entries[key] = summary
+}
+
+// Len returns the number of cached summaries
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Save writes the cache to disk
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	data, err := json.Marshal(c.entries)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write summary cache: %w", err)
+	}
+	return os.Rename(tmp, c.path)
+}
@@ -0,0 +1,290 @@
+// Package summarizer generates TL;DRs, contribution bullets and technique
+// tags for papers and repositories with an LLM behind an OpenAI-compatible
+// chat endpoint. Results are cached by content hash, and each refresh may
+// spend at most a fixed number of tokens.
+package summarizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gerryyang2025/llm-news/internal/llm"
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/store"
+)
+
+// promptVersion is part of the cache key; bump it when the prompt changes
+const promptVersion = "v1"
+
+// maxInputRunes bounds the text sent for one item
+const maxInputRunes = 4000
+
+// maxOutputTokens bounds the reply for one item
+const maxOutputTokens = 400
+
+// ErrBudgetExceeded is returned when a refresh has used up its token budget
+var ErrBudgetExceeded = errors.New("token budget exceeded")
+
+// Summary is the generated description of a paper or repository
+type Summary struct {
+	TLDR          string   `json:"tldr"`
+	Contributions []string `json:"contributions"`
+	Techniques    []string `json:"techniques"`
+}
+
+// Chatter is the part of llm.Client used by the summarizer
+type Chatter interface {
+	Chat(messages []llm.Message, maxTokens int, jsonMode bool) (llm.Response, error)
+	ModelName() string
+}
+
+// Summarizer generates and caches summaries
+type Summarizer struct {
+	client Chatter
+	cache  *Cache
+	budget int // 每次刷新可消耗的token数，0表示不限制
+}
+
+// New creates a summarizer. cache may be nil to disable caching.
+func New(client Chatter, cache *Cache, budget int) *Summarizer {
+	return &Summarizer{client: client, cache: cache, budget: budget}
+}
+
+var (
+	defaultOnce       sync.Once
+	defaultSummarizer *Summarizer
+)
+
+// Default returns the summarizer configured through the environment, built
+// once per process, or nil when summaries are disabled
+func Default() *Summarizer {
+	defaultOnce.Do(func() {
+		defaultSummarizer = FromEnv()
+		if defaultSummarizer != nil {
+			log.Printf("LLM summaries enabled with model %s (budget %d tokens per refresh)",
+				defaultSummarizer.client.ModelName(), defaultSummarizer.budget)
+		}
+	})
+	return defaultSummarizer
+}
+
+// FromEnv builds a summarizer when LLM_NEWS_SUMMARIZER is "on". The chat
+// endpoint comes from llm.ClientFromEnv, the per-refresh token budget from
+// LLM_NEWS_SUMMARIZER_TOKEN_BUDGET (default 50000) and the cache is kept in
+// <data dir>/summaries.json.
+func FromEnv() *Summarizer {
+	switch strings.ToLower(os.Getenv("LLM_NEWS_SUMMARIZER")) {
+	case "on", "true", "1":
+	default:
+		return nil
+	}
+
+	budget := 50000
+	if v, err := strconv.Atoi(os.Getenv("LLM_NEWS_SUMMARIZER_TOKEN_BUDGET")); err == nil {
+		budget = v
+	}
+
+	cache, err := OpenCache(filepath.Join(store.DataDir(), "summaries.json"))
+	if err != nil {
+		log.Printf("Warning: Failed to open summary cache, summaries will not be cached: %v", err)
+		cache = nil
+	}
+	return New(llm.ClientFromEnv(), cache, budget)
+}
+
+// run tracks the tokens spent during one refresh
+type run struct {
+	s       *Summarizer
+	spent   int
+	skipped int // 因预算不足跳过的条目数
+}
+
+// SummarizePapers fills TLDR, CoreContributions and KeyTechniques of the
+// papers. Papers that fail or do not fit in the budget keep their
+// heuristic values; cached summaries are applied regardless of the budget.
+func (s *Summarizer) SummarizePapers(papers []models.Paper) {
+	r := &run{s: s}
+	generated := 0
+	for i := range papers {
+		p := &papers[i]
+		if strings.TrimSpace(p.Summary) == "" {
+			continue
+		}
+		summary, fresh, err := r.summarize("paper", p.Title, p.Summary)
+		if err != nil {
+			if errors.Is(err, ErrBudgetExceeded) {
+				r.skipped++ // 预算用尽后仍继续使用缓存
+				continue
+			}
+			log.Printf("Warning: Failed to summarize paper %s: %v", p.Title, err)
+			continue
+		}
+		if fresh {
+			generated++
+		}
+		p.TLDR = summary.TLDR
+		if len(summary.Contributions) > 0 {
+			p.CoreContributions = summary.Contributions
+		}
+		if len(summary.Techniques) > 0 {
+			p.KeyTechniques = summary.Techniques
+		}
+	}
+	r.finish("papers", generated)
+}
+
+// SummarizeRepos fills TLDR, Highlights and KeyTechniques of the repositories
+func (s *Summarizer) SummarizeRepos(repos []models.Repository) {
+	r := &run{s: s}
+	generated := 0
+	for i := range repos {
+		repo := &repos[i]
+		if strings.TrimSpace(repo.Description) == "" {
+			continue
+		}
+		text := repo.Description
+		if len(repo.TechStack) > 0 {
+			text += "\nTopics: " + strings.Join(repo.TechStack, ", ")
+		}
+		summary, fresh, err := r.summarize("repository", repo.Name, text)
+		if err != nil {
+			if errors.Is(err, ErrBudgetExceeded) {
+				r.skipped++ // 预算用尽后仍继续使用缓存
+				continue
+			}
+			log.Printf("Warning: Failed to summarize repository %s: %v", repo.Name, err)
+			continue
+		}
+		if fresh {
+			generated++
+		}
+		repo.TLDR = summary.TLDR
+		repo.Highlights = summary.Contributions
+		repo.KeyTechniques = summary.Techniques
+	}
+	r.finish("repositories", generated)
+}
+
+func (r *run) finish(kind string, generated int) {
+	if r.s.cache != nil && generated > 0 {
+		if err := r.s.cache.Save(); err != nil {
+			log.Printf("Warning: Failed to save summary cache: %v", err)
+		}
+	}
+	if generated > 0 {
+		log.Printf("Generated %d %s summaries using about %d tokens", generated, kind, r.spent)
+	}
+	if r.skipped > 0 {
+		log.Printf("Warning: Token budget of %d exhausted, %d %s were not summarized", r.s.budget, r.skipped, kind)
+	}
+}
+
+// summarize returns the cached summary of an item or generates it. fresh
+// reports whether the LLM was called.
+func (r *run) summarize(kind, title, text string) (Summary, bool, error) {
+	text = truncateRunes(strings.TrimSpace(text), maxInputRunes)
+
+	key := cacheKey(r.s.client.ModelName(), kind, title, text)
+	if r.s.cache != nil {
+		if summary, ok := r.s.cache.Get(key); ok {
+			return summary, false, nil
+		}
+	}
+
+	messages := buildPrompt(kind, title, text)
+	estimate := maxOutputTokens
+	for _, m := range messages {
+		estimate += llm.EstimateTokens(m.Content)
+	}
+	if r.s.budget > 0 && r.spent+estimate > r.s.budget {
+		return Summary{}, false, ErrBudgetExceeded
+	}
+
+	resp, err := r.s.client.Chat(messages, maxOutputTokens, true)
+	if resp.TotalTokens > 0 {
+		r.spent += resp.TotalTokens
+	} else {
+		r.spent += estimate
+	}
+	if err != nil {
+		return Summary{}, false, err
+	}
+
+	summary, err := parseSummary(resp.Content)
+	if err != nil {
+		return Summary{}, false, err
+	}
+	if r.s.cache != nil {
+		r.s.cache.Put(key, summary)
+	}
+	return summary, true, nil
+}
+
+func buildPrompt(kind, title, text string) []llm.Message {
+	system := "You summarize AI research papers and open-source repositories for a news site. " +
+		"Reply with a JSON object only, using the keys:\n" +
+		`"tldr": one sentence of at most 30 words saying what the ` + kind + " is and why it matters;\n" +
+		`"contributions": 2 to 4 short bullet points with the main contributions or features;` + "\n" +
+		`"techniques": 2 to 5 short lowercase tags naming the techniques used, e.g. "mixture of experts".` + "\n" +
+		"Only use facts stated in the text. Write in the language of the text."
+	user := fmt.Sprintf("Type: %s\nTitle: %s\n\n%s", kind, title, text)
+	return []llm.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: user},
+	}
+}
+
+func parseSummary(content string) (Summary, error) {
+	raw, err := llm.ExtractJSON(content)
+	if err != nil {
+		return Summary{}, err
+	}
+	var summary Summary
+	if err := json.Unmarshal([]byte(raw), &summary); err != nil {
+		return Summary{}, fmt.Errorf("failed to parse summary: %w", err)
+	}
+
+	summary.TLDR = strings.TrimSpace(summary.TLDR)
+	if summary.TLDR == "" {
+		return Summary{}, fmt.Errorf("summary has no tldr")
+	}
+	summary.Contributions = cleanList(summary.Contributions, 4)
+	summary.Techniques = cleanList(summary.Techniques, 5)
+	return summary, nil
+}
+
+func cleanList(items []string, max int) []string {
+	var result []string
+	for _, item := range items {
+		item = strings.TrimSpace(strings.TrimLeft(item, "-*• "))
+		if item != "" {
+			result = append(result, item)
+		}
+		if len(result) == max {
+			break
+		}
+	}
+	return result
+}
+
+func cacheKey(model, kind, title, text string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{promptVersion, model, kind, title, text}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,107 @@
+package summarizer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gerryyang2025/llm-news/internal/llm"
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// stubChat stands in for an OpenAI-compatible endpoint
+type stubChat struct {
+	calls  int
+	tokens int
+	reply  func(messages []llm.Message) string
+}
+
+func (s *stubChat) ModelName() string { return "stub" }
+
+func (s *stubChat) Chat(messages []llm.Message, maxTokens int, jsonMode bool) (llm.Response, error) {
+	s.calls++
+	return llm.Response{Content: s.reply(messages), TotalTokens: s.tokens}, nil
+}
+
+func titleReply(messages []llm.Message) string {
+	user := messages[len(messages)-1].Content
+	title := strings.TrimPrefix(strings.Split(user, "\n")[1], "Title: ")
+	return fmt.Sprintf("```json\n{\"tldr\": \"About %s.\", \"contributions\": [\"- first\", \"second\"], \"techniques\": [\"rag\"]}\n```", title)
+}
+
+func TestSummarizePapersUsesCache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "summaries.json")
+	cache, err := OpenCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat := &stubChat{reply: titleReply, tokens: 100}
+	s := New(chat, cache, 0)
+
+	papers := []models.Paper{
+		{Title: "Paper A", Summary: "Abstract A."},
+		{Title: "Paper B", Summary: "Abstract B."},
+		{Title: "No abstract"},
+	}
+	s.SummarizePapers(papers)
+
+	if chat.calls != 2 {
+		t.Fatalf("expected 2 chat calls, got %d", chat.calls)
+	}
+	if papers[0].TLDR != "About Paper A." {
+		t.Errorf("unexpected TLDR %q", papers[0].TLDR)
+	}
+	if got := papers[1].CoreContributions; len(got) != 2 || got[0] != "first" {
+		t.Errorf("unexpected contributions %q", got)
+	}
+	if papers[2].TLDR != "" {
+		t.Errorf("paper without abstract should not be summarized")
+	}
+
+	// 重新加载缓存后，相同内容不再调用LLM
+	reloaded, err := OpenCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat2 := &stubChat{reply: titleReply}
+	again := []models.Paper{{Title: "Paper A", Summary: "Abstract A."}}
+	New(chat2, reloaded, 0).SummarizePapers(again)
+	if chat2.calls != 0 {
+		t.Errorf("expected cached summary, got %d chat calls", chat2.calls)
+	}
+	if again[0].TLDR != "About Paper A." {
+		t.Errorf("unexpected cached TLDR %q", again[0].TLDR)
+	}
+}
+
+func TestSummarizeReposRespectsBudget(t *testing.T) {
+	chat := &stubChat{reply: titleReply, tokens: 600}
+	s := New(chat, nil, 1000)
+
+	repos := []models.Repository{
+		{Name: "a/one", Description: "First repository"},
+		{Name: "b/two", Description: "Second repository"},
+		{Name: "c/three", Description: "Third repository"},
+	}
+	s.SummarizeRepos(repos)
+
+	// 第一次调用消耗600，剩余预算不足以再调用一次
+	if chat.calls != 1 {
+		t.Fatalf("expected 1 chat call within budget, got %d", chat.calls)
+	}
+	if repos[0].TLDR == "" || repos[1].TLDR != "" {
+		t.Errorf("unexpected TLDRs %q, %q", repos[0].TLDR, repos[1].TLDR)
+	}
+	if len(repos[0].KeyTechniques) != 1 || repos[0].KeyTechniques[0] != "rag" {
+		t.Errorf("unexpected techniques %q", repos[0].KeyTechniques)
+	}
+}
+
+func TestParseSummaryRejectsInvalidReplies(t *testing.T) {
+	for _, reply := range []string{"", "no json here", `{"contributions": ["x"]}`, `{"tldr": 3}`} {
+		if _, err := parseSummary(reply); err == nil {
+			t.Errorf("parseSummary(%q) should fail", reply)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package textmatch
+
+import (
+	"sync"
+	"unicode/utf8"
+)
+
+// baseWords is a small dictionary of common Chinese words seen in AI news.
+// It does not need to be complete: its job is to claim the characters
+// around a keyword so that a keyword is only matched on word boundaries,
+// e.g. "资讯" and "飞速" keep "讯飞" from matching "资讯飞速".
+var baseWords = []string{
+	// AI与技术
+	"人工智能", "智能", "机器学习", "深度学习", "强化学习", "机器", "学习", "神经网络", "网络",
+	"大模型", "模型", "语言模型", "大语言模型", "多模态", "智能体", "算法", "算力", "芯片",
+	"训练", "推理", "微调", "对齐", "评测", "基准", "数据", "数据集", "数据库", "向量", "检索",
+	"增强", "生成", "生成式", "图像", "视频", "语音", "识别", "合成", "翻译", "自然语言", "语言",
+	"处理", "计算", "计算机", "视觉", "编程", "代码", "开源", "框架", "工具", "平台", "应用",
+	"接口", "插件", "助手", "机器人", "自动驾驶", "具身智能", "提示词", "上下文", "参数",
+	"架构", "技术", "系统", "研究", "论文", "开发", "开发者", "工程师", "产品", "服务", "安全",
+	// 公司与机构
+	"公司", "企业", "团队", "实验室", "大学", "研究院", "阿里巴巴", "阿里云", "腾讯", "百度", "华为",
+	"字节跳动", "京东", "美团", "小米", "商汤", "旷视", "科大", "科技", "微软", "谷歌", "英伟达",
+	"苹果", "亚马逊", "清华", "北大",
+	// 常用词
+	"发布", "推出", "上线", "更新", "升级", "版本", "最新", "全新", "宣布", "支持", "实现", "提升",
+	"性能", "能力", "效果", "成本", "免费", "价格", "用户", "市场", "行业", "领域", "未来", "趋势",
+	"发展", "飞速", "资讯", "新闻", "报道", "消息", "文章", "教程", "指南", "问题", "方法", "方案",
+	"中国", "国内", "全球", "世界", "第一", "一个", "我们", "他们", "如何", "什么", "为什么", "以及",
+	"可以", "通过", "进行", "使用", "基于", "包括", "已经", "正在", "开始", "今天", "昨天", "本周",
+	"合作", "投资", "融资", "开放", "内测", "公测", "体验", "测试", "对比", "超越", "挑战",
+}
+
+// dictionary is a set of Chinese words used for segmentation
+type dictionary struct {
+	words  map[string]bool
+	maxLen int // 最长词的字数
+}
+
+func newDictionary() *dictionary {
+	return &dictionary{words: make(map[string]bool), maxLen: 1}
+}
+
+// add adds the Chinese words among words and reports whether there were any
+func (d *dictionary) add(words ...string) bool {
+	added := false
+	for _, word := range words {
+		if !isHanWord(word) {
+			continue
+		}
+		d.words[word] = true
+		if n := utf8.RuneCountInString(word); n > d.maxLen {
+			d.maxLen = n
+		}
+		added = true
+	}
+	return added
+}
+
+// has reports whether word is in d; a nil dictionary is empty
+func (d *dictionary) has(word string) bool {
+	return d != nil && d.words[word]
+}
+
+var (
+	dictMu sync.RWMutex
+	dict   = newDictionary() // 所有分词共用的基础词典
+)
+
+func init() {
+	AddWords(baseWords...)
+}
+
+// AddWords adds Chinese words to the shared segmentation dictionary. Words
+// without Chinese characters are ignored. Keywords passed to New do not need
+// to be added: each Matcher keeps its own keywords.
+func AddWords(words ...string) {
+	dictMu.Lock()
+	defer dictMu.Unlock()
+	dict.add(words...)
+}
+
+func isHanWord(word string) bool {
+	if utf8.RuneCountInString(word) < 2 {
+		return false
+	}
+	for _, r := range word {
+		if !isHan(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// segment splits a run of Chinese characters into the fewest words of the
+// shared dictionary and extra, treating unknown characters as
+// single-character words. It also returns the dictionary words contained in
+// the longer words it produced.
+func segment(han []rune, extra *dictionary) (words []string, sub []string) {
+	dictMu.RLock()
+	defer dictMu.RUnlock()
+
+	maxWordLen := dict.maxLen
+	if extra != nil && extra.maxLen > maxWordLen {
+		maxWordLen = extra.maxLen
+	}
+	known := func(word string) bool {
+		return dict.words[word] || extra.has(word)
+	}
+
+	n := len(han)
+	type state struct {
+		count, singles, prev int
+	}
+	// best[i] 为前i个字的最优切分：词数最少，其次单字最少
+	best := make([]state, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = state{count: -1}
+	}
+	for i := 0; i < n; i++ {
+		if best[i].count < 0 {
+			continue
+		}
+		for l := 1; l <= maxWordLen && i+l <= n; l++ {
+			if l > 1 && !known(string(han[i:i+l])) {
+				continue
+			}
+			candidate := state{count: best[i].count + 1, singles: best[i].singles, prev: i}
+			if l == 1 {
+				candidate.singles++
+			}
+			cur := best[i+l]
+			if cur.count < 0 || candidate.count < cur.count ||
+				candidate.count == cur.count && candidate.singles < cur.singles {
+				best[i+l] = candidate
+			}
+		}
+	}
+
+	for end := n; end > 0; end = best[end].prev {
+		words = append(words, string(han[best[end].prev:end]))
+	}
+	for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+		words[i], words[j] = words[j], words[i]
+	}
+
+	for _, word := range words {
+		runes := []rune(word)
+		if len(runes) < 3 {
+			continue
+		}
+		for i := 0; i < len(runes); i++ {
+			for j := i + 2; j <= len(runes); j++ {
+				if j-i < len(runes) && known(string(runes[i:j])) {
+					sub = append(sub, string(runes[i:j]))
+				}
+			}
+		}
+	}
+	return words, sub
+}
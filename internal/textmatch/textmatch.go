@@ -0,0 +1,259 @@
+// Package textmatch matches keywords against text on token boundaries
+// instead of raw substrings, so that "ai" no longer matches "email" and
+// "gan" no longer matches "organization".
+//
+// Text is split into tokens on non-alphanumeric characters, then on
+// camelCase and letter/digit boundaries ("LangChainGPT4" becomes
+// lang, chain, gpt, 4). Runs of Chinese characters are segmented with a
+// dictionary. A keyword matches only whole tokens, so "langchain",
+// "lang-chain" and "LangChain" all match each other, while "rl" does not
+// match "url" and "讯飞" does not match "资讯飞速".
+package textmatch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokens is a tokenized text that can be matched against several keywords
+type Tokens struct {
+	text   string // 原文，含中文时供带专用词典的Matcher重新分词
+	hasHan bool
+	words  []string        // 按顺序排列的词
+	chunks []int           // 每个词所属的单词或连续中文片段的编号
+	sub    map[string]bool // 长中文词中包含的词典子词，如"腾讯混元"中的"混元"
+}
+
+// Tokenize splits text into lowercase tokens
+func Tokenize(text string) Tokens {
+	return tokenize(text, nil)
+}
+
+// tokenize segments Chinese text with the shared dictionary and extra
+func tokenize(text string, extra *dictionary) Tokens {
+	t := Tokens{text: text}
+	var word []rune
+	var han []rune
+	chunk := 0
+
+	// 连字符和下划线只切分词，不切分单词，如 "lang-chain" 仍可拼接为 "langchain"
+	inWord := false
+	flushWord := func(endOfWord bool) {
+		if len(word) > 0 {
+			for _, part := range splitWord(word) {
+				t.words = append(t.words, part)
+				t.chunks = append(t.chunks, chunk)
+			}
+			inWord = true
+			word = word[:0]
+		}
+		if endOfWord && inWord {
+			chunk++
+			inWord = false
+		}
+	}
+	flushHan := func() {
+		if len(han) > 0 {
+			t.hasHan = true
+			words, sub := segment(han, extra)
+			for _, w := range words {
+				t.words = append(t.words, w)
+				t.chunks = append(t.chunks, chunk)
+			}
+			chunk++
+			for _, w := range sub {
+				if t.sub == nil {
+					t.sub = make(map[string]bool)
+				}
+				t.sub[w] = true
+			}
+			han = han[:0]
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case isHan(r):
+			flushWord(true)
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word = append(word, r)
+		case r == '-' || r == '_':
+			flushWord(false)
+			flushHan()
+		default:
+			flushWord(true)
+			flushHan()
+		}
+	}
+	flushWord(true)
+	flushHan()
+	return t
+}
+
+// Words returns the tokens in order
+func (t Tokens) Words() []string {
+	return t.words
+}
+
+// Contains reports whether keyword occurs in the tokens
+func (t Tokens) Contains(keyword string) bool {
+	return t.contains(compile(keyword, nil))
+}
+
+// key is a keyword reduced to its tokens
+type key struct {
+	parts  []string
+	joined string
+}
+
+func compile(keyword string, extra *dictionary) key {
+	parts := tokenize(keyword, extra).words
+	return key{parts: parts, joined: strings.Join(parts, "")}
+}
+
+// contains matches a keyword in two ways:
+//   - its tokens equal a run of consecutive text tokens, e.g. "machine-learning"
+//     matches "Machine Learning" and "gpt-4" matches "GPT4";
+//   - its joined tokens equal a run of tokens split from one word, e.g.
+//     "langchain" matches "LangChain" and "lang-chain", and "ai" matches "OpenAI".
+//
+// A trailing "s" on the text side is accepted as a plural ("agents").
+func (t Tokens) contains(k key) bool {
+	if k.joined == "" {
+		return false
+	}
+	if t.sub[k.joined] {
+		return true
+	}
+
+	n := len(k.parts)
+	for i := range t.words {
+		// 按词序列匹配
+		if i+n <= len(t.words) {
+			matched := true
+			for j := 0; j < n; j++ {
+				w := t.words[i+j]
+				if w != k.parts[j] && !(j == n-1 && w == k.parts[j]+"s") {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+
+		// 在同一个单词内拼接匹配
+		joined := ""
+		for j := i; j < len(t.words) && t.chunks[j] == t.chunks[i] && len(joined) < len(k.joined); j++ {
+			joined += t.words[j]
+			if joined == k.joined || joined == k.joined+"s" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// splitWord splits a run of letters and digits on camelCase and letter/digit
+// boundaries and lowercases the parts
+func splitWord(word []rune) []string {
+	var parts []string
+	start := 0
+	for i := 1; i < len(word); i++ {
+		prev, cur := word[i-1], word[i]
+		split := false
+		switch {
+		case unicode.IsDigit(prev) != unicode.IsDigit(cur):
+			split = true
+		case unicode.IsLower(prev) && unicode.IsUpper(cur):
+			split = true
+		case unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(word) && unicode.IsLower(word[i+1]):
+			// 连续大写后接小写，如 "HTMLParser" 在 "P" 前切分
+			split = true
+		}
+		if split {
+			parts = append(parts, strings.ToLower(string(word[start:i])))
+			start = i
+		}
+	}
+	return append(parts, strings.ToLower(string(word[start:])))
+}
+
+// Matcher matches a fixed set of keywords
+type Matcher struct {
+	keywords []string
+	keys     []key       // 与keywords一一对应
+	dict     *dictionary // 中文关键词，仅用于本Matcher的分词；没有中文关键词时为nil
+}
+
+// New compiles a matcher for keywords. Chinese keywords are kept in a
+// dictionary of the matcher, so that they are segmented as whole words
+// without affecting other matchers or Tokenize.
+func New(keywords ...string) *Matcher {
+	m := &Matcher{}
+	if d := newDictionary(); d.add(keywords...) {
+		m.dict = d
+	}
+	for _, keyword := range keywords {
+		k := compile(keyword, m.dict)
+		if k.joined == "" {
+			continue
+		}
+		m.keywords = append(m.keywords, keyword)
+		m.keys = append(m.keys, k)
+	}
+	return m
+}
+
+// Match returns the keywords found in text, in the order they were given
+func (m *Matcher) Match(text string) []string {
+	return m.MatchTokens(Tokenize(text))
+}
+
+// MatchTokens is like Match for an already tokenized text
+func (m *Matcher) MatchTokens(t Tokens) []string {
+	t = m.resegment(t)
+	var found []string
+	for i, k := range m.keys {
+		if t.contains(k) {
+			found = append(found, m.keywords[i])
+		}
+	}
+	return found
+}
+
+// MatchAny reports whether any keyword occurs in text
+func (m *Matcher) MatchAny(text string) bool {
+	return m.MatchAnyTokens(Tokenize(text))
+}
+
+// MatchAnyTokens is like MatchAny for an already tokenized text
+func (m *Matcher) MatchAnyTokens(t Tokens) bool {
+	t = m.resegment(t)
+	for _, k := range m.keys {
+		if t.contains(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// resegment tokenizes Chinese text again with the matcher's own keywords
+func (m *Matcher) resegment(t Tokens) Tokens {
+	if m.dict == nil || !t.hasHan {
+		return t
+	}
+	return tokenize(t.text, m.dict)
+}
+
+// Contains reports whether a single keyword occurs in text
+func Contains(text, keyword string) bool {
+	return Tokenize(text).Contains(keyword)
+}
@@ -0,0 +1,152 @@
+package textmatch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContainsFalsePositives(t *testing.T) {
+	// 这些组合在strings.Contains下都会误判为命中
+	tests := []struct {
+		text    string
+		keyword string
+	}{
+		{"A simple email client", "ai"},
+		{"Send transactional emails with templates", "ai"},
+		{"Maintained by the community", "ai"},
+		{"Detailed contribution guide", "ai"},
+		{"Sustainability dashboard", "ai"},
+		{"Fast HTML parser", "ml"},
+		{"YAML configuration loader", "ml"},
+		{"Minimal XML toolkit", "ml"},
+		{"URL shortener service", "rl"},
+		{"Control your world from the terminal", "rl"},
+		{"Organization-wide settings sync", "gan"},
+		{"Elegant dashboards for Kubernetes", "gan"},
+		{"Yield farming calculator", "yi"},
+		{"Python library for 3D geometry", "phi"},
+		{"Graphics engine written in Rust", "phi"},
+		{"Open-source commander for files", "command"},
+		{"Storage engine for time series", "rag"},
+		{"Drag and drop file uploads", "rag"},
+		{"Palmistry image archive", "palm"},
+		{"Brainstorming whiteboard", "rag"},
+		{"Ultimate neovim config", "vim"},
+		{"Attention to detail in CSS", "tei"},
+		{"Protein folding visualizer", "tei"},
+		{"Tokenomics dashboard for DAOs", "token"},
+		{"Explain the difference", "llama"},
+		{"资讯飞速发展", "讯飞"},
+		{"阿里巴巴集团新闻", "阿里云"},
+	}
+
+	for _, tt := range tests {
+		if Contains(tt.text, tt.keyword) {
+			t.Errorf("Contains(%q, %q) = true, want false", tt.text, tt.keyword)
+		}
+	}
+}
+
+func TestContainsMatches(t *testing.T) {
+	tests := []struct {
+		text    string
+		keyword string
+	}{
+		{"An AI assistant for your terminal", "ai"},
+		{"OpenAI API client", "ai"},
+		{"openai/whisper", "whisper"},
+		{"hwchase17/langchain", "langchain"},
+		{"LangChain for Go", "langchain"},
+		{"LangChainGo: building LLM apps", "langchaingo"},
+		{"lang-chain examples", "langchain"},
+		{"awesome_machine_learning", "machine-learning"},
+		{"Machine Learning in Rust", "machine-learning"},
+		{"deep-learning course", "deep learning"},
+		{"Build agents with tools", "agent"},
+		{"Large Language Models for everyone", "language model"},
+		{"GPT4 demos", "gpt-4"},
+		{"Chat with GPT-4 from Slack", "gpt4"},
+		{"gpt-3.5-turbo benchmark", "gpt-3.5"},
+		{"Meta Llama 3 inference", "llama-3"},
+		{"llama2.c", "llama2"},
+		{"DeepSeekCoder fine-tuning", "deepseek-coder"},
+		{"vLLM serving engine", "vllm"},
+		{"Stable Diffusion WebUI", "stable-diffusion"},
+		{"Retrieval-Augmented Generation (RAG) toolkit", "rag"},
+		{"HTMLParser for LLM output", "llm"},
+		{"阿里发布通义千问2.5", "通义千问"},
+		{"阿里发布通义千问2.5", "通义"},
+		{"腾讯混元大模型开源", "混元"},
+		{"腾讯混元大模型开源", "腾讯混元"},
+		{"科大讯飞发布星火大模型", "讯飞"},
+		{"基于ChatGLM的知识库问答", "chatglm"},
+		{"百度文心一言更新", "文心一言"},
+	}
+
+	for _, tt := range tests {
+		if !Contains(tt.text, tt.keyword) {
+			t.Errorf("Contains(%q, %q) = false, want true (tokens %q)", tt.text, tt.keyword, Tokenize(tt.text).Words())
+		}
+		if !New(tt.keyword).MatchAny(tt.text) {
+			t.Errorf("New(%q).MatchAny(%q) = false, want true", tt.keyword, tt.text)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"LangChainGPT4", []string{"lang", "chain", "gpt", "4"}},
+		{"HTMLParser", []string{"html", "parser"}},
+		{"llama.cpp", []string{"llama", "cpp"}},
+		{"awesome_llm-apps", []string{"awesome", "llm", "apps"}},
+		{"Qwen2.5-VL", []string{"qwen", "2", "5", "vl"}},
+		{"资讯飞速发展", []string{"资讯", "飞速", "发展"}},
+	}
+
+	for _, tt := range tests {
+		if got := Tokenize(tt.text).Words(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	m := New("llm", "ai", "ml", "rag", "混元")
+
+	got := m.Match("RAG pipelines for LLMs with 混元 embeddings, see the HTML docs")
+	want := []string{"llm", "rag", "混元"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Match() = %q, want %q", got, want)
+	}
+
+	if m.MatchAny("Email templates in HTML") {
+		t.Error("MatchAny() = true for a text without AI keywords")
+	}
+}
+
+// TestMatcherDictionary checks that the Chinese keywords of one matcher do
+// not change how other matchers and Tokenize segment text
+func TestMatcherDictionary(t *testing.T) {
+	before := Tokenize("腾讯混元大模型").Words()
+
+	// "元大"会把"混元大模型"切成"混/元大/模型"，只应影响自己
+	bank := New("元大")
+	model := New("大模型")
+	if !model.MatchAny("腾讯混元大模型") {
+		t.Error("another matcher's keyword changed the segmentation")
+	}
+	if !bank.MatchAny("元大证券") {
+		t.Error("matcher does not find its own Chinese keyword")
+	}
+	if got := Tokenize("腾讯混元大模型").Words(); !reflect.DeepEqual(got, before) {
+		t.Errorf("New changed Tokenize: got %q, want %q", got, before)
+	}
+
+	tokens := Tokenize("科大讯飞 LLM")
+	if !New("讯飞", "llm").MatchAnyTokens(tokens) || New("资讯").MatchAnyTokens(tokens) {
+		t.Error("matching already tokenized text is inconsistent with MatchAny")
+	}
+}
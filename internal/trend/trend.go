@@ -0,0 +1,217 @@
+// Package trend detects repositories breaking out: their star velocity is
+// far above their own history (EWMA z-score) or above the other trending
+// repositories (z-score of the log velocity across the cohort).
+package trend
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// Breakout reasons
+const (
+	ReasonSelf   = "self"   // 远高于自身历史增速
+	ReasonCohort = "cohort" // 远高于同批仓库
+)
+
+// Sample is the star count of a repository at one point in time
+type Sample struct {
+	Time  time.Time
+	Stars int
+}
+
+// Config holds the detection thresholds
+type Config struct {
+	Window      time.Duration // 计算当前增速的时间窗口
+	Alpha       float64       // EWMA平滑系数
+	MinHistory  int           // 计算自身z分数所需的最少历史增速个数
+	MinCohort   int           // 计算同批z分数所需的最少仓库数
+	MinVelocity float64       // 每日新增星标低于该值时不视为突破
+	MinStd      float64       // 标准差下限，避免平稳仓库的微小波动被放大
+	SelfZ       float64       // 自身z分数阈值
+	SelfRatio   float64       // 当前增速至少为历史基线的倍数
+	CohortZ     float64       // 同批z分数阈值
+}
+
+// DefaultConfig returns the thresholds used by the server
+func DefaultConfig() Config {
+	return Config{
+		Window:      24 * time.Hour,
+		Alpha:       0.3,
+		MinHistory:  3,
+		MinCohort:   5,
+		MinVelocity: 50,
+		MinStd:      5,
+		SelfZ:       3,
+		SelfRatio:   2,
+		CohortZ:     2.5,
+	}
+}
+
+// Detect sets the Breakout field of every repository: non-nil for the ones
+// breaking out, nil for the others. history holds the earlier star samples
+// of each repository keyed by models.RepoID, oldest first; the current star
+// count is taken from the repository itself. It returns the number of
+// breakouts.
+func Detect(repos []models.Repository, history map[string][]Sample, now time.Time, cfg Config) int {
+	type stats struct {
+		velocity, baseline, selfZ float64
+		hasHistory                bool
+	}
+	all := make([]stats, len(repos))
+	logs := make([]float64, len(repos))
+
+	for i, repo := range repos {
+		samples := history[models.RepoID(repo.Name)]
+		current := Sample{Time: now, Stars: repo.Stars}
+
+		var st stats
+		base, ok := baseSample(samples, now, cfg.Window)
+		if ok && repo.Stars > 0 {
+			st.velocity = perDay(base, current)
+		} else {
+			// 没有足够历史时使用趋势榜上报告的增长数
+			st.velocity = float64(repo.TrendMetrics.Stars24h)
+		}
+
+		// 基线只使用当前窗口之前的历史
+		var earlier []Sample
+		for _, s := range samples {
+			if !s.Time.After(base.Time) {
+				earlier = append(earlier, s)
+			}
+		}
+		if vs := dailyVelocities(earlier); ok && len(vs) >= cfg.MinHistory {
+			mean, std := ewma(vs, cfg.Alpha)
+			st.baseline = mean
+			st.selfZ = (st.velocity - mean) / math.Max(std, math.Max(cfg.MinStd, 0.1*math.Abs(mean)))
+			st.hasHistory = true
+		}
+
+		all[i] = st
+		logs[i] = math.Log1p(math.Max(st.velocity, 0))
+	}
+
+	cohortMean, cohortStd := meanStd(logs)
+	flagged := 0
+	for i := range repos {
+		st := all[i]
+		cohortZ := 0.0
+		if len(repos) >= cfg.MinCohort && cohortStd > 0 {
+			cohortZ = (logs[i] - cohortMean) / cohortStd
+		}
+
+		var reasons []string
+		if st.velocity >= cfg.MinVelocity {
+			if st.hasHistory && st.selfZ >= cfg.SelfZ && st.velocity >= cfg.SelfRatio*st.baseline {
+				reasons = append(reasons, ReasonSelf)
+			}
+			if cohortZ >= cfg.CohortZ {
+				reasons = append(reasons, ReasonCohort)
+			}
+		}
+		if len(reasons) == 0 {
+			repos[i].Breakout = nil
+			continue
+		}
+		repos[i].Breakout = &models.Breakout{
+			Velocity: round(st.velocity),
+			Baseline: round(st.baseline),
+			SelfZ:    round(st.selfZ),
+			CohortZ:  round(cohortZ),
+			Reasons:  reasons,
+		}
+		flagged++
+	}
+	return flagged
+}
+
+// Breakouts returns the repositories breaking out, fastest first
+func Breakouts(repos []models.Repository) []models.Repository {
+	var result []models.Repository
+	for _, repo := range repos {
+		if repo.Breakout != nil {
+			result = append(result, repo)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Breakout.Velocity > result[j].Breakout.Velocity
+	})
+	return result
+}
+
+// baseSample returns the latest sample taken at least window before now. A
+// younger sample is accepted when it is at least a quarter of the window
+// old, so repositories seen only since a few hours still get a velocity.
+func baseSample(samples []Sample, now time.Time, window time.Duration) (Sample, bool) {
+	for i := len(samples) - 1; i >= 0; i-- {
+		if !samples[i].Time.After(now.Add(-window)) {
+			return samples[i], true
+		}
+	}
+	if len(samples) > 0 && now.Sub(samples[0].Time) >= window/4 {
+		return samples[0], true
+	}
+	return Sample{}, false
+}
+
+// perDay returns the stars per day gained between two samples
+func perDay(from, to Sample) float64 {
+	days := to.Time.Sub(from.Time).Hours() / 24
+	if days <= 0 {
+		return 0
+	}
+	return float64(to.Stars-from.Stars) / days
+}
+
+// dailyVelocities returns the stars per day between samples taken at least
+// a day apart
+func dailyVelocities(samples []Sample) []float64 {
+	if len(samples) < 2 {
+		return nil
+	}
+	var result []float64
+	prev := samples[0]
+	for _, s := range samples[1:] {
+		if s.Time.Sub(prev.Time) < 24*time.Hour {
+			continue
+		}
+		result = append(result, perDay(prev, s))
+		prev = s
+	}
+	return result
+}
+
+// ewma returns the exponentially weighted mean and standard deviation
+func ewma(values []float64, alpha float64) (float64, float64) {
+	mean, variance := values[0], 0.0
+	for _, v := range values[1:] {
+		diff := v - mean
+		mean += alpha * diff
+		variance = (1 - alpha) * (variance + alpha*diff*diff)
+	}
+	return mean, math.Sqrt(variance)
+}
+
+func meanStd(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,104 @@
+package trend
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+// steadyHistory returns daily samples for the last days, gaining perDay
+// stars a day (plus a little noise) and ending one day before now
+func steadyHistory(start, perDay, days int) ([]Sample, int) {
+	var samples []Sample
+	stars := start
+	for d := days; d >= 1; d-- {
+		samples = append(samples, Sample{Time: now.Add(-time.Duration(d) * 24 * time.Hour), Stars: stars})
+		stars += perDay + d%3 - 1
+	}
+	return samples, samples[len(samples)-1].Stars
+}
+
+func TestDetectSelfBreakout(t *testing.T) {
+	jumpHistory, jumpLast := steadyHistory(1000, 60, 8)
+	steady, steadyLast := steadyHistory(5000, 60, 8)
+	small, smallLast := steadyHistory(100, 5, 8)
+
+	repos := []models.Repository{
+		{Name: "acme/rocket", Stars: jumpLast + 2000},
+		{Name: "acme/steady", Stars: steadyLast + 62},
+		{Name: "acme/small", Stars: smallLast + 40}, // 增速暴涨但绝对值太小
+	}
+	history := map[string][]Sample{
+		"acme/rocket": jumpHistory,
+		"acme/steady": steady,
+		"acme/small":  small,
+	}
+
+	if n := Detect(repos, history, now, DefaultConfig()); n != 1 {
+		t.Fatalf("Detect flagged %d repositories, want 1", n)
+	}
+	b := repos[0].Breakout
+	if b == nil || len(b.Reasons) != 1 || b.Reasons[0] != ReasonSelf {
+		t.Fatalf("expected a self breakout, got %+v", b)
+	}
+	if b.Velocity != 2000 || b.Baseline < 55 || b.Baseline > 65 || b.SelfZ < 3 {
+		t.Errorf("unexpected breakout stats %+v", b)
+	}
+	if repos[1].Breakout != nil || repos[2].Breakout != nil {
+		t.Errorf("steady and small repositories should not break out: %+v, %+v", repos[1].Breakout, repos[2].Breakout)
+	}
+}
+
+func TestDetectCohortBreakout(t *testing.T) {
+	// 没有历史时使用趋势榜报告的增长数与同批仓库比较
+	var repos []models.Repository
+	for i := 0; i < 10; i++ {
+		repos = append(repos, models.Repository{
+			Name:         fmt.Sprintf("acme/repo%d", i),
+			Stars:        1000,
+			TrendMetrics: models.TrendMetrics{Stars24h: 20 + i*5},
+		})
+	}
+	repos = append(repos, models.Repository{Name: "acme/viral", Stars: 4000, TrendMetrics: models.TrendMetrics{Stars24h: 3000}})
+
+	if n := Detect(repos, nil, now, DefaultConfig()); n != 1 {
+		t.Fatalf("Detect flagged %d repositories, want 1", n)
+	}
+	viral := repos[len(repos)-1].Breakout
+	if viral == nil || viral.Reasons[0] != ReasonCohort || viral.CohortZ < 2.5 || viral.Baseline != 0 {
+		t.Errorf("unexpected cohort breakout %+v", viral)
+	}
+
+	got := Breakouts(repos)
+	if len(got) != 1 || got[0].Name != "acme/viral" {
+		t.Errorf("Breakouts = %+v", got)
+	}
+
+	// 重新检测时清除过期的标记
+	repos[len(repos)-1].TrendMetrics.Stars24h = 40
+	Detect(repos, nil, now, DefaultConfig())
+	if repos[len(repos)-1].Breakout != nil {
+		t.Error("breakout flag should be cleared when the repository calms down")
+	}
+}
+
+func TestBaseSample(t *testing.T) {
+	samples := []Sample{
+		{Time: now.Add(-48 * time.Hour), Stars: 10},
+		{Time: now.Add(-25 * time.Hour), Stars: 20},
+		{Time: now.Add(-2 * time.Hour), Stars: 30},
+	}
+	if s, ok := baseSample(samples, now, 24*time.Hour); !ok || s.Stars != 20 {
+		t.Errorf("baseSample = %+v, %v; want the sample from 25h ago", s, ok)
+	}
+	if s, ok := baseSample(samples[2:], now, 24*time.Hour); ok {
+		t.Errorf("a 2h old sample is too recent for a velocity, got %+v", s)
+	}
+	if s, ok := baseSample([]Sample{{Time: now.Add(-8 * time.Hour), Stars: 1}}, now, 24*time.Hour); !ok || s.Stars != 1 {
+		t.Errorf("an 8h old sample should be accepted, got %+v, %v", s, ok)
+	}
+}
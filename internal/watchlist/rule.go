@@ -0,0 +1,524 @@
+package watchlist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+	"github.com/gerryyang2025/llm-news/internal/textmatch"
+)
+
+// The rule language is a small boolean expression language over the fields
+// of a repository or paper, for example:
+//
+//	category = "Claude" AND stars_24h > 200
+//	text ~ "RAG" AND source ~ "arxiv" AND keyword = "cs.CL"
+//	(language = Go OR language = Rust) AND NOT topic = "awesome"
+//
+// Comparison operators are =, !=, >, >=, <, <= and ~ (case-insensitive
+// "contains"). String comparisons ignore case. On list fields such as
+// category, topic, keyword and author, a comparison holds when any element
+// matches.
+
+// Rule is a compiled rule expression
+type Rule struct {
+	source string
+	root   node
+}
+
+// String returns the source text of the rule
+func (r *Rule) String() string {
+	return r.source
+}
+
+// MatchRepository evaluates the rule against a repository
+func (r *Rule) MatchRepository(repo models.Repository) bool {
+	return r.root.eval(repoFields{repo: &repo})
+}
+
+// MatchPaper evaluates the rule against a paper
+func (r *Rule) MatchPaper(paper models.Paper) bool {
+	return r.root.eval(paperFields{paper: &paper})
+}
+
+// RepositoryFields and PaperFields list the fields a rule may reference
+var (
+	RepositoryFields = []string{
+		"name", "description", "text", "language", "source", "category", "topic",
+		"stars", "forks", "stars_24h", "gained_stars", "relevance", "has_docs", "doc_quality", "days_since_commit",
+	}
+	PaperFields = []string{
+		"title", "summary", "text", "source", "keyword", "author", "url",
+		"novelty", "reproducibility", "citations", "days_since_published",
+	}
+)
+
+// numericFields are compared as numbers. Their values must be numbers, or
+// true and false for the boolean has_docs.
+var numericFields = map[string]bool{
+	"stars": true, "forks": true, "stars_24h": true, "gained_stars": true, "relevance": true,
+	"has_docs": true, "doc_quality": true, "days_since_commit": true,
+	"novelty": true, "reproducibility": true, "citations": true, "days_since_published": true,
+}
+
+// Parse compiles a rule expression and checks that every referenced field
+// exists for the given target
+func Parse(expr string, target Target) (*Rule, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("rule is empty")
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+
+	allowed := RepositoryFields
+	if target == TargetPapers {
+		allowed = PaperFields
+	}
+	if err := checkFields(root, allowed); err != nil {
+		return nil, err
+	}
+
+	return &Rule{source: expr, root: root}, nil
+}
+
+// ---- 词法分析 ----
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			i++
+			var sb strings.Builder
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++ // 跳过结束引号
+			tokens = append(tokens, token{tokString, sb.String(), start})
+		case strings.ContainsRune("=!<>~", r):
+			start := i
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != '=' && r != '~' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected '!' at position %d", start)
+			}
+			i += len([]rune(op))
+			tokens = append(tokens, token{tokOp, op, start})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_-.", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, string(runes[start:i]), start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+		}
+	}
+
+	return tokens, nil
+}
+
+// ---- 语法分析 ----
+
+type node interface {
+	eval(f fields) bool
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ inner node }
+type compareNode struct {
+	field string
+	op    string
+	value string
+	num   float64 // 数值字段解析后的值
+
+	matcher *textmatch.Matcher // ~ 按词边界匹配value
+}
+
+func (n andNode) eval(f fields) bool { return n.left.eval(f) && n.right.eval(f) }
+func (n orNode) eval(f fields) bool  { return n.left.eval(f) || n.right.eval(f) }
+func (n notNode) eval(f fields) bool { return !n.inner.eval(f) }
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool  { return p.pos >= len(p.tokens) }
+func (p *parser) peek() token { return p.tokens[p.pos] }
+func (p *parser) next() token { t := p.tokens[p.pos]; p.pos++; return t }
+func (p *parser) isKeyword(word string) bool {
+	return !p.done() && p.peek().kind == tokIdent && strings.EqualFold(p.peek().text, word)
+}
+
+// or := and ("OR" and)*
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+// and := unary ("AND" unary)*
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+// unary := "NOT" unary | "(" or ")" | comparison
+func (p *parser) parseUnary() (node, error) {
+	if p.done() {
+		return nil, fmt.Errorf("unexpected end of rule")
+	}
+	if p.isKeyword("NOT") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.next()
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+// comparison := ident op value
+func (p *parser) parseComparison() (node, error) {
+	field := p.next()
+	if field.kind != tokIdent {
+		return nil, fmt.Errorf("expected a field name at position %d, got %q", field.pos, field.text)
+	}
+	if p.done() || p.peek().kind != tokOp {
+		return nil, fmt.Errorf("expected an operator after %q", field.text)
+	}
+	op := p.next()
+	if p.done() {
+		return nil, fmt.Errorf("expected a value after %q", op.text)
+	}
+	value := p.next()
+	if value.kind == tokLParen || value.kind == tokRParen || value.kind == tokOp {
+		return nil, fmt.Errorf("expected a value at position %d, got %q", value.pos, value.text)
+	}
+	n := compareNode{field: strings.ToLower(field.text), op: op.text, value: value.text}
+	if numericFields[n.field] {
+		if n.op == "~" {
+			return nil, fmt.Errorf("operator ~ does not apply to numeric field %q", n.field)
+		}
+		num, err := parseNumber(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %q needs a number at position %d, got %q", n.field, value.pos, value.text)
+		}
+		n.num = num
+	}
+	if n.op == "~" {
+		n.matcher = textmatch.New(n.value)
+	}
+	return n, nil
+}
+
+// parseNumber reads the value of a numeric comparison. Boolean fields may be
+// written as has_docs = true.
+func parseNumber(t token) (float64, error) {
+	if t.kind == tokIdent {
+		switch strings.ToLower(t.text) {
+		case "true":
+			return 1, nil
+		case "false":
+			return 0, nil
+		}
+	}
+	if t.kind != tokNumber && t.kind != tokString { // 允许 stars > "200"
+		return 0, fmt.Errorf("not a number")
+	}
+	return strconv.ParseFloat(t.text, 64)
+}
+
+func checkFields(n node, allowed []string) error {
+	switch v := n.(type) {
+	case andNode:
+		if err := checkFields(v.left, allowed); err != nil {
+			return err
+		}
+		return checkFields(v.right, allowed)
+	case orNode:
+		if err := checkFields(v.left, allowed); err != nil {
+			return err
+		}
+		return checkFields(v.right, allowed)
+	case notNode:
+		return checkFields(v.inner, allowed)
+	case compareNode:
+		for _, field := range allowed {
+			if field == v.field {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown field %q, expected one of: %s", v.field, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// ---- 求值 ----
+
+// fields resolves a field name to its string values or numeric value
+type fields interface {
+	values(name string) ([]string, bool)
+	number(name string) (float64, bool)
+}
+
+func (n compareNode) eval(f fields) bool {
+	if numericFields[n.field] {
+		// 缺失的数值（如未分析的doc_quality）不满足任何比较
+		num, ok := f.number(n.field)
+		if !ok {
+			return false
+		}
+		want := n.num
+		switch n.op {
+		case "=":
+			return num == want
+		case "!=":
+			return num != want
+		case ">":
+			return num > want
+		case ">=":
+			return num >= want
+		case "<":
+			return num < want
+		case "<=":
+			return num <= want
+		}
+		return false
+	}
+
+	values, ok := f.values(n.field)
+	if !ok {
+		return false
+	}
+	want := strings.ToLower(n.value)
+
+	// != 表示所有元素都不相等
+	if n.op == "!=" {
+		for _, v := range values {
+			if strings.ToLower(v) == want {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, raw := range values {
+		v := strings.ToLower(raw)
+		switch n.op {
+		case "=":
+			if v == want {
+				return true
+			}
+		case "~":
+			// 用原文分词以保留驼峰边界，"rag" 匹配 "FastRAG" 但不匹配 "storage"
+			if n.matcher.MatchAny(raw) {
+				return true
+			}
+		case ">":
+			if v > want {
+				return true
+			}
+		case ">=":
+			if v >= want {
+				return true
+			}
+		case "<":
+			if v < want {
+				return true
+			}
+		case "<=":
+			if v <= want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type repoFields struct{ repo *models.Repository }
+
+func (f repoFields) values(name string) ([]string, bool) {
+	switch name {
+	case "name":
+		return []string{f.repo.Name}, true
+	case "description":
+		return []string{f.repo.Description}, true
+	case "text":
+		return []string{f.repo.Name + " " + f.repo.Description + " " + strings.Join(f.repo.TechStack, " ")}, true
+	case "language":
+		return []string{f.repo.Language}, true
+	case "source":
+		return []string{f.repo.Source}, true
+	case "category":
+		return f.repo.GetModelCategories(), true
+	case "topic":
+		return f.repo.TechStack, true
+	}
+	return nil, false
+}
+
+func (f repoFields) number(name string) (float64, bool) {
+	switch name {
+	case "stars":
+		return float64(f.repo.Stars), true
+	case "forks":
+		return float64(f.repo.Forks), true
+	case "stars_24h":
+		return float64(f.repo.TrendMetrics.Stars24h), true
+	case "gained_stars":
+		return float64(f.repo.GainedStars), true
+	case "relevance":
+		return f.repo.RelevanceScore, true
+	case "has_docs":
+		if f.repo.HasDocs {
+			return 1, true
+		}
+		return 0, true
+	case "doc_quality":
+		if f.repo.DocQuality == nil {
+			return 0, false
+		}
+		return f.repo.DocQuality.Score, true
+	case "days_since_commit":
+		if f.repo.LastCommit.IsZero() {
+			return 0, false
+		}
+		return time.Since(f.repo.LastCommit).Hours() / 24, true
+	}
+	return 0, false
+}
+
+type paperFields struct{ paper *models.Paper }
+
+func (f paperFields) values(name string) ([]string, bool) {
+	switch name {
+	case "title":
+		return []string{f.paper.Title}, true
+	case "summary":
+		return []string{f.paper.Summary}, true
+	case "text":
+		return []string{f.paper.Title + " " + f.paper.Summary + " " + strings.Join(f.paper.Keywords, " ")}, true
+	case "source":
+		return []string{f.paper.Source}, true
+	case "keyword":
+		return f.paper.Keywords, true
+	case "author":
+		return f.paper.Authors, true
+	case "url":
+		return []string{f.paper.URL}, true
+	}
+	return nil, false
+}
+
+func (f paperFields) number(name string) (float64, bool) {
+	switch name {
+	case "novelty":
+		return f.paper.NoveltyScore, true
+	case "reproducibility":
+		return f.paper.ReproducibilityScore, true
+	case "citations":
+		return float64(f.paper.CitationCount), true
+	case "days_since_published":
+		if f.paper.PublishedDate.IsZero() {
+			return 0, false
+		}
+		return time.Since(f.paper.PublishedDate).Hours() / 24, true
+	}
+	return 0, false
+}
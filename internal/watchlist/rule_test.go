@@ -0,0 +1,181 @@
+package watchlist
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr   string
+		target Target
+		err    string // 错误信息中应包含的片段
+	}{
+		{"", TargetRepos, "rule is empty"},
+		{"stars >", TargetRepos, `expected a value after ">"`},
+		{"stars 200", TargetRepos, `expected an operator after "stars"`},
+		{"(stars > 1", TargetRepos, "missing closing parenthesis"},
+		{"stars > 1)", TargetRepos, `unexpected ")" at position 9`},
+		{"stars > 1 AND", TargetRepos, "unexpected end of rule"},
+		{`name = "open`, TargetRepos, "unterminated string at position 7"},
+		{"stars ! 1", TargetRepos, "unexpected '!' at position 6"},
+		{"stars > 1 # x", TargetRepos, "unexpected character '#' at position 10"},
+		{"stars = = 1", TargetRepos, `expected a value at position 8, got "="`},
+		{"owner = x", TargetRepos, `unknown field "owner"`},
+		{"stars > 10", TargetPapers, `unknown field "stars"`},
+		{"keyword = cs.CL", TargetRepos, `unknown field "keyword"`},
+		{"stars > abc", TargetRepos, `field "stars" needs a number at position 8, got "abc"`},
+		{`citations >= "many"`, TargetPapers, `field "citations" needs a number`},
+		{"has_docs = yes", TargetRepos, `field "has_docs" needs a number`},
+		{"stars ~ 1", TargetRepos, `operator ~ does not apply to numeric field "stars"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr, tt.target)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+var testRepo = models.Repository{
+	Name:         "acme/FastRAG",
+	Description:  "Retrieval augmented generation in Go",
+	Language:     "Go",
+	Source:       "GitHub",
+	Stars:        1500,
+	Forks:        40,
+	TechStack:    []string{"rag", "vector-db"},
+	TrendMetrics: models.TrendMetrics{Stars24h: 250},
+	HasDocs:      true,
+	LastCommit:   time.Now().Add(-48 * time.Hour),
+}
+
+func TestMatchRepository(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"stars > 1000", true},
+		{"stars >= 1500 AND stars <= 1500", true},
+		{"stars = 1500.0", true},
+		{`stars > "1000"`, true},
+		{"stars_24h < 200", false},
+		{"forks != 40", false},
+		{"has_docs = true", true},
+		{"has_docs = FALSE", false},
+		{"days_since_commit < 3", true},
+		{"doc_quality > 0", false}, // 未分析时缺失，不满足任何比较
+		{"NOT doc_quality > 0", true},
+
+		// 字符串比较忽略大小写，~ 按词边界匹配
+		{"language = go", true},
+		{`name = "ACME/fastrag"`, true},
+		{"name ~ rag", true},
+		{"description ~ 'augmented generation'", true},
+		{`description ~ "say \"hi\""`, false},
+		{"text ~ vector-db", true},
+		{"text ~ vectordb", true},
+		{"description ~ gen", false},
+		{"name ~ acm", false},
+		{"language != Rust", true},
+		{"source = github", true},
+
+		// 列表字段任一元素满足即可，!= 要求所有元素都不相等
+		{"topic = RAG", true},
+		{"topic = llm", false},
+		{"topic != rag", false},
+		{"topic != llm", true},
+
+		// AND 优先于 OR，NOT 优先于 AND
+		{"stars > 9999 AND language = Go OR topic = rag", true},
+		{"topic = rag OR stars > 9999 AND language = Rust", true},
+		{"(topic = rag OR stars > 9999) AND language = Rust", false},
+		{"NOT language = Rust AND stars > 1000", true},
+		{"NOT (language = Go AND stars > 1000)", false},
+		{"NOT NOT language = go", true},
+		{"stars > 1000 and language = go or language = rust", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			rule, err := Parse(tt.expr, TargetRepos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.MatchRepository(testRepo); got != tt.want {
+				t.Errorf("MatchRepository = %v, want %v", got, tt.want)
+			}
+			if rule.String() != tt.expr {
+				t.Errorf("String() = %q, want the source text", rule.String())
+			}
+		})
+	}
+}
+
+func TestMatchPaper(t *testing.T) {
+	paper := models.Paper{
+		Title:         "Scaling RAG",
+		URL:           "https://arxiv.org/abs/2401.00001",
+		Authors:       []string{"Ada Lovelace", "Alan Turing"},
+		Source:        "arXiv",
+		Keywords:      []string{"cs.CL", "cs.AI"},
+		CitationCount: 12,
+		NoveltyScore:  4.5,
+		PublishedDate: time.Now().Add(-24 * time.Hour),
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"keyword = cs.cl AND source ~ arxiv", true},
+		{`author = "alan turing"`, true},
+		{"author ~ grace", false},
+		{"citations >= 10 AND novelty > 4", true},
+		{"days_since_published <= 2", true},
+		{"text ~ rag AND NOT url ~ github", true},
+		{"keyword ~ cs", true},
+		{`author ~ "ada lovelace"`, true},
+		{"reproducibility > 0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			rule, err := Parse(tt.expr, TargetPapers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.MatchPaper(paper); got != tt.want {
+				t.Errorf("MatchPaper = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMatchWordBoundary checks that ~ matches whole words only
+func TestMatchWordBoundary(t *testing.T) {
+	paper := models.Paper{Title: "Object storage for LLM checkpoints", Summary: "资讯飞速更新的训练框架"}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`text ~ "rag"`, false},
+		{"text ~ storage", true},
+		{"text ~ checkpoint", true},
+		{"text ~ 讯飞", false},
+		{"text ~ 资讯", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			rule, err := Parse(tt.expr, TargetPapers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := rule.MatchPaper(paper); got != tt.want {
+				t.Errorf("MatchPaper = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package watchlist
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// Target selects whether a watchlist applies to repositories or papers
+type Target string
+
+const (
+	TargetRepos  Target = "repos"
+	TargetPapers Target = "papers"
+)
+
+// maxHits bounds the number of hits kept on disk
+const maxHits = 1000
+
+// ErrNotFound is returned when a watchlist does not exist
+var ErrNotFound = errors.New("watchlist not found")
+
+// Watchlist is a saved alert rule. Repository watchlists may additionally
+// carry structured FilterCriteria that are checked before the rule.
+type Watchlist struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Owner     string                 `json:"owner,omitempty"`
+	Target    Target                 `json:"target"`
+	Rule      string                 `json:"rule"`
+	Criteria  *models.FilterCriteria `json:"criteria,omitempty"`
+	Notify    bool                   `json:"notify"` // 命中时是否推送通知
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+
+	compiled *Rule
+}
+
+// Hit records that an item matched a watchlist after a refresh
+type Hit struct {
+	WatchlistID string    `json:"watchlist_id"`
+	Target      Target    `json:"target"`
+	Key         string    `json:"key"` // 仓库名或论文URL
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	MatchedAt   time.Time `json:"matched_at"`
+}
+
+// ValidationError is returned for a watchlist that fails validation, as
+// opposed to failures of reading or writing the watchlist files
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// Validate normalizes the watchlist and compiles its rule. Errors are of
+// type *ValidationError.
+func (w *Watchlist) Validate() error {
+	if err := w.validate(); err != nil {
+		return &ValidationError{Err: err}
+	}
+	return nil
+}
+
+func (w *Watchlist) validate() error {
+	w.Name = strings.TrimSpace(w.Name)
+	w.Rule = strings.TrimSpace(w.Rule)
+	if w.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if w.Target == "" {
+		w.Target = TargetRepos
+	}
+	if w.Target != TargetRepos && w.Target != TargetPapers {
+		return fmt.Errorf("target must be %q or %q", TargetRepos, TargetPapers)
+	}
+	if w.Criteria != nil && w.Target != TargetRepos {
+		return fmt.Errorf("criteria are only supported for repository watchlists")
+	}
+	if w.Rule == "" && w.Criteria == nil {
+		return fmt.Errorf("rule or criteria is required")
+	}
+
+	w.compiled = nil
+	if w.Rule != "" {
+		rule, err := Parse(w.Rule, w.Target)
+		if err != nil {
+			return fmt.Errorf("invalid rule: %w", err)
+		}
+		w.compiled = rule
+	}
+	return nil
+}
+
+// MatchRepository reports whether a repository satisfies the criteria and rule
+func (w *Watchlist) MatchRepository(repo models.Repository) bool {
+	if w.Target != TargetRepos {
+		return false
+	}
+	if w.Criteria != nil && !w.Criteria.Matches(repo) {
+		return false
+	}
+	return w.compiled == nil || w.compiled.MatchRepository(repo)
+}
+
+// MatchPaper reports whether a paper satisfies the rule
+func (w *Watchlist) MatchPaper(paper models.Paper) bool {
+	if w.Target != TargetPapers || w.compiled == nil {
+		return false
+	}
+	return w.compiled.MatchPaper(paper)
+}
+
+// Manager stores watchlists and their hits as JSON files in a directory
+type Manager struct {
+	dir string
+
+	mu         sync.RWMutex
+	watchlists map[string]*Watchlist
+	invalid    []*Watchlist // 加载时校验失败的条目，原样保存回文件以免丢失
+	hits       []Hit
+	seen       map[string]bool // watchlistID|key，同一条目只记录首次命中
+}
+
+// Open loads watchlists and hits from dir. A stored watchlist that fails
+// validation is skipped with a warning but kept in the file.
+func Open(dir string) (*Manager, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create watchlist directory: %w", err)
+	}
+
+	m := &Manager{
+		dir:        dir,
+		watchlists: make(map[string]*Watchlist),
+		seen:       make(map[string]bool),
+	}
+
+	var lists []*Watchlist
+	if err := readJSON(m.watchlistPath(), &lists); err != nil {
+		return nil, err
+	}
+	for _, w := range lists {
+		if err := w.Validate(); err != nil {
+			log.Printf("Warning: Skipping stored watchlist %s: %v", w.ID, err)
+			m.invalid = append(m.invalid, w)
+			continue
+		}
+		m.watchlists[w.ID] = w
+	}
+
+	if err := readJSON(m.hitsPath(), &m.hits); err != nil {
+		return nil, err
+	}
+	for _, hit := range m.hits {
+		m.seen[hit.WatchlistID+"|"+hit.Key] = true
+	}
+
+	return m, nil
+}
+
+func (m *Manager) watchlistPath() string { return filepath.Join(m.dir, "watchlists.json") }
+func (m *Manager) hitsPath() string      { return filepath.Join(m.dir, "watchlist_hits.json") }
+
+// List returns the watchlists, optionally restricted to one owner
+func (m *Manager) List(owner string) []Watchlist {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := []Watchlist{}
+	for _, w := range m.watchlists {
+		if owner != "" && w.Owner != owner {
+			continue
+		}
+		result = append(result, *w)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Get returns one watchlist
+func (m *Manager) Get(id string) (Watchlist, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	w, ok := m.watchlists[id]
+	if !ok {
+		return Watchlist{}, ErrNotFound
+	}
+	return *w, nil
+}
+
+// Create validates and stores a new watchlist
+func (m *Manager) Create(w Watchlist) (Watchlist, error) {
+	if err := w.Validate(); err != nil {
+		return Watchlist{}, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Watchlist{}, err
+	}
+	w.ID = id
+	w.CreatedAt = time.Now().UTC()
+	w.UpdatedAt = w.CreatedAt
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watchlists[w.ID] = &w
+	if err := m.saveWatchlistsLocked(); err != nil {
+		delete(m.watchlists, w.ID)
+		return Watchlist{}, err
+	}
+	return w, nil
+}
+
+// Update replaces an existing watchlist, keeping its ID and creation time
+func (m *Manager) Update(id string, w Watchlist) (Watchlist, error) {
+	if err := w.Validate(); err != nil {
+		return Watchlist{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.watchlists[id]
+	if !ok {
+		return Watchlist{}, ErrNotFound
+	}
+	w.ID = id
+	w.CreatedAt = existing.CreatedAt
+	w.UpdatedAt = time.Now().UTC()
+
+	m.watchlists[id] = &w
+	if err := m.saveWatchlistsLocked(); err != nil {
+		m.watchlists[id] = existing
+		return Watchlist{}, err
+	}
+	return w, nil
+}
+
+// Delete removes a watchlist and its hits
+func (m *Manager) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.watchlists[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(m.watchlists, id)
+	if err := m.saveWatchlistsLocked(); err != nil {
+		m.watchlists[id] = existing
+		return err
+	}
+
+	kept := m.hits[:0]
+	for _, hit := range m.hits {
+		if hit.WatchlistID == id {
+			delete(m.seen, hit.WatchlistID+"|"+hit.Key)
+			continue
+		}
+		kept = append(kept, hit)
+	}
+	m.hits = kept
+	return writeJSON(m.hitsPath(), m.hits)
+}
+
+// Hits returns the recorded hits of a watchlist, newest first.
+// An empty id returns the hits of every watchlist.
+func (m *Manager) Hits(id string) []Hit {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := []Hit{}
+	for i := len(m.hits) - 1; i >= 0; i-- {
+		if id == "" || m.hits[i].WatchlistID == id {
+			result = append(result, m.hits[i])
+		}
+	}
+	return result
+}
+
+// Evaluate runs every watchlist against a refreshed snapshot, records the
+// new hits and returns them. Items that already matched a watchlist are not
+// reported again.
+func (m *Manager) Evaluate(repos []models.Repository, papers []models.Paper) ([]Hit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	var newHits []Hit
+	record := func(w *Watchlist, key, title, url string) {
+		seenKey := w.ID + "|" + key
+		if m.seen[seenKey] {
+			return
+		}
+		m.seen[seenKey] = true
+		newHits = append(newHits, Hit{
+			WatchlistID: w.ID,
+			Target:      w.Target,
+			Key:         key,
+			Title:       title,
+			URL:         url,
+			MatchedAt:   now,
+		})
+	}
+
+	for _, w := range m.watchlists {
+		switch w.Target {
+		case TargetRepos:
+			for _, repo := range repos {
+				if w.MatchRepository(repo) {
+					record(w, strings.ToLower(repo.Name), repo.Name, repo.URL)
+				}
+			}
+		case TargetPapers:
+			for _, paper := range papers {
+				if w.MatchPaper(paper) {
+					key := paper.URL
+					if key == "" {
+						key = strings.ToLower(paper.Title)
+					}
+					record(w, key, paper.Title, paper.URL)
+				}
+			}
+		}
+	}
+
+	if len(newHits) == 0 {
+		return nil, nil
+	}
+
+	m.hits = append(m.hits, newHits...)
+	if len(m.hits) > maxHits {
+		// 保留最新的记录，但仍保留seen集合以免重复通知
+		m.hits = append([]Hit(nil), m.hits[len(m.hits)-maxHits:]...)
+	}
+	return newHits, writeJSON(m.hitsPath(), m.hits)
+}
+
+func (m *Manager) saveWatchlistsLocked() error {
+	lists := make([]*Watchlist, 0, len(m.watchlists))
+	for _, w := range m.watchlists {
+		lists = append(lists, w)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].CreatedAt.Before(lists[j].CreatedAt)
+	})
+	return writeJSON(m.watchlistPath(), append(lists, m.invalid...))
+}
+
+func newID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate watchlist id: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return os.Rename(tmp, path)
+}
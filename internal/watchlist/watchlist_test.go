@@ -0,0 +1,142 @@
+package watchlist
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gerryyang2025/llm-news/internal/models"
+)
+
+// TestOpenSkipsInvalid checks that one broken stored watchlist does not
+// take down the others and is kept in the file
+func TestOpenSkipsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	stored := `[
+  {"id": "good", "name": "Go repos", "target": "repos", "rule": "language = go"},
+  {"id": "bad", "name": "Broken", "target": "repos", "rule": "stars > abc"}
+]`
+	if err := os.WriteFile(filepath.Join(dir, "watchlists.json"), []byte(stored), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get("good"); err != nil {
+		t.Errorf("valid watchlist was not loaded: %v", err)
+	}
+	if _, err := m.Get("bad"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("invalid watchlist was loaded, err = %v", err)
+	}
+
+	if _, err := m.Create(Watchlist{Name: "Papers", Target: TargetPapers, Rule: "keyword = cs.CL"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "watchlists.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"id": "bad"`) {
+		t.Errorf("saving dropped the invalid watchlist:\n%s", data)
+	}
+}
+
+func TestManager(t *testing.T) {
+	dir := t.TempDir()
+	m, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Create(Watchlist{Name: " ", Rule: "stars > 1"}); err == nil {
+		t.Error("Create accepted a watchlist without name")
+	}
+	w, err := m.Create(Watchlist{Name: "Big Go", Rule: "language = go AND stars > 100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.ID == "" || w.Target != TargetRepos || w.CreatedAt.IsZero() {
+		t.Errorf("Create did not fill in defaults: %+v", w)
+	}
+
+	repos := []models.Repository{
+		{Name: "a/go-big", URL: "https://github.com/a/go-big", Language: "Go", Stars: 500},
+		{Name: "b/go-small", Language: "Go", Stars: 10},
+	}
+	hits, err := m.Evaluate(repos, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].Key != "a/go-big" || hits[0].WatchlistID != w.ID {
+		t.Fatalf("got hits %+v, want a/go-big", hits)
+	}
+	if hits, _ := m.Evaluate(repos, nil); len(hits) != 0 {
+		t.Errorf("repeated match was reported again: %+v", hits)
+	}
+
+	if _, err := m.Update("missing", w); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update of a missing watchlist returned %v", err)
+	}
+	w.Rule = "stars > 5"
+	updated, err := m.Update(w.ID, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.CreatedAt.Equal(w.CreatedAt) {
+		t.Error("Update changed the creation time")
+	}
+
+	// 重新打开后规则和命中记录都应保留
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reopened.Get(w.ID)
+	if err != nil || got.Rule != "stars > 5" {
+		t.Fatalf("reopened watchlist = %+v, %v", got, err)
+	}
+	if hits, _ := reopened.Evaluate(repos, nil); len(hits) != 1 || hits[0].Key != "b/go-small" {
+		t.Errorf("after reopening got hits %+v, want only b/go-small", hits)
+	}
+
+	if err := reopened.Delete(w.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(reopened.Hits("")) != 0 {
+		t.Error("Delete kept the hits of the watchlist")
+	}
+	if err := reopened.Delete(w.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete returned %v", err)
+	}
+}
+
+// TestErrorKinds checks that callers can tell invalid input from storage
+// failures
+func TestErrorKinds(t *testing.T) {
+	dir := t.TempDir()
+	m, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var invalid *ValidationError
+	if _, err := m.Create(Watchlist{Name: "x", Rule: "stars > abc"}); !errors.As(err, &invalid) {
+		t.Errorf("invalid rule returned %v, want a ValidationError", err)
+	}
+
+	// 用同名目录占住临时文件路径，使写入失败
+	if err := os.Mkdir(filepath.Join(dir, "watchlists.json.tmp"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.Create(Watchlist{Name: "x", Rule: "stars > 1"})
+	if err == nil || errors.As(err, &invalid) {
+		t.Errorf("write failure returned %v, want a storage error", err)
+	}
+	if len(m.List("")) != 0 {
+		t.Error("failed Create kept the watchlist")
+	}
+}
@@ -47,7 +47,8 @@ cd "$ROOT_DIR"
 # -s: 去除符号表
 # -w: 去除DWARF调试信息，减小二进制文件大小
 # 可以根据需要调整
-go build -ldflags="-s -w" -o "$OUTPUT_FILE" ./cmd/server/main.go
+go build -ldflags="-s -w" -o "$OUTPUT_FILE" ./cmd/server && \
+    go build -ldflags="-s -w" -o "$OUTPUT_DIR/llmnews" ./cmd/llmnews
 
 # 检查构建结果
 if [ $? -eq 0 ] && [ -f "$OUTPUT_FILE" ]; then
@@ -63,6 +64,8 @@ if [ $? -eq 0 ] && [ -f "$OUTPUT_FILE" ]; then
 
     echo -e "${YELLOW}你可以使用以下命令运行:${NC}"
     echo -e "${GREEN}$OUTPUT_FILE${NC}"
+    echo -e "${YELLOW}命令行工具:${NC}"
+    echo -e "${GREEN}$OUTPUT_DIR/llmnews help${NC}"
 else
     echo -e "${RED}构建失败${NC}"
     exit 1
@@ -59,7 +59,7 @@ for PLATFORM in "${PLATFORMS[@]}"; do
     export GOARCH=$GOARCH
 
     # 构建
-    go build -ldflags="-s -w" -o "$OUTPUT_FILE" ./cmd/server/main.go
+    go build -ldflags="-s -w" -o "$OUTPUT_FILE" ./cmd/server
 
     if [ $? -eq 0 ]; then
         echo -e "${GREEN}$GOOS/$GOARCH 构建成功!${NC}"
@@ -38,7 +38,7 @@ elif [ -f "$NATIVE_BINARY_PATH_EXE" ]; then
 else
     echo "未找到构建好的二进制文件，使用 'go run' 命令运行..."
     # 运行服务，将输出重定向到日志文件，并将进程放入后台
-    cd "$ROOT_DIR" && nohup go run cmd/server/main.go > "$ROOT_DIR/logs/llm-news.log" 2>&1 &
+    cd "$ROOT_DIR" && nohup go run ./cmd/server > "$ROOT_DIR/logs/llm-news.log" 2>&1 &
 fi
 
 # 保存进程ID到文件
@@ -1,3 +1,3 @@
 #!/bin/bash
-pkill -f "go run cmd/server/main.go"
+pkill -f "go run ./cmd/server"
 kill -9 $(lsof -ti:8081) 2>/dev/null || echo "端口8081未被占用"
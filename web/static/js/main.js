@@ -1,3 +1,12 @@
+// t returns the localized message for key from the catalog injected by the
+// server (window.I18N), replacing {0}, {1}, ... with the arguments
+function t(key, ...args) {
+    const messages = window.I18N || {};
+    const template = messages[key] !== undefined ? messages[key] : key;
+    return template.replace(/\{(\d+)\}/g, (match, index) =>
+        args[index] !== undefined ? args[index] : match);
+}
+
 document.addEventListener('DOMContentLoaded', function() {
     // Add a timestamp to show when the data was last updated
     const now = new Date();
@@ -5,7 +14,7 @@ document.addEventListener('DOMContentLoaded', function() {
 
     if (footer) {
         const lastUpdated = document.createElement('p');
-        lastUpdated.textContent = `Last updated: ${now.toLocaleString()}`;
+        lastUpdated.textContent = t('js.last_updated', now.toLocaleString());
         lastUpdated.style.fontSize = '0.85rem';
         lastUpdated.style.marginTop = '0.5rem';
         footer.querySelector('.container').appendChild(lastUpdated);
@@ -87,7 +96,7 @@ document.addEventListener('DOMContentLoaded', function() {
             activeModelFilter = '';
             const dropdownBtn = document.querySelector('#repositories .dropbtn');
             if (dropdownBtn) {
-                dropdownBtn.textContent = 'Models ▼';
+                dropdownBtn.textContent = `${t('js.models')} ▼`;
                 dropdownBtn.classList.remove('active');
             }
 
@@ -124,7 +133,7 @@ document.addEventListener('DOMContentLoaded', function() {
         // 显示加载状态
         const repoGrid = document.querySelector('.repo-grid');
         if (repoGrid) {
-            repoGrid.innerHTML = '<div class="loading"><i class="fas fa-spinner fa-spin"></i><p>正在查找相关仓库...</p></div>';
+            repoGrid.innerHTML = '<div class="loading"><i class="fas fa-spinner fa-spin"></i><p>' + t('js.searching') + '</p></div>';
         }
 
         try {
@@ -213,7 +222,7 @@ document.addEventListener('DOMContentLoaded', function() {
                             if (cardTitle) {
                                 const badge = document.createElement('span');
                                 badge.className = 'official-badge';
-                                badge.textContent = 'Official';
+                                badge.textContent = t('js.official');
                                 cardTitle.appendChild(badge);
                             }
                         }
@@ -229,7 +238,7 @@ document.addEventListener('DOMContentLoaded', function() {
             // 更新标题以显示当前显示的是哪个模型的仓库
             const filterTitle = document.querySelector('.repo-filter-title');
             if (filterTitle) {
-                filterTitle.textContent = `${modelName} Repositories (${visibleCount})`;
+                filterTitle.textContent = t('js.model_repos', modelName, visibleCount);
                 filterTitle.style.display = 'block';
             }
 
@@ -240,7 +249,7 @@ document.addEventListener('DOMContentLoaded', function() {
             if (visibleCount === 0) {
                 repoGrid.innerHTML = `<div class="empty-state">
                     <i class="fas fa-search"></i>
-                    <p>没有找到与 ${modelName} 相关的仓库</p>
+                    <p>${t('js.no_model_repos', modelName)}</p>
                 </div>`;
             }
 
@@ -250,8 +259,8 @@ document.addEventListener('DOMContentLoaded', function() {
             if (repoGrid) {
                 repoGrid.innerHTML = `<div class="error">
                     <i class="fas fa-exclamation-triangle"></i>
-                    <p>过滤仓库时发生错误: ${error.message}</p>
-                    <button id="retry-btn" class="retry-button">重试</button>
+                    <p>${t('js.filter_error', error.message)}</p>
+                    <button id="retry-btn" class="retry-button">${t('js.retry')}</button>
                 </div>`;
 
                 // 添加重试按钮逻辑
@@ -337,7 +346,7 @@ document.addEventListener('DOMContentLoaded', function() {
         // 显示"正在过滤"提示
         const repoGrid = document.querySelector('.repo-grid');
         if (repoGrid) {
-            repoGrid.innerHTML = '<div class="loading"><i class="fas fa-spinner fa-spin"></i><p>正在应用过滤器...</p></div>';
+            repoGrid.innerHTML = '<div class="loading"><i class="fas fa-spinner fa-spin"></i><p>' + t('js.filtering') + '</p></div>';
         }
 
         // 应用模型过滤器优先
@@ -418,7 +427,7 @@ document.addEventListener('DOMContentLoaded', function() {
         if (visibleCount === 0) {
             repoGrid.innerHTML = `<div class="empty-state">
                 <i class="fas fa-search"></i>
-                <p>没有找到匹配当前过滤器的仓库</p>
+                <p>${t('js.no_filter_repos')}</p>
             </div>`;
         }
     }
@@ -427,7 +436,7 @@ document.addEventListener('DOMContentLoaded', function() {
     function updateFilterResultCount(count) {
         const countElement = document.querySelector('.filter-result-count');
         if (countElement) {
-            countElement.textContent = count === 1 ? '1 repository' : `${count} repositories`;
+            countElement.textContent = count === 1 ? t('js.one_repo') : t('js.repo_count', count);
         }
     }
 
@@ -487,17 +496,145 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     });
 
-    // Add click event to repository cards for better mobile experience
-    const repoCards = document.querySelectorAll('.repo-card');
-    repoCards.forEach(card => {
-        card.addEventListener('click', function(e) {
+    // Add click event to repository cards for better mobile experience.
+    // 使用事件委托，过滤和实时更新替换后的卡片同样有效
+    const repoSection = document.querySelector('#repositories');
+    if (repoSection) {
+        repoSection.addEventListener('click', function(e) {
+            const card = e.target.closest('.repo-card');
             // Only trigger if the click wasn't on the link itself
-            if (!e.target.closest('a')) {
+            if (card && !e.target.closest('a')) {
                 const link = card.querySelector('h3 a');
                 if (link) {
                     window.open(link.href, '_blank');
                 }
             }
         });
-    });
+    }
+
+    // 实时更新：订阅/api/stream，数据刷新后就地更新卡片，无需整页重载
+    if (document.body.hasAttribute('data-live') && window.EventSource) {
+        const stream = new EventSource('/api/stream');
+        let refreshTimer = null;
+
+        stream.addEventListener('refresh', function() {
+            // 多个数据源可能接连刷新，合并为一次页面请求
+            clearTimeout(refreshTimer);
+            refreshTimer = setTimeout(refreshInPlace, 2000);
+        });
+        stream.addEventListener('breakout', function(e) {
+            const data = JSON.parse(e.data);
+            data.repos.forEach(repo => showToast(t('js.breakout_started', repo.name), '/repos/' + repo.id));
+        });
+        stream.addEventListener('health', function(e) {
+            const data = JSON.parse(e.data);
+            showToast(t('js.source_' + data.source.status, data.source.source));
+        });
+    }
+
+    // 重新请求当前页面，用新的HTML替换变化的部分。
+    // 刷新不算新的访问：服务器不更新last_visit cookie，仍与本页对应的上次访问比较
+    function refreshInPlace() {
+        const headers = {
+            'X-Live-Refresh': '1',
+            'X-Last-Visit': document.body.dataset.lastVisit || ''
+        };
+        fetch(window.location.href, { credentials: 'same-origin', headers: headers })
+            .then(response => response.ok ? response.text() : Promise.reject(response.status))
+            .then(html => {
+                const fresh = new DOMParser().parseFromString(html, 'text/html');
+                syncElement(fresh, '.last-update');
+                syncElement(fresh, '.new-since-visit', el => document.querySelector('.last-update').after(el));
+                syncElement(fresh, '.main-nav ul');
+                syncElement(fresh, '#breakouts', el => document.querySelector('main').prepend(el));
+                updateRepoCards(fresh);
+                updatePaperCards(fresh);
+            })
+            .catch(err => console.log('实时更新失败:', err));
+    }
+
+    // syncElement replaces the element matching selector with the one of the
+    // fresh page, removes it when the fresh page has none, and adds it with
+    // insert when only the fresh page has one
+    function syncElement(fresh, selector, insert) {
+        const current = document.querySelector(selector);
+        const next = fresh.querySelector(selector);
+        if (current && next) {
+            current.replaceWith(next);
+        } else if (current) {
+            current.remove();
+        } else if (next && insert) {
+            insert(next);
+        }
+    }
+
+    function setScoreWidths(root) {
+        root.querySelectorAll('.score-fill').forEach(el => {
+            const percent = el.getAttribute('data-percent');
+            if (percent) {
+                el.style.width = percent + '%';
+            }
+        });
+    }
+
+    function updateRepoCards(fresh) {
+        const freshCards = Array.from(fresh.querySelectorAll('.repo-card'));
+        if (freshCards.length === 0) {
+            return; // 抓取失败时保留当前卡片
+        }
+        const previousStars = {};
+        (window.originalRepoCards || []).forEach(card => {
+            previousStars[card.dataset.id] = card.dataset.stars;
+        });
+        freshCards.forEach(card => {
+            setScoreWidths(card);
+            if (previousStars[card.dataset.id] !== undefined && previousStars[card.dataset.id] !== card.dataset.stars) {
+                card.classList.add('card-updated');
+            }
+        });
+        window.originalRepoCards = freshCards.map(card => card.cloneNode(true));
+
+        // 有过滤条件时按原条件重新过滤
+        if (activeMainFilter !== 'all' || activeModelFilter) {
+            applyRepoFilters();
+            return;
+        }
+        const repoGrid = document.querySelector('.repo-grid');
+        if (repoGrid) {
+            repoGrid.replaceChildren(...freshCards);
+            updateFilterResultCount(freshCards.length);
+        }
+    }
+
+    function updatePaperCards(fresh) {
+        const papersList = document.querySelector('.papers-list');
+        const freshCards = Array.from(fresh.querySelectorAll('.paper-card'));
+        if (!papersList || freshCards.length === 0) {
+            return;
+        }
+        freshCards.forEach(setScoreWidths);
+        papersList.replaceChildren(...freshCards);
+        const activePaperFilter = document.querySelector('#papers .filter-btn.active');
+        if (activePaperFilter) {
+            activePaperFilter.click();
+        }
+    }
+
+    // showToast shows a short notice in the corner, linking to href if given
+    function showToast(message, href) {
+        let container = document.querySelector('.live-toasts');
+        if (!container) {
+            container = document.createElement('div');
+            container.className = 'live-toasts';
+            document.body.appendChild(container);
+        }
+        const toast = document.createElement(href ? 'a' : 'div');
+        toast.className = 'live-toast';
+        toast.textContent = message;
+        if (href) {
+            toast.href = href;
+        }
+        container.appendChild(toast);
+        setTimeout(() => toast.remove(), 8000);
+    }
 });
\ No newline at end of file